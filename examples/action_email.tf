@@ -41,3 +41,15 @@ resource "humio_action" "example_email_body_subject" {
     subject_template = "{alert_name}"
   }
 }
+
+resource "humio_action" "example_email_csv" {
+  repository = "sandbox"
+  name       = "example_email_csv"
+  type       = "EmailAction"
+
+  email {
+    recipients = ["ops@example.com"]
+    attach_csv = true
+    use_proxy  = true
+  }
+}
@@ -8,3 +8,22 @@ resource "humio_action" "example_opsgenie" {
     genie_key = "XXXXXXXXXXXXXXX"
   }
 }
+
+variable "opsgenie_genie_key" {
+  type      = string
+  sensitive = true
+}
+
+# The genie key is sent to LogScale but never stored in state. Bump
+# secret_wo_version to send a new key.
+resource "humio_action" "example_opsgenie_write_only" {
+  repository        = "sandbox"
+  name              = "example_opsgenie_write_only"
+  type              = "OpsGenieAction"
+  secret_wo         = var.opsgenie_genie_key
+  secret_wo_version = 1
+
+  opsgenie {
+    api_url = "https://api.opsgenie.com"
+  }
+}
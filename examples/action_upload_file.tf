@@ -0,0 +1,10 @@
+resource "humio_action" "example_upload_file" {
+  repository = "sandbox"
+  name       = "example_upload_file"
+  type       = "UploadFileAction"
+
+  upload_file {
+    file_name   = "blocklist.csv"
+    update_mode = "Append"
+  }
+}
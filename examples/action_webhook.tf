@@ -31,3 +31,41 @@ resource "humio_action" "example_webhook" {
 TEMPLATE
   }
 }
+
+resource "humio_action" "example_webhook_internal" {
+  repository = "sandbox"
+  name       = "example_webhook_internal"
+  type       = "WebhookAction"
+
+  webhook {
+    method     = "POST"
+    url        = "https://alerts.internal.example.com/humio"
+    ignore_ssl = true
+    use_proxy  = true
+
+    # Instead of ignore_ssl, the CA of the internal endpoint can be trusted:
+    # ca_certificate = file("internal-ca.pem")
+  }
+}
+
+variable "webhook_bearer_token" {
+  type      = string
+  sensitive = true
+}
+
+resource "humio_action" "example_webhook_write_only_headers" {
+  repository        = "sandbox"
+  name              = "example_webhook_write_only_headers"
+  type              = "WebhookAction"
+  secret_wo_version = 1
+  webhook_headers_wo = {
+    Authorization = "Bearer ${var.webhook_bearer_token}"
+  }
+
+  webhook {
+    url = "https://alerts.internal.example.com/humio"
+    headers = {
+      Content-Type = "application/json"
+    }
+  }
+}
@@ -0,0 +1,30 @@
+resource "humio_aggregate_alert" "example_error_count" {
+  repository = humio_action.example_email.repository
+  name       = "example_error_count"
+
+  actions = [humio_action.example_email.action_id]
+
+  query                   = "loglevel=ERROR | count() | _count > 100"
+  search_interval_seconds = 3600
+  throttle_time_seconds   = 3600
+  enabled                 = true
+}
+
+resource "humio_aggregate_alert" "example_error_count_per_host" {
+  repository  = humio_action.example_email_body.repository
+  name        = "example_error_count_per_host"
+  description = "Hosts logging more than 10 errors in 15 minutes"
+
+  actions = [humio_action.example_email_body.action_id]
+
+  labels                  = ["terraform", "errors"]
+  query                   = "loglevel=ERROR | groupBy(host) | _count > 10"
+  search_interval_seconds = 900
+  throttle_time_seconds   = 1800
+  throttle_field          = "host"
+  query_timestamp_type    = "IngestTimestamp"
+  trigger_mode            = "ImmediateMode"
+  enabled                 = true
+
+  query_ownership_type = "Organization"
+}
@@ -5,7 +5,7 @@ resource "humio_alert" "example_alert_with_labels" {
   actions = [humio_action.example_email.action_id]
 
   labels               = ["terraform", "ops"]
-  throttle_time_millis = 300000
+  throttle_time        = "5m"
   enabled              = true
   query                = "count()"
   start                = "12h"
@@ -17,7 +17,7 @@ resource "humio_alert" "example_alert_with_throttle_field" {
 
   actions = [humio_action.example_email.action_id]
 
-  throttle_time_millis = 300000
+  throttle_time        = "5m"
   throttle_field       = "serviceName"
   enabled              = true
   query                = "level = ERROR"
@@ -30,7 +30,7 @@ resource "humio_alert" "example_alert_without_labels" {
 
   actions = [humio_action.example_email_body.action_id]
 
-  throttle_time_millis = 300000
+  throttle_time        = "5m"
   enabled              = true
   query                = "count()"
   start                = "12h"
@@ -47,7 +47,7 @@ resource "humio_alert" "example_alert_with_description" {
   ]
 
   labels               = ["terraform", "ops"]
-  throttle_time_millis = 300000
+  throttle_time        = "5m"
   enabled              = true
   query                = "count()"
   start                = "1d"
@@ -57,7 +57,7 @@ resource "humio_alert" "example_alert_with_user_owner" {
   repository = humio_action.example_email_body.repository
   name       = "example_alert_with_user_owner"
 
-  throttle_time_millis = 300000
+  throttle_time        = "5m"
   enabled              = true
   query                = "count()"
   start                = "1d"
@@ -71,7 +71,7 @@ resource "humio_alert" "example_alert_with_organization_owner" {
   repository = humio_action.example_email_body.repository
   name       = "example_alert_with_organization_owner"
 
-  throttle_time_millis = 300000
+  throttle_time        = "5m"
   enabled              = true
   query                = "count()"
   start                = "1d"
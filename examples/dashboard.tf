@@ -0,0 +1,32 @@
+resource "humio_dashboard" "example_dashboard_from_yaml" {
+  repository = "sandbox"
+  name       = "example_dashboard_from_yaml"
+
+  # An exported dashboard template. The name in the template is replaced by the name of the resource.
+  template = file("${path.module}/dashboards/overview.yaml")
+}
+
+resource "humio_dashboard" "example_dashboard_from_json" {
+  repository = "sandbox"
+  name       = "example_dashboard_from_json"
+
+  template = jsonencode({
+    "$schema" = "https://schemas.humio.com/dashboard/v0.17.0"
+    name      = "example_dashboard_from_json"
+    widgets = {
+      events = {
+        x             = 0
+        y             = 0
+        height        = 4
+        width         = 4
+        type          = "query"
+        title         = "Events"
+        queryString   = "count()"
+        start         = "1d"
+        end           = "now"
+        isLive        = false
+        visualization = "single-value"
+      }
+    }
+  })
+}
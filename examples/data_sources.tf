@@ -0,0 +1,38 @@
+data "humio_repository" "example_central" {
+  name = "central"
+}
+
+data "humio_action" "example_oncall_email" {
+  repository = data.humio_repository.example_central.name
+  name       = "oncall-email"
+}
+
+data "humio_actions" "example_oncall" {
+  repository = data.humio_repository.example_central.name
+  name_regex = "^oncall-"
+}
+
+resource "humio_alert" "example_alert_with_central_actions" {
+  repository = data.humio_repository.example_central.name
+  name       = "example_alert_with_central_actions"
+
+  actions = data.humio_actions.example_oncall.actions[*].action_id
+
+  throttle_time = "5m"
+  start         = "5m"
+  query         = "loglevel=ERROR"
+}
+
+data "humio_view" "example_all" {
+  name = "all"
+}
+
+data "humio_parser" "example_json" {
+  repository = data.humio_repository.example_central.name
+  name       = "json"
+}
+
+data "humio_ingest_token" "example_shipper" {
+  repository = data.humio_repository.example_central.name
+  name       = "shipper"
+}
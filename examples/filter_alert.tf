@@ -0,0 +1,25 @@
+resource "humio_filter_alert" "example_error_filter" {
+  repository = humio_action.example_email.repository
+  name       = "example_error_filter"
+
+  actions = [humio_action.example_email.action_id]
+
+  query   = "loglevel=ERROR"
+  enabled = true
+}
+
+resource "humio_filter_alert" "example_error_filter_per_host" {
+  repository  = humio_action.example_email_body.repository
+  name        = "example_error_filter_per_host"
+  description = "Errors, at most once an hour per host"
+
+  actions = [humio_action.example_email_body.action_id]
+
+  labels                = ["terraform", "errors"]
+  query                 = "loglevel=ERROR"
+  throttle_time_seconds = 3600
+  throttle_field        = "host"
+  enabled               = true
+
+  query_ownership_type = "Organization"
+}
@@ -0,0 +1,8 @@
+resource "humio_group" "example_group_minimal_fields_set" {
+  display_name = "example_group_minimal_fields_set"
+}
+
+resource "humio_group" "example_group_all_fields_set" {
+  display_name = "example_group_all_fields_set"
+  lookup_name  = "cn=developers,ou=groups,dc=example,dc=org"
+}
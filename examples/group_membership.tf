@@ -0,0 +1,4 @@
+resource "humio_group_membership" "example_group_membership" {
+  group_id = humio_group.example_group_all_fields_set.group_id
+  user_id  = humio_user.example_user_all_fields_set.user_id
+}
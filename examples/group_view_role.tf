@@ -0,0 +1,5 @@
+resource "humio_group_view_role" "example_group_view_role" {
+  group_id = humio_group.example_group_all_fields_set.group_id
+  view     = humio_repository.example_repo_all_fields_set.name
+  role_id  = humio_role.example_role_searcher.role_id
+}
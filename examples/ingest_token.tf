@@ -13,6 +13,20 @@ resource "humio_ingest_token" "example_ingest_token_with_accesslog_parser" {
   ]
 }
 
+resource "humio_ingest_token" "example_ingest_token_rotated" {
+  repository = "sandbox"
+  name       = "example_ingest_token_rotated"
+
+  # Change any value in this map to rotate the token on the next apply. To rotate on a schedule, use a value that
+  # changes periodically, e.g. the id of a time_rotating resource from the hashicorp/time provider.
+  rotate_when = {
+    rotated_at = "2024-01-01"
+  }
+
+  # Keep the previous token working for 10 minutes after a rotation, while shippers pick up the new token.
+  rotation_grace_period_seconds = 600
+}
+
 output "ingest_token_without_parser" {
   value     = humio_ingest_token.example_ingest_token_without_parser.token
   sensitive = true
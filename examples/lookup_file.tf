@@ -0,0 +1,15 @@
+resource "humio_lookup_file" "example_lookup_file_from_source" {
+  repository = "sandbox"
+  name       = "asset_inventory.csv"
+  source     = "${path.module}/lookup_files/asset_inventory.csv"
+}
+
+resource "humio_lookup_file" "example_lookup_file_inline" {
+  repository = "sandbox"
+  name       = "ip_allowlist.csv"
+  content    = <<-EOT
+    ip,description
+    10.0.0.1,bastion
+    10.0.0.2,vpn
+  EOT
+}
@@ -0,0 +1,9 @@
+resource "humio_organization_token" "example_organization_token" {
+  name        = "example_organization_token"
+  permissions = ["CreateRepository", "ManageUsers"]
+  expire_at   = "2030-01-01T00:00:00Z"
+
+  rotate_when = {
+    rotated_at = "2024-01-01"
+  }
+}
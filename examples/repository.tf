@@ -6,7 +6,11 @@ resource "humio_repository" "example_repo_all_fields_set" {
   name        = "example_repo_all_fields_set"
   description = "This is an example"
 
+  allow_data_deletion = true
+
   retention {
-    time_in_days = 30
+    time_in_days       = 30
+    ingest_size_in_gb  = 10
+    storage_size_in_gb = 5
   }
 }
@@ -0,0 +1,13 @@
+resource "humio_role" "example_role_searcher" {
+  name             = "example_role_searcher"
+  view_permissions = ["ReadAccess"]
+}
+
+resource "humio_role" "example_role_all_fields_set" {
+  name        = "example_role_all_fields_set"
+  description = "Search, and manage alerts and dashboards"
+
+  view_permissions         = ["ReadAccess", "ChangeTriggers", "ChangeDashboards"]
+  organization_permissions = ["CreateRepository"]
+  system_permissions       = ["ReadHealthCheck"]
+}
@@ -0,0 +1,22 @@
+resource "humio_saved_query" "example_saved_query_minimal_fields_set" {
+  repository = "sandbox"
+  name       = "example_saved_query_minimal_fields_set"
+  query      = "count()"
+}
+
+resource "humio_saved_query" "example_saved_query_all_fields_set" {
+  repository  = "sandbox"
+  name        = "example_saved_query_all_fields_set"
+  query       = "loglevel=ERROR | groupBy(host)"
+  start       = "7d"
+  end         = "now"
+  is_live     = false
+  widget_type = "bar-chart"
+  options     = jsonencode({ "series" = {} })
+  labels      = ["ops", "errors"]
+}
+
+# Refer to saved queries by ID, e.g. from dashboard templates, so renaming a query does not break the reference.
+output "errors_by_host_saved_query_id" {
+  value = humio_saved_query.example_saved_query_all_fields_set.saved_query_id
+}
@@ -0,0 +1,30 @@
+resource "humio_scheduled_search" "example_hourly_error_count" {
+  repository = humio_action.example_email.repository
+  name       = "example_hourly_error_count"
+
+  actions = [humio_action.example_email.action_id]
+
+  query    = "loglevel=ERROR | count()"
+  start    = "1h"
+  schedule = "0 * * * *"
+  enabled  = true
+}
+
+resource "humio_scheduled_search" "example_weekly_report" {
+  repository  = humio_action.example_email_body.repository
+  name        = "example_weekly_report"
+  description = "Weekly summary of errors per service"
+
+  actions = [humio_action.example_email_body.action_id]
+
+  labels         = ["terraform", "reports"]
+  query          = "loglevel=ERROR | groupBy(serviceName)"
+  start          = "7d"
+  end            = "now"
+  schedule       = "0 8 * * 1"
+  time_zone      = "UTC+01:00"
+  backfill_limit = 1
+  enabled        = true
+
+  query_ownership_type = "Organization"
+}
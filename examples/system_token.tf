@@ -0,0 +1,4 @@
+resource "humio_system_token" "example_system_token" {
+  name        = "example_system_token"
+  permissions = ["ReadHealthCheck"]
+}
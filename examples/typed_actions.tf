@@ -0,0 +1,55 @@
+resource "humio_email_action" "example_email" {
+  repository = "sandbox"
+  name       = "example_typed_email"
+  recipients = ["ops@example.com"]
+  attach_csv = true
+}
+
+resource "humio_webhook_action" "example_webhook" {
+  repository = "sandbox"
+  name       = "example_typed_webhook"
+  url        = "https://alerts.internal.example.com/humio"
+
+  # Fail the apply if LogScale cannot deliver a test notification.
+  verify_on_apply = true
+
+  headers = {
+    Content-Type = "application/json"
+  }
+}
+
+# Sends a test notification through the action when created, and again only
+# when the action is recreated or one of the triggers changes. Refreshing and
+# planning do not send anything.
+resource "humio_action_test_result" "example_webhook" {
+  repository = humio_webhook_action.example_webhook.repository
+  action_id  = humio_webhook_action.example_webhook.action_id
+
+  triggers = {
+    url = humio_webhook_action.example_webhook.url
+  }
+}
+
+check "example_webhook_delivers" {
+  assert {
+    condition     = humio_action_test_result.example_webhook.success
+    error_message = "Test notification failed: ${humio_action_test_result.example_webhook.message}"
+  }
+}
+
+resource "humio_opsgenie_action" "example_opsgenie" {
+  repository        = "sandbox"
+  name              = "example_typed_opsgenie"
+  genie_key_wo      = var.opsgenie_genie_key
+  secret_wo_version = 1
+}
+
+# Existing humio_action resources can be moved to the typed resources without
+# recreating the action (requires Terraform 1.8 or later). Replace the
+# humio_action resource with the typed resource, keeping the settings of its
+# block as top-level attributes, and add a moved block:
+#
+# moved {
+#   from = humio_action.example_slack
+#   to   = humio_slack_action.example_slack
+# }
@@ -0,0 +1,12 @@
+resource "humio_user" "example_user_minimal_fields_set" {
+  username = "jane@example.org"
+}
+
+resource "humio_user" "example_user_all_fields_set" {
+  username     = "john@example.org"
+  full_name    = "John Doe"
+  email        = "john@example.org"
+  company      = "Example"
+  country_code = "DK"
+  is_root      = false
+}
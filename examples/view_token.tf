@@ -0,0 +1,18 @@
+resource "humio_view_token" "example_view_token_minimal_fields_set" {
+  name        = "example_view_token_minimal_fields_set"
+  views       = ["example_view"]
+  permissions = ["ReadAccess"]
+}
+
+resource "humio_view_token" "example_view_token_all_fields_set" {
+  name         = "example_view_token_all_fields_set"
+  views        = ["example_view", "example_repo"]
+  permissions  = ["ReadAccess", "ChangeTriggers"]
+  expire_at    = "2030-01-01T00:00:00Z"
+  ip_filter_id = "example_ip_filter_id"
+
+  # Change any value in this map to rotate the token on the next apply.
+  rotate_when = {
+    rotated_at = "2024-01-01"
+  }
+}
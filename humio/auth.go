@@ -0,0 +1,261 @@
+// Copyright © 2020 Humio Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package humio
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// authenticationAttributes are the provider attributes that each configure a way of authenticating with LogScale.
+// Exactly one of them must be used.
+var authenticationAttributes = []string{"api_token", "api_token_file", "api_token_command", "oauth2"}
+
+// apiTokenCommandTimeout is how long api_token_command may run before it is killed.
+const apiTokenCommandTimeout = time.Minute
+
+// authentication is how requests to LogScale are authenticated: with a static token, or with tokens from an OAuth2
+// token endpoint, which are renewed as they expire.
+type authentication struct {
+	token  string
+	oauth2 *oauth2TokenSource
+}
+
+// authenticationFromResourceData returns the authentication configured for the provider. OAuth2 tokens are requested
+// with tokenClient.
+func authenticationFromResourceData(ctx context.Context, r *schema.ResourceData, tokenClient *http.Client) (authentication, error) {
+	method, err := authenticationMethod(r)
+	if err != nil {
+		return authentication{}, err
+	}
+
+	switch method {
+	case "api_token":
+		return authentication{token: r.Get("api_token").(string)}, nil
+	case "api_token_file":
+		token, err := apiTokenFromFile(r.Get("api_token_file").(string))
+		return authentication{token: token}, err
+	case "api_token_command":
+		token, err := apiTokenFromCommand(ctx, convertInterfaceListToStringSlice(r.Get("api_token_command").([]interface{})))
+		return authentication{token: token}, err
+	}
+
+	oauth2 := r.Get("oauth2").([]interface{})[0].(tfMap)
+	source := &oauth2TokenSource{
+		tokenURL:     oauth2["token_url"].(string),
+		clientID:     oauth2["client_id"].(string),
+		clientSecret: oauth2["client_secret"].(string),
+		scopes:       convertInterfaceListToStringSlice(oauth2["scopes"].([]interface{})),
+		client:       tokenClient,
+	}
+	// Get the first token right away, so misconfigured credentials are reported when configuring the provider.
+	if _, err := source.Token(); err != nil {
+		return authentication{}, err
+	}
+	return authentication{oauth2: source}, nil
+}
+
+// authenticationMethod returns the attribute of the authentication method to use.
+func authenticationMethod(r *schema.ResourceData) (string, error) {
+	method, err := chooseAttribute(r, authenticationAttributes...)
+	if err != nil {
+		return "", err
+	}
+	if method == "" {
+		return "", fmt.Errorf("no credentials for LogScale were given, set one of %s", strings.Join(authenticationAttributes, ", "))
+	}
+	return method, nil
+}
+
+// chooseAttribute returns which of the mutually exclusive attributes is set, or "" if none is. Attributes set in the
+// provider block take precedence over attributes set through environment variables, so e.g. a HUMIO_API_TOKEN in the
+// environment does not conflict with an oauth2 block.
+func chooseAttribute(r *schema.ResourceData, attributes ...string) (string, error) {
+	config := r.GetRawConfig()
+	var configured, set []string
+	for _, attribute := range attributes {
+		if attributeConfigured(config, attribute) {
+			configured = append(configured, attribute)
+		}
+		if _, ok := r.GetOk(attribute); ok {
+			set = append(set, attribute)
+		}
+	}
+
+	chosen := configured
+	if len(chosen) == 0 {
+		chosen = set
+	}
+	switch len(chosen) {
+	case 0:
+		return "", nil
+	case 1:
+		return chosen[0], nil
+	}
+	return "", fmt.Errorf("only one of %s may be set, but %s were set", strings.Join(attributes, ", "), strings.Join(chosen, " and "))
+}
+
+// attributeConfigured returns whether the attribute is set in the configuration itself, rather than through a default.
+func attributeConfigured(config cty.Value, attribute string) bool {
+	if config.IsNull() || !config.IsKnown() {
+		return false
+	}
+	v := config.GetAttr(attribute)
+	if v.IsNull() {
+		return false
+	}
+	if v.IsKnown() && (v.Type().IsListType() || v.Type().IsSetType()) {
+		return v.LengthInt() > 0
+	}
+	return true
+}
+
+// apiTokenFromFile reads the API token from the file, ignoring surrounding whitespace such as a trailing newline.
+func apiTokenFromFile(path string) (string, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("could not read api_token_file: %w", err)
+	}
+	token := strings.TrimSpace(string(content))
+	if token == "" {
+		return "", fmt.Errorf("api_token_file %s is empty", path)
+	}
+	return token, nil
+}
+
+// apiTokenFromCommand runs the credential helper and returns the API token it prints.
+func apiTokenFromCommand(ctx context.Context, command []string) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, apiTokenCommandTimeout)
+	defer cancel()
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, command[0], command[1:]...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("api_token_command %s failed: %w: %s", command[0], err, strings.TrimSpace(stderr.String()))
+	}
+	token := strings.TrimSpace(stdout.String())
+	if token == "" {
+		return "", fmt.Errorf("api_token_command %s printed no token", command[0])
+	}
+	return token, nil
+}
+
+// oauth2ExpiryDelta is how long before they expire tokens are renewed, so they do not expire while in flight.
+const oauth2ExpiryDelta = time.Minute
+
+// oauth2TokenSource gets tokens from an OAuth2 token endpoint with the client credentials grant, authenticating with
+// HTTP basic authentication. The token is cached until shortly before it expires.
+type oauth2TokenSource struct {
+	tokenURL     string
+	clientID     string
+	clientSecret string
+	scopes       []string
+	client       *http.Client
+
+	mu     sync.Mutex
+	token  string
+	expiry time.Time
+}
+
+func (s *oauth2TokenSource) Token() (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.token != "" && (s.expiry.IsZero() || time.Now().Add(oauth2ExpiryDelta).Before(s.expiry)) {
+		return s.token, nil
+	}
+
+	form := url.Values{"grant_type": {"client_credentials"}}
+	if len(s.scopes) > 0 {
+		form.Set("scope", strings.Join(s.scopes, " "))
+	}
+	req, err := http.NewRequest(http.MethodPost, s.tokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", fmt.Errorf("could not create OAuth2 token request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	req.SetBasicAuth(url.QueryEscape(s.clientID), url.QueryEscape(s.clientSecret))
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("could not get OAuth2 token from %s: %w", s.tokenURL, err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return "", fmt.Errorf("could not read OAuth2 token response from %s: %w", s.tokenURL, err)
+	}
+
+	var token struct {
+		AccessToken      string `json:"access_token"`
+		ExpiresIn        int64  `json:"expires_in"`
+		Error            string `json:"error"`
+		ErrorDescription string `json:"error_description"`
+	}
+	if err := json.Unmarshal(body, &token); err != nil && resp.StatusCode == http.StatusOK {
+		return "", fmt.Errorf("could not parse OAuth2 token response from %s: %w", s.tokenURL, err)
+	}
+	if resp.StatusCode != http.StatusOK || token.Error != "" {
+		if token.Error != "" {
+			return "", fmt.Errorf("could not get OAuth2 token from %s: %s: %s", s.tokenURL, token.Error, token.ErrorDescription)
+		}
+		return "", fmt.Errorf("could not get OAuth2 token from %s: server responded with %s", s.tokenURL, resp.Status)
+	}
+	if token.AccessToken == "" {
+		return "", fmt.Errorf("OAuth2 token response from %s has no access_token", s.tokenURL)
+	}
+
+	s.token = token.AccessToken
+	s.expiry = time.Time{}
+	if token.ExpiresIn > 0 {
+		s.expiry = time.Now().Add(time.Duration(token.ExpiresIn) * time.Second)
+	}
+	return s.token, nil
+}
+
+// oauth2Transport authenticates requests with a token from the token source.
+type oauth2Transport struct {
+	base   http.RoundTripper
+	source *oauth2TokenSource
+}
+
+func (t *oauth2Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	token, err := t.source.Token()
+	if err != nil {
+		if req.Body != nil {
+			_ = req.Body.Close()
+		}
+		return nil, err
+	}
+	r := req.Clone(req.Context())
+	r.Header.Set("Authorization", "Bearer "+token)
+	return t.base.RoundTrip(r)
+}
@@ -0,0 +1,314 @@
+// Copyright © 2020 Humio Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package humio
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"regexp"
+	"sync/atomic"
+	"testing"
+
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+// configureTestProvider configures the provider with the given attributes, leaving the others unset.
+func configureTestProvider(t *testing.T, attributes map[string]cty.Value) (*providerMeta, diag.Diagnostics) {
+	t.Helper()
+	p := Provider()
+	block := schema.InternalMap(p.Schema).CoreConfigSchema()
+
+	values := map[string]cty.Value{}
+	for name, attributeType := range block.ImpliedType().AttributeTypes() {
+		switch v, ok := attributes[name]; {
+		case ok:
+			values[name] = v
+		case attributeType.IsListType() && attributeType.ElementType().IsObjectType():
+			values[name] = cty.ListValEmpty(attributeType.ElementType())
+		default:
+			values[name] = cty.NullVal(attributeType)
+		}
+	}
+
+	config := terraform.NewResourceConfigShimmed(cty.ObjectVal(values), block)
+	// Terraform passes the configuration itself as well, which tells set attributes from defaults.
+	config.CtyValue = cty.ObjectVal(values)
+	diags := p.Configure(context.Background(), config)
+	if diags.HasError() {
+		return nil, diags
+	}
+	return p.Meta().(*providerMeta), diags
+}
+
+func TestProviderAuthentication(t *testing.T) {
+	tokenFile := writeTestFile(t, "token", "file-token\n")
+	emptyFile := writeTestFile(t, "token", " \n")
+
+	tests := []struct {
+		name       string
+		env        map[string]string
+		attributes map[string]cty.Value
+		wantToken  string
+		wantErr    string
+	}{
+		{
+			name:       "api_token",
+			attributes: map[string]cty.Value{"api_token": cty.StringVal("static-token")},
+			wantToken:  "static-token",
+		},
+		{
+			name:      "api_token from environment",
+			env:       map[string]string{"HUMIO_API_TOKEN": "env-token"},
+			wantToken: "env-token",
+		},
+		{
+			name:       "api_token_file",
+			attributes: map[string]cty.Value{"api_token_file": cty.StringVal(tokenFile)},
+			wantToken:  "file-token",
+		},
+		{
+			name:      "api_token_file from environment",
+			env:       map[string]string{"HUMIO_API_TOKEN_FILE": tokenFile},
+			wantToken: "file-token",
+		},
+		{
+			name:       "missing api_token_file",
+			attributes: map[string]cty.Value{"api_token_file": cty.StringVal(filepath.Join(t.TempDir(), "missing"))},
+			wantErr:    "could not read api_token_file",
+		},
+		{
+			name:       "empty api_token_file",
+			attributes: map[string]cty.Value{"api_token_file": cty.StringVal(emptyFile)},
+			wantErr:    "api_token_file .* is empty",
+		},
+		{
+			name:       "api_token_command",
+			attributes: map[string]cty.Value{"api_token_command": cty.ListVal([]cty.Value{cty.StringVal("echo"), cty.StringVal("command-token")})},
+			wantToken:  "command-token",
+		},
+		{
+			name: "failing api_token_command",
+			attributes: map[string]cty.Value{"api_token_command": cty.ListVal([]cty.Value{
+				cty.StringVal("sh"), cty.StringVal("-c"), cty.StringVal("echo access denied >&2; exit 3"),
+			})},
+			wantErr: "api_token_command sh failed: exit status 3: access denied",
+		},
+		{
+			name:       "api_token_command printing nothing",
+			attributes: map[string]cty.Value{"api_token_command": cty.ListVal([]cty.Value{cty.StringVal("true")})},
+			wantErr:    "api_token_command true printed no token",
+		},
+		{
+			name:       "configuration takes precedence over environment",
+			env:        map[string]string{"HUMIO_API_TOKEN": "env-token"},
+			attributes: map[string]cty.Value{"api_token_file": cty.StringVal(tokenFile)},
+			wantToken:  "file-token",
+		},
+		{
+			name: "more than one configured",
+			attributes: map[string]cty.Value{
+				"api_token":      cty.StringVal("static-token"),
+				"api_token_file": cty.StringVal(tokenFile),
+			},
+			wantErr: "only one of api_token, api_token_file, api_token_command, oauth2 may be set, but api_token and api_token_file were set",
+		},
+		{
+			name:    "more than one in environment",
+			env:     map[string]string{"HUMIO_API_TOKEN": "env-token", "HUMIO_API_TOKEN_FILE": tokenFile},
+			wantErr: "but api_token and api_token_file were set",
+		},
+		{
+			name:    "none",
+			wantErr: "no credentials for LogScale were given",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("HUMIO_API_TOKEN", "")
+			t.Setenv("HUMIO_API_TOKEN_FILE", "")
+			for k, v := range tt.env {
+				t.Setenv(k, v)
+			}
+
+			meta, diags := configureTestProvider(t, tt.attributes)
+			if tt.wantErr != "" {
+				if !diags.HasError() || !regexp.MustCompile(tt.wantErr).MatchString(diags[0].Detail) {
+					t.Fatalf("got diagnostics %v, want error matching %q", diags, tt.wantErr)
+				}
+				return
+			}
+			if diags.HasError() {
+				t.Fatalf("unexpected diagnostics %v", diags)
+			}
+			if got := meta.client.Token(); got != tt.wantToken {
+				t.Errorf("token = %q, want %q", got, tt.wantToken)
+			}
+		})
+	}
+}
+
+// newTokenEndpoint returns a fake OAuth2 token endpoint accepting the client credentials terraform and s3cret, and
+// issuing numbered tokens that expire after the given number of seconds. The endpoint serves TLS with the certificate,
+// or plain HTTP if it is nil.
+func newTokenEndpoint(t *testing.T, expiresIn int, certificate *testCertificate) (*httptest.Server, *atomic.Int32) {
+	var requests atomic.Int32
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := requests.Add(1)
+		w.Header().Set("Content-Type", "application/json")
+		clientID, clientSecret, ok := r.BasicAuth()
+		if !ok || clientID != "terraform" || clientSecret != "s3cret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = io.WriteString(w, `{"error":"invalid_client","error_description":"Bad client credentials"}`)
+			return
+		}
+		if r.Method != http.MethodPost || r.PostFormValue("grant_type") != "client_credentials" || r.PostFormValue("scope") != "api logscale" {
+			t.Errorf("unexpected token request %s %v", r.Method, r.PostForm)
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		_, _ = fmt.Fprintf(w, `{"access_token":"oauth-token-%d","token_type":"Bearer","expires_in":%d}`, n, expiresIn)
+	}))
+	if certificate == nil {
+		server.Start()
+	} else {
+		server.TLS = &tls.Config{Certificates: []tls.Certificate{{
+			Certificate: [][]byte{certificate.certificate.Raw},
+			PrivateKey:  certificate.key,
+		}}}
+		server.StartTLS()
+	}
+	t.Cleanup(server.Close)
+	return server, &requests
+}
+
+func oauth2TestBlock(tokenURL, clientSecret string) cty.Value {
+	return cty.ListVal([]cty.Value{cty.ObjectVal(map[string]cty.Value{
+		"token_url":     cty.StringVal(tokenURL),
+		"client_id":     cty.StringVal("terraform"),
+		"client_secret": cty.StringVal(clientSecret),
+		"scopes":        cty.ListVal([]cty.Value{cty.StringVal("api"), cty.StringVal("logscale")}),
+	})})
+}
+
+func TestProviderOAuth2(t *testing.T) {
+	t.Setenv("HUMIO_API_TOKEN", "env-token")
+	ca := newTestCertificate(t, "CA", nil)
+
+	tests := []struct {
+		name string
+		// certificate is the certificate of the token endpoint, which serves plain HTTP if it is nil.
+		certificate *testCertificate
+		attributes  map[string]cty.Value
+	}{
+		{
+			name: "plain HTTP token endpoint",
+		},
+		{
+			name:        "TLS token endpoint trusted through ca_certificate_file",
+			certificate: newTestCertificate(t, "token endpoint", ca),
+			attributes: map[string]cty.Value{
+				"ca_certificate_file": cty.StringVal(writeTestFile(t, "ca.pem", ca.certPEM)),
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tokenEndpoint, tokenRequests := newTokenEndpoint(t, 3600, tt.certificate)
+
+			var authorization []string
+			logscale := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				authorization = append(authorization, r.Header.Get("Authorization"))
+				_, _ = io.WriteString(w, `{"data":{"viewer":{"username":"terraform"}}}`)
+			}))
+			defer logscale.Close()
+
+			attributes := map[string]cty.Value{
+				"addr":   cty.StringVal(logscale.URL),
+				"oauth2": oauth2TestBlock(tokenEndpoint.URL, "s3cret"),
+			}
+			for name, v := range tt.attributes {
+				attributes[name] = v
+			}
+			meta, diags := configureTestProvider(t, attributes)
+			if diags.HasError() {
+				t.Fatalf("unexpected diagnostics %v", diags)
+			}
+			if got := meta.client.Token(); got != "" {
+				t.Errorf("static token = %q, want none", got)
+			}
+
+			for i := 0; i < 2; i++ {
+				if err := meta.client.Query(&testViewerQuery{}, nil); err != nil {
+					t.Fatal(err)
+				}
+			}
+			for _, got := range authorization {
+				if got != "Bearer oauth-token-1" {
+					t.Errorf("Authorization = %q, want %q", got, "Bearer oauth-token-1")
+				}
+			}
+			if got := tokenRequests.Load(); got != 1 {
+				t.Errorf("got %d token requests, want 1", got)
+			}
+		})
+	}
+}
+
+func TestProviderOAuth2InvalidClient(t *testing.T) {
+	t.Setenv("HUMIO_API_TOKEN", "")
+	tokenEndpoint, _ := newTokenEndpoint(t, 3600, nil)
+
+	_, diags := configureTestProvider(t, map[string]cty.Value{
+		"oauth2": oauth2TestBlock(tokenEndpoint.URL, "wrong"),
+	})
+	want := regexp.MustCompile("could not get OAuth2 token from .*: invalid_client: Bad client credentials")
+	if !diags.HasError() || !want.MatchString(diags[0].Detail) {
+		t.Errorf("got diagnostics %v, want error matching %q", diags, want)
+	}
+}
+
+func TestOAuth2TokenSourceRenewsExpiringTokens(t *testing.T) {
+	// Tokens expiring within oauth2ExpiryDelta are renewed on every use.
+	tokenEndpoint, tokenRequests := newTokenEndpoint(t, 30, nil)
+	source := &oauth2TokenSource{
+		tokenURL:     tokenEndpoint.URL,
+		clientID:     "terraform",
+		clientSecret: "s3cret",
+		scopes:       []string{"api", "logscale"},
+		client:       http.DefaultClient,
+	}
+
+	for i := 1; i <= 2; i++ {
+		token, err := source.Token()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := fmt.Sprintf("oauth-token-%d", i); token != want {
+			t.Errorf("token = %q, want %q", token, want)
+		}
+	}
+	if got := tokenRequests.Load(); got != 2 {
+		t.Errorf("got %d token requests, want 2", got)
+	}
+}
@@ -0,0 +1,62 @@
+// Copyright © 2020 Humio Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package humio
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+)
+
+// appendCertificates adds every certificate in the PEM bundle to the pool.
+func appendCertificates(pool *x509.CertPool, source string, bundle []byte) error {
+	found := 0
+	for rest := bundle; ; {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			break
+		}
+		if block.Type != "CERTIFICATE" {
+			return fmt.Errorf("%s contains a PEM block of type %s, but only certificates are expected", source, block.Type)
+		}
+		certificate, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return fmt.Errorf("%s contains an invalid certificate: %w", source, err)
+		}
+		pool.AddCert(certificate)
+		found++
+	}
+	if found == 0 {
+		return fmt.Errorf("%s specified but no pem was found", source)
+	}
+	return nil
+}
+
+// validateCertificatePEM validates that the value holds one or more PEM encoded certificates.
+func validateCertificatePEM(v interface{}, path cty.Path) diag.Diagnostics {
+	if err := appendCertificates(x509.NewCertPool(), "certificate", []byte(v.(string))); err != nil {
+		return diag.Diagnostics{{
+			Severity:      diag.Error,
+			Summary:       "Invalid certificate",
+			Detail:        err.Error(),
+			AttributePath: path,
+		}}
+	}
+	return nil
+}
@@ -0,0 +1,457 @@
+// Copyright © 2020 Humio Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package humio
+
+import (
+	"fmt"
+
+	graphql "github.com/cli/shurcooL-graphql"
+
+	humio "github.com/humio/cli/api"
+)
+
+// ActionTypeUploadFile is the type of actions that write the results of alerts into a lookup file.
+const ActionTypeUploadFile = "UploadFileAction"
+
+// UploadFileAction holds the settings of upload file actions, which github.com/humio/cli does not support.
+type UploadFileAction struct {
+	FileName   string
+	UpdateMode string
+}
+
+// UpdateMode is the GraphQL enum deciding whether upload file actions replace or append to the file.
+type UpdateMode string
+
+// Action extends humio.Action with the action types and settings github.com/humio/cli does not support.
+type Action struct {
+	humio.Action
+	UploadFileAction UploadFileAction
+	// EmailAttachCSV is whether email actions attach the results of the alert as a CSV file.
+	EmailAttachCSV bool
+	// WebhookCACertificate is the PEM encoded CA certificate webhook actions trust when calling the webhook.
+	WebhookCACertificate string
+}
+
+type uploadFileActionData struct {
+	ID         string `graphql:"id"`
+	Name       string `graphql:"name"`
+	FileName   string `graphql:"fileName"`
+	UpdateMode string `graphql:"updateMode"`
+}
+
+type emailActionData struct {
+	ID              string   `graphql:"id"`
+	Name            string   `graphql:"name"`
+	Recipients      []string `graphql:"recipients"`
+	SubjectTemplate *string  `graphql:"subjectTemplate"`
+	BodyTemplate    *string  `graphql:"bodyTemplate"`
+	UseProxy        bool     `graphql:"useProxy"`
+	AttachCSV       bool     `graphql:"attachCsv"`
+}
+
+type webhookActionData struct {
+	ID            string                       `graphql:"id"`
+	Name          string                       `graphql:"name"`
+	URL           string                       `graphql:"url"`
+	Method        string                       `graphql:"method"`
+	Headers       []humio.HttpHeaderEntryInput `graphql:"headers"`
+	BodyTemplate  string                       `graphql:"bodyTemplate"`
+	IgnoreSSL     bool                         `graphql:"ignoreSSL"`
+	UseProxy      bool                         `graphql:"useProxy"`
+	CACertificate *string                      `graphql:"caCertificate"`
+}
+
+// actionSettings holds the settings of an action that are not returned by github.com/humio/cli.
+type actionSettings struct {
+	UploadFileAction     UploadFileAction
+	EmailAttachCSV       bool
+	WebhookCACertificate string
+}
+
+type actions struct {
+	client *humio.Client
+	lists  *listCache
+}
+
+func newActions(client *humio.Client) *actions {
+	return &actions{client: client}
+}
+
+// List lists the actions in the view, reusing the list of an earlier call if it is cached.
+func (a *actions) List(viewName string) ([]Action, error) {
+	return cachedList(a.lists, "actions", viewName, a.list)
+}
+
+func (a *actions) list(viewName string) ([]Action, error) {
+	list, err := a.client.Actions().List(viewName)
+	if err != nil {
+		return nil, err
+	}
+
+	var settings map[string]actionSettings
+	for _, action := range list {
+		if !handledByCLI(action.Type) {
+			settings, err = a.listActionSettings(viewName)
+			if err != nil {
+				return nil, err
+			}
+			break
+		}
+	}
+
+	actions := make([]Action, len(list))
+	for i, action := range list {
+		actions[i] = Action{
+			Action:               action,
+			UploadFileAction:     settings[action.ID].UploadFileAction,
+			EmailAttachCSV:       settings[action.ID].EmailAttachCSV,
+			WebhookCACertificate: settings[action.ID].WebhookCACertificate,
+		}
+	}
+	return actions, nil
+}
+
+// listActionSettings returns the settings not returned by github.com/humio/cli of the actions in the view by action ID.
+func (a *actions) listActionSettings(viewName string) (map[string]actionSettings, error) {
+	var query struct {
+		SearchDomain struct {
+			Actions []struct {
+				ID               string               `graphql:"id"`
+				UploadFileAction uploadFileActionData `graphql:"... on UploadFileAction"`
+				EmailAction      struct {
+					AttachCSV bool `graphql:"attachCsv"`
+				} `graphql:"... on EmailAction"`
+				WebhookAction struct {
+					CACertificate *string `graphql:"caCertificate"`
+				} `graphql:"... on WebhookAction"`
+			} `graphql:"actions"`
+		} `graphql:"searchDomain(name: $viewName)"`
+	}
+
+	variables := map[string]interface{}{
+		"viewName": graphql.String(viewName),
+	}
+
+	err := a.client.Query(&query, variables)
+	if err != nil {
+		return nil, err
+	}
+
+	settings := make(map[string]actionSettings)
+	for _, action := range query.SearchDomain.Actions {
+		var caCertificate string
+		if action.WebhookAction.CACertificate != nil {
+			caCertificate = *action.WebhookAction.CACertificate
+		}
+		settings[action.ID] = actionSettings{
+			UploadFileAction: UploadFileAction{
+				FileName:   action.UploadFileAction.FileName,
+				UpdateMode: action.UploadFileAction.UpdateMode,
+			},
+			EmailAttachCSV:       action.EmailAction.AttachCSV,
+			WebhookCACertificate: caCertificate,
+		}
+	}
+	return settings, nil
+}
+
+// handledByCLI reports whether github.com/humio/cli supports all the settings of the action type, so actions of the
+// type can be created and updated through it.
+func handledByCLI(actionType string) bool {
+	switch actionType {
+	case ActionTypeUploadFile, humio.ActionTypeEmail, humio.ActionTypeWebhook:
+		return false
+	}
+	return true
+}
+
+func (a *actions) Get(viewName, actionName string) (*Action, error) {
+	actions, err := a.List(viewName)
+	if err != nil {
+		return nil, fmt.Errorf("unable to list actions: %w", err)
+	}
+	for _, action := range actions {
+		if action.Name == actionName {
+			return &action, nil
+		}
+	}
+
+	return nil, humio.ActionNotFound(actionName)
+}
+
+func (a *actions) Add(viewName string, action *Action) (*Action, error) {
+	defer a.lists.invalidate("actions", viewName)
+
+	if action == nil {
+		return nil, fmt.Errorf("action must not be nil")
+	}
+
+	if handledByCLI(action.Type) {
+		created, err := a.client.Actions().Add(viewName, &action.Action)
+		if err != nil {
+			return nil, err
+		}
+		return &Action{Action: *created}, nil
+	}
+
+	if action.Type == humio.ActionTypeEmail {
+		var mutation struct {
+			EmailAction emailActionData `graphql:"createEmailAction(input: { viewName: $viewName, name: $actionName, recipients: $recipients, subjectTemplate: $subjectTemplate, bodyTemplate: $bodyTemplate, useProxy: $useProxy, attachCsv: $attachCsv })"`
+		}
+
+		err := a.client.Mutate(&mutation, emailActionVariables(viewName, action))
+		if err != nil {
+			return nil, err
+		}
+
+		return toEmailAction(mutation.EmailAction), nil
+	}
+
+	if action.Type == humio.ActionTypeWebhook {
+		var mutation struct {
+			WebhookAction webhookActionData `graphql:"createWebhookAction(input: { viewName: $viewName, name: $actionName, url: $url, method: $method, headers: $headers, bodyTemplate: $bodyTemplate, ignoreSSL: $ignoreSSL, useProxy: $useProxy, caCertificate: $caCertificate })"`
+		}
+
+		err := a.client.Mutate(&mutation, webhookActionVariables(viewName, action))
+		if err != nil {
+			return nil, err
+		}
+
+		return toWebhookAction(mutation.WebhookAction), nil
+	}
+
+	var mutation struct {
+		UploadFileAction uploadFileActionData `graphql:"createUploadFileAction(input: { viewName: $viewName, name: $actionName, fileName: $fileName, updateMode: $updateMode })"`
+	}
+
+	variables := map[string]interface{}{
+		"viewName":   graphql.String(viewName),
+		"actionName": graphql.String(action.Name),
+		"fileName":   graphql.String(action.UploadFileAction.FileName),
+		"updateMode": UpdateMode(action.UploadFileAction.UpdateMode),
+	}
+
+	err := a.client.Mutate(&mutation, variables)
+	if err != nil {
+		return nil, err
+	}
+
+	return toUploadFileAction(mutation.UploadFileAction), nil
+}
+
+func (a *actions) Update(viewName string, action *Action) (*Action, error) {
+	defer a.lists.invalidate("actions", viewName)
+
+	if action == nil {
+		return nil, fmt.Errorf("action must not be nil")
+	}
+
+	if handledByCLI(action.Type) {
+		updated, err := a.client.Actions().Update(viewName, &action.Action)
+		if err != nil {
+			return nil, err
+		}
+		return &Action{Action: *updated}, nil
+	}
+
+	if action.ID == "" {
+		return nil, fmt.Errorf("action must have non-empty action id")
+	}
+
+	if action.Type == humio.ActionTypeEmail {
+		var mutation struct {
+			EmailAction emailActionData `graphql:"updateEmailAction(input: { id: $id, viewName: $viewName, name: $actionName, recipients: $recipients, subjectTemplate: $subjectTemplate, bodyTemplate: $bodyTemplate, useProxy: $useProxy, attachCsv: $attachCsv })"`
+		}
+
+		variables := emailActionVariables(viewName, action)
+		variables["id"] = graphql.String(action.ID)
+
+		err := a.client.Mutate(&mutation, variables)
+		if err != nil {
+			return nil, err
+		}
+
+		return toEmailAction(mutation.EmailAction), nil
+	}
+
+	if action.Type == humio.ActionTypeWebhook {
+		var mutation struct {
+			WebhookAction webhookActionData `graphql:"updateWebhookAction(input: { id: $id, viewName: $viewName, name: $actionName, url: $url, method: $method, headers: $headers, bodyTemplate: $bodyTemplate, ignoreSSL: $ignoreSSL, useProxy: $useProxy, caCertificate: $caCertificate })"`
+		}
+
+		variables := webhookActionVariables(viewName, action)
+		variables["id"] = graphql.String(action.ID)
+
+		err := a.client.Mutate(&mutation, variables)
+		if err != nil {
+			return nil, err
+		}
+
+		return toWebhookAction(mutation.WebhookAction), nil
+	}
+
+	var mutation struct {
+		UploadFileAction uploadFileActionData `graphql:"updateUploadFileAction(input: { id: $id, viewName: $viewName, name: $actionName, fileName: $fileName, updateMode: $updateMode })"`
+	}
+
+	variables := map[string]interface{}{
+		"id":         graphql.String(action.ID),
+		"viewName":   graphql.String(viewName),
+		"actionName": graphql.String(action.Name),
+		"fileName":   graphql.String(action.UploadFileAction.FileName),
+		"updateMode": UpdateMode(action.UploadFileAction.UpdateMode),
+	}
+
+	err := a.client.Mutate(&mutation, variables)
+	if err != nil {
+		return nil, err
+	}
+
+	return toUploadFileAction(mutation.UploadFileAction), nil
+}
+
+func (a *actions) Delete(viewName, actionName string) error {
+	defer a.lists.invalidate("actions", viewName)
+
+	return a.client.Actions().Delete(viewName, actionName)
+}
+
+func emailActionVariables(viewName string, action *Action) map[string]interface{} {
+	return map[string]interface{}{
+		"viewName":        graphql.String(viewName),
+		"actionName":      graphql.String(action.Name),
+		"recipients":      graphqlStringList(action.EmailAction.Recipients),
+		"subjectTemplate": optStringArg(action.EmailAction.SubjectTemplate),
+		"bodyTemplate":    optStringArg(action.EmailAction.BodyTemplate),
+		"useProxy":        graphql.Boolean(action.EmailAction.UseProxy),
+		"attachCsv":       graphql.NewBoolean(graphql.Boolean(action.EmailAttachCSV)),
+	}
+}
+
+func toEmailAction(data emailActionData) *Action {
+	action := &Action{
+		Action: humio.Action{
+			Type: humio.ActionTypeEmail,
+			ID:   data.ID,
+			Name: data.Name,
+			EmailAction: humio.EmailAction{
+				Recipients: data.Recipients,
+				UseProxy:   data.UseProxy,
+			},
+		},
+		EmailAttachCSV: data.AttachCSV,
+	}
+	if data.SubjectTemplate != nil {
+		action.EmailAction.SubjectTemplate = *data.SubjectTemplate
+	}
+	if data.BodyTemplate != nil {
+		action.EmailAction.BodyTemplate = *data.BodyTemplate
+	}
+	return action
+}
+
+func webhookActionVariables(viewName string, action *Action) map[string]interface{} {
+	headers := action.WebhookAction.Headers
+	if headers == nil {
+		headers = []humio.HttpHeaderEntryInput{}
+	}
+	return map[string]interface{}{
+		"viewName":      graphql.String(viewName),
+		"actionName":    graphql.String(action.Name),
+		"url":           graphql.String(action.WebhookAction.Url),
+		"method":        graphql.String(action.WebhookAction.Method),
+		"headers":       headers,
+		"bodyTemplate":  graphql.String(action.WebhookAction.BodyTemplate),
+		"ignoreSSL":     graphql.Boolean(action.WebhookAction.IgnoreSSL),
+		"useProxy":      graphql.Boolean(action.WebhookAction.UseProxy),
+		"caCertificate": optStringArg(action.WebhookCACertificate),
+	}
+}
+
+func toWebhookAction(data webhookActionData) *Action {
+	action := &Action{
+		Action: humio.Action{
+			Type: humio.ActionTypeWebhook,
+			ID:   data.ID,
+			Name: data.Name,
+			WebhookAction: humio.WebhookAction{
+				Method:       data.Method,
+				Url:          data.URL,
+				Headers:      data.Headers,
+				BodyTemplate: data.BodyTemplate,
+				IgnoreSSL:    data.IgnoreSSL,
+				UseProxy:     data.UseProxy,
+			},
+		},
+	}
+	if data.CACertificate != nil {
+		action.WebhookCACertificate = *data.CACertificate
+	}
+	return action
+}
+
+func toUploadFileAction(data uploadFileActionData) *Action {
+	return &Action{
+		Action: humio.Action{
+			Type: ActionTypeUploadFile,
+			ID:   data.ID,
+			Name: data.Name,
+		},
+		UploadFileAction: UploadFileAction{
+			FileName:   data.FileName,
+			UpdateMode: data.UpdateMode,
+		},
+	}
+}
+
+// ActionTestResult is the result of sending a test notification through an action.
+type ActionTestResult struct {
+	Success bool
+	Message string
+}
+
+// actionTestTriggerName and actionTestEventData describe the alert and events of the test notifications sent by Test.
+const (
+	actionTestTriggerName = "Terraform verification"
+	actionTestEventData   = `[{"message":"Test notification sent by the Humio Terraform provider"}]`
+)
+
+// Test sends a test notification through the action with the given ID. Failing to deliver the notification is not an
+// error, but is reported in the result.
+func (a *actions) Test(viewName, actionID string) (*ActionTestResult, error) {
+	var mutation struct {
+		TestActionFromID struct {
+			Success bool   `graphql:"success"`
+			Message string `graphql:"message"`
+		} `graphql:"testActionFromId(input: { viewName: $viewName, actionId: $actionId, triggerName: $triggerName, eventData: $eventData })"`
+	}
+
+	variables := map[string]interface{}{
+		"viewName":    graphql.String(viewName),
+		"actionId":    graphql.String(actionID),
+		"triggerName": graphql.String(actionTestTriggerName),
+		"eventData":   graphql.String(actionTestEventData),
+	}
+
+	err := a.client.Mutate(&mutation, variables)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ActionTestResult{
+		Success: mutation.TestActionFromID.Success,
+		Message: mutation.TestActionFromID.Message,
+	}, nil
+}
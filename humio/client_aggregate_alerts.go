@@ -0,0 +1,232 @@
+// Copyright © 2020 Humio Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package humio
+
+import (
+	"fmt"
+
+	graphql "github.com/cli/shurcooL-graphql"
+
+	humio "github.com/humio/cli/api"
+)
+
+// AggregateAlert mirrors the shape of humio.Alert for aggregate alerts, which github.com/humio/cli does not support.
+type AggregateAlert struct {
+	ID                    string
+	Name                  string
+	Description           string
+	QueryString           string
+	Actions               []string
+	Labels                []string
+	Enabled               bool
+	ThrottleTimeSeconds   int
+	ThrottleField         string
+	SearchIntervalSeconds int
+	QueryTimestampType    string
+	TriggerMode           string
+	RunAsUserID           string
+	QueryOwnershipType    string
+}
+
+type aggregateAlertData struct {
+	ID                    string            `graphql:"id"`
+	Name                  string            `graphql:"name"`
+	Description           string            `graphql:"description"`
+	QueryString           string            `graphql:"queryString"`
+	Actions               []actionReference `graphql:"actions"`
+	Labels                []string          `graphql:"labels"`
+	Enabled               bool              `graphql:"enabled"`
+	ThrottleTimeSeconds   int               `graphql:"throttleTimeSeconds"`
+	ThrottleField         *string           `graphql:"throttleField"`
+	SearchIntervalSeconds int               `graphql:"searchIntervalSeconds"`
+	QueryTimestampType    string            `graphql:"queryTimestampType"`
+	TriggerMode           string            `graphql:"triggerMode"`
+	QueryOwnership        queryOwnership    `graphql:"queryOwnership"`
+	RunAsUser             *struct {
+		ID string `graphql:"id"`
+	} `graphql:"runAsUser"`
+}
+
+type aggregateAlerts struct {
+	client *humio.Client
+	lists  *listCache
+}
+
+func newAggregateAlerts(client *humio.Client) *aggregateAlerts {
+	return &aggregateAlerts{client: client}
+}
+
+// List lists the aggregate alerts in the view, reusing the list of an earlier call if it is cached.
+func (a *aggregateAlerts) List(viewName string) ([]AggregateAlert, error) {
+	return cachedList(a.lists, "aggregate alerts", viewName, a.list)
+}
+
+func (a *aggregateAlerts) list(viewName string) ([]AggregateAlert, error) {
+	var query struct {
+		SearchDomain struct {
+			AggregateAlerts []aggregateAlertData `graphql:"aggregateAlerts"`
+		} `graphql:"searchDomain(name: $viewName)"`
+	}
+
+	variables := map[string]interface{}{
+		"viewName": graphql.String(viewName),
+	}
+
+	err := a.client.Query(&query, variables)
+	if err != nil {
+		return nil, err
+	}
+
+	var alerts []AggregateAlert
+	for _, data := range query.SearchDomain.AggregateAlerts {
+		alerts = append(alerts, toAggregateAlert(data))
+	}
+	return alerts, nil
+}
+
+func (a *aggregateAlerts) Get(viewName, name string) (*AggregateAlert, error) {
+	alerts, err := a.List(viewName)
+	if err != nil {
+		return nil, fmt.Errorf("unable to list aggregate alerts: %w", err)
+	}
+	for _, alert := range alerts {
+		if alert.Name == name {
+			return &alert, nil
+		}
+	}
+
+	return nil, notFoundError{entityType: "aggregate alert", key: name}
+}
+
+func (a *aggregateAlerts) Add(viewName string, alert *AggregateAlert) (*AggregateAlert, error) {
+	defer a.lists.invalidate("aggregate alerts", viewName)
+
+	if alert == nil {
+		return nil, fmt.Errorf("aggregate alert must not be nil")
+	}
+
+	var mutation struct {
+		AggregateAlert aggregateAlertData `graphql:"createAggregateAlert(input: { viewName: $viewName, name: $name, description: $description, queryString: $queryString, actionIdsOrNames: $actionIdsOrNames, labels: $labels, enabled: $enabled, throttleTimeSeconds: $throttleTimeSeconds, throttleField: $throttleField, searchIntervalSeconds: $searchIntervalSeconds, queryTimestampType: $queryTimestampType, triggerMode: $triggerMode, runAsUserId: $runAsUserId, queryOwnershipType: $queryOwnershipType })"`
+	}
+
+	variables := aggregateAlertVariables(viewName, alert)
+
+	err := a.client.Mutate(&mutation, variables)
+	if err != nil {
+		return nil, err
+	}
+
+	created := toAggregateAlert(mutation.AggregateAlert)
+	return &created, nil
+}
+
+func (a *aggregateAlerts) Update(viewName string, alert *AggregateAlert) (*AggregateAlert, error) {
+	defer a.lists.invalidate("aggregate alerts", viewName)
+
+	if alert == nil {
+		return nil, fmt.Errorf("aggregate alert must not be nil")
+	}
+
+	if alert.ID == "" {
+		return nil, fmt.Errorf("aggregate alert must have non-empty id")
+	}
+
+	var mutation struct {
+		AggregateAlert aggregateAlertData `graphql:"updateAggregateAlert(input: { viewName: $viewName, id: $id, name: $name, description: $description, queryString: $queryString, actionIdsOrNames: $actionIdsOrNames, labels: $labels, enabled: $enabled, throttleTimeSeconds: $throttleTimeSeconds, throttleField: $throttleField, searchIntervalSeconds: $searchIntervalSeconds, queryTimestampType: $queryTimestampType, triggerMode: $triggerMode, runAsUserId: $runAsUserId, queryOwnershipType: $queryOwnershipType })"`
+	}
+
+	variables := aggregateAlertVariables(viewName, alert)
+	variables["id"] = graphql.String(alert.ID)
+
+	err := a.client.Mutate(&mutation, variables)
+	if err != nil {
+		return nil, err
+	}
+
+	updated := toAggregateAlert(mutation.AggregateAlert)
+	return &updated, nil
+}
+
+func (a *aggregateAlerts) Delete(viewName, name string) error {
+	defer a.lists.invalidate("aggregate alerts", viewName)
+
+	alert, err := a.Get(viewName, name)
+	if err != nil {
+		return err
+	}
+
+	var mutation struct {
+		DeleteAggregateAlert bool `graphql:"deleteAggregateAlert(input: { viewName: $viewName, id: $id })"`
+	}
+
+	variables := map[string]interface{}{
+		"viewName": RepoOrViewName(viewName),
+		"id":       graphql.String(alert.ID),
+	}
+
+	return a.client.Mutate(&mutation, variables)
+}
+
+func aggregateAlertVariables(viewName string, alert *AggregateAlert) map[string]interface{} {
+	ownership := QueryOwnershipType(alert.QueryOwnershipType)
+	if ownership == "" {
+		ownership = QueryOwnershipType(humio.QueryOwnershipTypeOrganization)
+	}
+
+	return map[string]interface{}{
+		"viewName":              RepoOrViewName(viewName),
+		"name":                  graphql.String(alert.Name),
+		"description":           optStringArg(alert.Description),
+		"queryString":           graphql.String(alert.QueryString),
+		"actionIdsOrNames":      graphqlStringList(alert.Actions),
+		"labels":                graphqlStringList(alert.Labels),
+		"enabled":               graphql.Boolean(alert.Enabled),
+		"throttleTimeSeconds":   Long(alert.ThrottleTimeSeconds),
+		"throttleField":         optStringArg(alert.ThrottleField),
+		"searchIntervalSeconds": Long(alert.SearchIntervalSeconds),
+		"queryTimestampType":    QueryTimestampType(alert.QueryTimestampType),
+		"triggerMode":           optTriggerModeArg(alert.TriggerMode),
+		"runAsUserId":           optStringArg(alert.RunAsUserID),
+		"queryOwnershipType":    ownership,
+	}
+}
+
+func toAggregateAlert(data aggregateAlertData) AggregateAlert {
+	var runAsUserID string
+	if data.RunAsUser != nil {
+		runAsUserID = data.RunAsUser.ID
+	}
+	var throttleField string
+	if data.ThrottleField != nil {
+		throttleField = *data.ThrottleField
+	}
+
+	return AggregateAlert{
+		ID:                    data.ID,
+		Name:                  data.Name,
+		Description:           data.Description,
+		QueryString:           data.QueryString,
+		Actions:               actionIDs(data.Actions),
+		Labels:                data.Labels,
+		Enabled:               data.Enabled,
+		ThrottleTimeSeconds:   data.ThrottleTimeSeconds,
+		ThrottleField:         throttleField,
+		SearchIntervalSeconds: data.SearchIntervalSeconds,
+		QueryTimestampType:    data.QueryTimestampType,
+		TriggerMode:           data.TriggerMode,
+		RunAsUserID:           runAsUserID,
+		QueryOwnershipType:    queryOwnershipTypeFromTypename(string(data.QueryOwnership.Typename)),
+	}
+}
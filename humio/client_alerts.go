@@ -0,0 +1,64 @@
+// Copyright © 2020 Humio Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package humio
+
+import (
+	"fmt"
+
+	humio "github.com/humio/cli/api"
+)
+
+// alerts manages alerts through github.com/humio/cli, caching the lists it gets alerts from.
+type alerts struct {
+	client *humio.Client
+	lists  *listCache
+}
+
+// List lists the alerts in the view, reusing the list of an earlier call if it is cached.
+func (a *alerts) List(viewName string) ([]humio.Alert, error) {
+	return cachedList(a.lists, "alerts", viewName, a.client.Alerts().List)
+}
+
+func (a *alerts) Get(viewName, alertName string) (*humio.Alert, error) {
+	alerts, err := a.List(viewName)
+	if err != nil {
+		return nil, fmt.Errorf("unable to list alerts: %w", err)
+	}
+	for _, alert := range alerts {
+		if alert.Name == alertName {
+			return &alert, nil
+		}
+	}
+
+	return nil, humio.AlertNotFound(alertName)
+}
+
+func (a *alerts) Add(viewName string, alert *humio.Alert) (*humio.Alert, error) {
+	defer a.lists.invalidate("alerts", viewName)
+
+	return a.client.Alerts().Add(viewName, alert)
+}
+
+func (a *alerts) Update(viewName string, alert *humio.Alert) (*humio.Alert, error) {
+	defer a.lists.invalidate("alerts", viewName)
+
+	return a.client.Alerts().Update(viewName, alert)
+}
+
+func (a *alerts) Delete(viewName, alertName string) error {
+	defer a.lists.invalidate("alerts", viewName)
+
+	return a.client.Alerts().Delete(viewName, alertName)
+}
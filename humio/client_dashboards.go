@@ -0,0 +1,143 @@
+// Copyright © 2020 Humio Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package humio
+
+import (
+	"fmt"
+
+	graphql "github.com/cli/shurcooL-graphql"
+
+	humio "github.com/humio/cli/api"
+)
+
+// Dashboard is a dashboard together with the YAML template describing its widgets, parameters, time selector and
+// sections. github.com/humio/cli does not support dashboards.
+type Dashboard struct {
+	ID           string
+	Name         string
+	TemplateYAML string
+}
+
+type dashboardData struct {
+	ID           string `graphql:"id"`
+	Name         string `graphql:"name"`
+	TemplateYAML string `graphql:"templateYaml"`
+}
+
+type dashboards struct {
+	client *humio.Client
+	lists  *listCache
+}
+
+func newDashboards(client *humio.Client) *dashboards {
+	return &dashboards{client: client}
+}
+
+// List lists the dashboards in the view, reusing the list of an earlier call if it is cached.
+func (d *dashboards) List(viewName string) ([]Dashboard, error) {
+	return cachedList(d.lists, "dashboards", viewName, d.list)
+}
+
+func (d *dashboards) list(viewName string) ([]Dashboard, error) {
+	var query struct {
+		SearchDomain struct {
+			Dashboards []dashboardData `graphql:"dashboards"`
+		} `graphql:"searchDomain(name: $viewName)"`
+	}
+
+	variables := map[string]interface{}{
+		"viewName": graphql.String(viewName),
+	}
+
+	err := d.client.Query(&query, variables)
+	if err != nil {
+		return nil, err
+	}
+
+	var dashboards []Dashboard
+	for _, data := range query.SearchDomain.Dashboards {
+		dashboards = append(dashboards, Dashboard(data))
+	}
+	return dashboards, nil
+}
+
+func (d *dashboards) Get(viewName, name string) (*Dashboard, error) {
+	dashboards, err := d.List(viewName)
+	if err != nil {
+		return nil, fmt.Errorf("unable to list dashboards: %w", err)
+	}
+	for _, dashboard := range dashboards {
+		if dashboard.Name == name {
+			return &dashboard, nil
+		}
+	}
+
+	return nil, notFoundError{entityType: "dashboard", key: name}
+}
+
+// Add creates a dashboard from its template. The name of the dashboard overrides any name given in the template.
+func (d *dashboards) Add(viewName string, dashboard *Dashboard) (*Dashboard, error) {
+	defer d.lists.invalidate("dashboards", viewName)
+
+	if dashboard == nil {
+		return nil, fmt.Errorf("dashboard must not be nil")
+	}
+
+	var mutation struct {
+		Dashboard struct {
+			ID   string `graphql:"id"`
+			Name string `graphql:"name"`
+		} `graphql:"createDashboardFromTemplateV2(input: { viewName: $viewName, template: $template, overrideName: $overrideName })"`
+	}
+
+	variables := map[string]interface{}{
+		"viewName":     RepoOrViewName(viewName),
+		"template":     graphql.String(dashboard.TemplateYAML),
+		"overrideName": optStringArg(dashboard.Name),
+	}
+
+	err := d.client.Mutate(&mutation, variables)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Dashboard{
+		ID:           mutation.Dashboard.ID,
+		Name:         mutation.Dashboard.Name,
+		TemplateYAML: dashboard.TemplateYAML,
+	}, nil
+}
+
+func (d *dashboards) Delete(viewName, name string) error {
+	defer d.lists.invalidate("dashboards", viewName)
+
+	dashboard, err := d.Get(viewName, name)
+	if err != nil {
+		return err
+	}
+
+	var mutation struct {
+		DeleteDashboard struct {
+			// We have to make a selection, so just take __typename
+			Typename graphql.String `graphql:"__typename"`
+		} `graphql:"deleteDashboard(input: { id: $id })"`
+	}
+
+	variables := map[string]interface{}{
+		"id": graphql.String(dashboard.ID),
+	}
+
+	return d.client.Mutate(&mutation, variables)
+}
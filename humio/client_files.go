@@ -0,0 +1,44 @@
+// Copyright © 2020 Humio Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package humio
+
+import (
+	"fmt"
+
+	humio "github.com/humio/cli/api"
+)
+
+// files adds lookups of single files to the file operations in github.com/humio/cli.
+type files struct {
+	client *humio.Client
+}
+
+func newFiles(client *humio.Client) *files {
+	return &files{client: client}
+}
+
+func (f *files) Get(viewName, fileName string) (*humio.File, error) {
+	files, err := f.client.Files().List(viewName)
+	if err != nil {
+		return nil, fmt.Errorf("unable to list files: %w", err)
+	}
+	for _, file := range files {
+		if file.Name == fileName {
+			return &file, nil
+		}
+	}
+
+	return nil, notFoundError{entityType: "file", key: fileName}
+}
@@ -0,0 +1,230 @@
+// Copyright © 2020 Humio Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package humio
+
+import (
+	"fmt"
+
+	graphql "github.com/cli/shurcooL-graphql"
+
+	humio "github.com/humio/cli/api"
+)
+
+// FilterAlert mirrors the shape of humio.Alert for filter alerts, which github.com/humio/cli does not support.
+type FilterAlert struct {
+	ID                  string
+	Name                string
+	Description         string
+	QueryString         string
+	Actions             []string
+	Labels              []string
+	Enabled             bool
+	ThrottleTimeSeconds int
+	ThrottleField       string
+	RunAsUserID         string
+	QueryOwnershipType  string
+}
+
+type filterAlertData struct {
+	ID                  string            `graphql:"id"`
+	Name                string            `graphql:"name"`
+	Description         string            `graphql:"description"`
+	QueryString         string            `graphql:"queryString"`
+	Actions             []actionReference `graphql:"actions"`
+	Labels              []string          `graphql:"labels"`
+	Enabled             bool              `graphql:"enabled"`
+	ThrottleTimeSeconds *int              `graphql:"throttleTimeSeconds"`
+	ThrottleField       *string           `graphql:"throttleField"`
+	QueryOwnership      queryOwnership    `graphql:"queryOwnership"`
+	RunAsUser           *struct {
+		ID string `graphql:"id"`
+	} `graphql:"runAsUser"`
+}
+
+type filterAlerts struct {
+	client *humio.Client
+	lists  *listCache
+}
+
+func newFilterAlerts(client *humio.Client) *filterAlerts {
+	return &filterAlerts{client: client}
+}
+
+// List lists the filter alerts in the view, reusing the list of an earlier call if it is cached.
+func (f *filterAlerts) List(viewName string) ([]FilterAlert, error) {
+	return cachedList(f.lists, "filter alerts", viewName, f.list)
+}
+
+func (f *filterAlerts) list(viewName string) ([]FilterAlert, error) {
+	var query struct {
+		SearchDomain struct {
+			FilterAlerts []filterAlertData `graphql:"filterAlerts"`
+		} `graphql:"searchDomain(name: $viewName)"`
+	}
+
+	variables := map[string]interface{}{
+		"viewName": graphql.String(viewName),
+	}
+
+	err := f.client.Query(&query, variables)
+	if err != nil {
+		return nil, err
+	}
+
+	var alerts []FilterAlert
+	for _, data := range query.SearchDomain.FilterAlerts {
+		alerts = append(alerts, toFilterAlert(data))
+	}
+	return alerts, nil
+}
+
+func (f *filterAlerts) Get(viewName, name string) (*FilterAlert, error) {
+	alerts, err := f.List(viewName)
+	if err != nil {
+		return nil, fmt.Errorf("unable to list filter alerts: %w", err)
+	}
+	for _, alert := range alerts {
+		if alert.Name == name {
+			return &alert, nil
+		}
+	}
+
+	return nil, notFoundError{entityType: "filter alert", key: name}
+}
+
+func (f *filterAlerts) Add(viewName string, alert *FilterAlert) (*FilterAlert, error) {
+	defer f.lists.invalidate("filter alerts", viewName)
+
+	if alert == nil {
+		return nil, fmt.Errorf("filter alert must not be nil")
+	}
+
+	var mutation struct {
+		FilterAlert filterAlertData `graphql:"createFilterAlert(input: { viewName: $viewName, name: $name, description: $description, queryString: $queryString, actionIdsOrNames: $actionIdsOrNames, labels: $labels, enabled: $enabled, throttleTimeSeconds: $throttleTimeSeconds, throttleField: $throttleField, runAsUserId: $runAsUserId, queryOwnershipType: $queryOwnershipType })"`
+	}
+
+	variables := filterAlertVariables(viewName, alert)
+
+	err := f.client.Mutate(&mutation, variables)
+	if err != nil {
+		return nil, err
+	}
+
+	created := toFilterAlert(mutation.FilterAlert)
+	return &created, nil
+}
+
+func (f *filterAlerts) Update(viewName string, alert *FilterAlert) (*FilterAlert, error) {
+	defer f.lists.invalidate("filter alerts", viewName)
+
+	if alert == nil {
+		return nil, fmt.Errorf("filter alert must not be nil")
+	}
+
+	if alert.ID == "" {
+		return nil, fmt.Errorf("filter alert must have non-empty id")
+	}
+
+	var mutation struct {
+		FilterAlert filterAlertData `graphql:"updateFilterAlert(input: { viewName: $viewName, id: $id, name: $name, description: $description, queryString: $queryString, actionIdsOrNames: $actionIdsOrNames, labels: $labels, enabled: $enabled, throttleTimeSeconds: $throttleTimeSeconds, throttleField: $throttleField, runAsUserId: $runAsUserId, queryOwnershipType: $queryOwnershipType })"`
+	}
+
+	variables := filterAlertVariables(viewName, alert)
+	variables["id"] = graphql.String(alert.ID)
+
+	err := f.client.Mutate(&mutation, variables)
+	if err != nil {
+		return nil, err
+	}
+
+	updated := toFilterAlert(mutation.FilterAlert)
+	return &updated, nil
+}
+
+func (f *filterAlerts) Delete(viewName, name string) error {
+	defer f.lists.invalidate("filter alerts", viewName)
+
+	alert, err := f.Get(viewName, name)
+	if err != nil {
+		return err
+	}
+
+	var mutation struct {
+		DeleteFilterAlert bool `graphql:"deleteFilterAlert(input: { viewName: $viewName, id: $id })"`
+	}
+
+	variables := map[string]interface{}{
+		"viewName": RepoOrViewName(viewName),
+		"id":       graphql.String(alert.ID),
+	}
+
+	return f.client.Mutate(&mutation, variables)
+}
+
+func filterAlertVariables(viewName string, alert *FilterAlert) map[string]interface{} {
+	var throttleTimeSeconds *Long
+	if alert.ThrottleTimeSeconds > 0 {
+		seconds := Long(alert.ThrottleTimeSeconds)
+		throttleTimeSeconds = &seconds
+	}
+
+	ownership := QueryOwnershipType(alert.QueryOwnershipType)
+	if ownership == "" {
+		ownership = QueryOwnershipType(humio.QueryOwnershipTypeOrganization)
+	}
+
+	return map[string]interface{}{
+		"viewName":            RepoOrViewName(viewName),
+		"name":                graphql.String(alert.Name),
+		"description":         optStringArg(alert.Description),
+		"queryString":         graphql.String(alert.QueryString),
+		"actionIdsOrNames":    graphqlStringList(alert.Actions),
+		"labels":              graphqlStringList(alert.Labels),
+		"enabled":             graphql.Boolean(alert.Enabled),
+		"throttleTimeSeconds": throttleTimeSeconds,
+		"throttleField":       optStringArg(alert.ThrottleField),
+		"runAsUserId":         optStringArg(alert.RunAsUserID),
+		"queryOwnershipType":  ownership,
+	}
+}
+
+func toFilterAlert(data filterAlertData) FilterAlert {
+	var runAsUserID string
+	if data.RunAsUser != nil {
+		runAsUserID = data.RunAsUser.ID
+	}
+	var throttleTimeSeconds int
+	if data.ThrottleTimeSeconds != nil {
+		throttleTimeSeconds = *data.ThrottleTimeSeconds
+	}
+	var throttleField string
+	if data.ThrottleField != nil {
+		throttleField = *data.ThrottleField
+	}
+
+	return FilterAlert{
+		ID:                  data.ID,
+		Name:                data.Name,
+		Description:         data.Description,
+		QueryString:         data.QueryString,
+		Actions:             actionIDs(data.Actions),
+		Labels:              data.Labels,
+		Enabled:             data.Enabled,
+		ThrottleTimeSeconds: throttleTimeSeconds,
+		ThrottleField:       throttleField,
+		RunAsUserID:         runAsUserID,
+		QueryOwnershipType:  queryOwnershipTypeFromTypename(string(data.QueryOwnership.Typename)),
+	}
+}
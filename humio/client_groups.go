@@ -0,0 +1,275 @@
+// Copyright © 2020 Humio Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package humio
+
+import (
+	graphql "github.com/cli/shurcooL-graphql"
+
+	humio "github.com/humio/cli/api"
+)
+
+// Group extends humio.Group with the fields needed to manage groups, which github.com/humio/cli does not support.
+type Group struct {
+	ID          string
+	DisplayName string
+	LookupName  string
+}
+
+// GroupViewRole is a role assigned to a group for a single view or repository.
+type GroupViewRole struct {
+	ViewName string
+	RoleID   string
+}
+
+type groupData struct {
+	ID          string  `graphql:"id"`
+	DisplayName string  `graphql:"displayName"`
+	LookupName  *string `graphql:"lookupName"`
+}
+
+type groups struct {
+	client *humio.Client
+}
+
+func newGroups(client *humio.Client) *groups {
+	return &groups{client: client}
+}
+
+func (g *groups) Get(id string) (*Group, error) {
+	var query struct {
+		Group groupData `graphql:"group(groupId: $groupId)"`
+	}
+
+	variables := map[string]interface{}{
+		"groupId": graphql.String(id),
+	}
+
+	err := g.client.Query(&query, variables)
+	if err != nil {
+		return nil, g.notFoundOr(id, err)
+	}
+
+	group := toGroup(query.Group)
+	return &group, nil
+}
+
+func (g *groups) Add(group *Group) (*Group, error) {
+	var mutation struct {
+		AddGroup struct {
+			Group groupData `graphql:"group"`
+		} `graphql:"addGroup(displayName: $displayName, lookupName: $lookupName)"`
+	}
+
+	variables := map[string]interface{}{
+		"displayName": graphql.String(group.DisplayName),
+		"lookupName":  optStringArg(group.LookupName),
+	}
+
+	err := g.client.Mutate(&mutation, variables)
+	if err != nil {
+		return nil, err
+	}
+
+	created := toGroup(mutation.AddGroup.Group)
+	return &created, nil
+}
+
+func (g *groups) Update(group *Group) (*Group, error) {
+	var mutation struct {
+		UpdateGroup struct {
+			Group groupData `graphql:"group"`
+		} `graphql:"updateGroup(input: { groupId: $groupId, displayName: $displayName, lookupName: $lookupName })"`
+	}
+
+	variables := map[string]interface{}{
+		"groupId":     graphql.String(group.ID),
+		"displayName": graphql.String(group.DisplayName),
+		"lookupName":  optStringArg(group.LookupName),
+	}
+
+	err := g.client.Mutate(&mutation, variables)
+	if err != nil {
+		return nil, err
+	}
+
+	updated := toGroup(mutation.UpdateGroup.Group)
+	return &updated, nil
+}
+
+func (g *groups) Delete(id string) error {
+	var mutation struct {
+		RemoveGroup struct {
+			// We have to make a selection, so just take __typename
+			Typename graphql.String `graphql:"__typename"`
+		} `graphql:"removeGroup(groupId: $groupId)"`
+	}
+
+	variables := map[string]interface{}{
+		"groupId": graphql.String(id),
+	}
+
+	return g.client.Mutate(&mutation, variables)
+}
+
+// UserIDs returns the IDs of the users that are members of the group.
+func (g *groups) UserIDs(id string) ([]string, error) {
+	var query struct {
+		Group struct {
+			Users []struct {
+				ID string `graphql:"id"`
+			} `graphql:"users"`
+		} `graphql:"group(groupId: $groupId)"`
+	}
+
+	variables := map[string]interface{}{
+		"groupId": graphql.String(id),
+	}
+
+	err := g.client.Query(&query, variables)
+	if err != nil {
+		return nil, g.notFoundOr(id, err)
+	}
+
+	var userIDs []string
+	for _, user := range query.Group.Users {
+		userIDs = append(userIDs, user.ID)
+	}
+	return userIDs, nil
+}
+
+// ViewRoles returns the roles assigned to the group, per view or repository.
+func (g *groups) ViewRoles(id string) ([]GroupViewRole, error) {
+	var query struct {
+		Group struct {
+			Roles []struct {
+				Role struct {
+					ID string `graphql:"id"`
+				} `graphql:"role"`
+				SearchDomain struct {
+					Name string `graphql:"name"`
+				} `graphql:"searchDomain"`
+			} `graphql:"roles"`
+		} `graphql:"group(groupId: $groupId)"`
+	}
+
+	variables := map[string]interface{}{
+		"groupId": graphql.String(id),
+	}
+
+	err := g.client.Query(&query, variables)
+	if err != nil {
+		return nil, g.notFoundOr(id, err)
+	}
+
+	var roles []GroupViewRole
+	for _, role := range query.Group.Roles {
+		roles = append(roles, GroupViewRole{
+			ViewName: role.SearchDomain.Name,
+			RoleID:   role.Role.ID,
+		})
+	}
+	return roles, nil
+}
+
+func (g *groups) AssignViewRole(id, viewName, roleID string) error {
+	viewID, err := searchDomainID(g.client, viewName)
+	if err != nil {
+		return err
+	}
+
+	var mutation struct {
+		AssignRoleToGroup struct {
+			// We have to make a selection, so just take __typename
+			Typename graphql.String `graphql:"__typename"`
+		} `graphql:"assignRoleToGroup(input: { viewId: $viewId, groupId: $groupId, roleId: $roleId })"`
+	}
+
+	variables := map[string]interface{}{
+		"viewId":  graphql.String(viewID),
+		"groupId": graphql.String(id),
+		"roleId":  graphql.String(roleID),
+	}
+
+	return g.client.Mutate(&mutation, variables)
+}
+
+func (g *groups) UnassignViewRole(id, viewName, roleID string) error {
+	viewID, err := searchDomainID(g.client, viewName)
+	if err != nil {
+		return err
+	}
+
+	var mutation struct {
+		UnassignRoleFromGroup struct {
+			// We have to make a selection, so just take __typename
+			Typename graphql.String `graphql:"__typename"`
+		} `graphql:"unassignRoleFromGroup(input: { viewId: $viewId, groupId: $groupId, roleId: $roleId })"`
+	}
+
+	variables := map[string]interface{}{
+		"viewId":  graphql.String(viewID),
+		"groupId": graphql.String(id),
+		"roleId":  graphql.String(roleID),
+	}
+
+	return g.client.Mutate(&mutation, variables)
+}
+
+// notFoundOr returns a notFoundError if the group no longer exists, as LogScale only reports this as a generic
+// GraphQL error when looking up a group by its ID, and err otherwise.
+func (g *groups) notFoundOr(id string, err error) error {
+	list, listErr := g.client.Groups().List()
+	if listErr != nil {
+		return err
+	}
+	for _, group := range list {
+		if group.ID == id {
+			return err
+		}
+	}
+	return notFoundError{entityType: "group", key: id}
+}
+
+// searchDomainID returns the ID of the view or repository with the given name.
+func searchDomainID(client *humio.Client, name string) (string, error) {
+	var query struct {
+		SearchDomain struct {
+			ID string `graphql:"id"`
+		} `graphql:"searchDomain(name: $name)"`
+	}
+
+	variables := map[string]interface{}{
+		"name": graphql.String(name),
+	}
+
+	err := client.Query(&query, variables)
+	if err != nil {
+		return "", err
+	}
+	return query.SearchDomain.ID, nil
+}
+
+func toGroup(data groupData) Group {
+	var lookupName string
+	if data.LookupName != nil {
+		lookupName = *data.LookupName
+	}
+
+	return Group{
+		ID:          data.ID,
+		DisplayName: data.DisplayName,
+		LookupName:  lookupName,
+	}
+}
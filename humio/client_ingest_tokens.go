@@ -0,0 +1,72 @@
+// Copyright © 2020 Humio Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package humio
+
+import (
+	graphql "github.com/cli/shurcooL-graphql"
+
+	humio "github.com/humio/cli/api"
+)
+
+// ingestTokens adds the ingest token operations that github.com/humio/cli does not support.
+type ingestTokens struct {
+	client *humio.Client
+}
+
+func newIngestTokens(client *humio.Client) *ingestTokens {
+	return &ingestTokens{client: client}
+}
+
+// Get returns the ingest token with the name in the repository, or a notFoundError if either does not exist.
+func (i *ingestTokens) Get(repositoryName, tokenName string) (*humio.IngestToken, error) {
+	list, err := i.client.IngestTokens().List(repositoryName)
+	if err != nil {
+		return nil, searchDomainNotFoundOr(i.client, repositoryName, err)
+	}
+	for _, token := range list {
+		if token.Name == tokenName {
+			return &token, nil
+		}
+	}
+	return nil, notFoundError{entityType: "ingest token", key: tokenName}
+}
+
+// Refresh replaces the secret of the ingest token. If gracePeriodSeconds is positive, the previous secret keeps
+// working for that many seconds, so shippers can be moved over to the new secret without dropping events.
+func (i *ingestTokens) Refresh(repositoryName, tokenName string, gracePeriodSeconds int) error {
+	variables := map[string]interface{}{
+		"repositoryName": graphql.String(repositoryName),
+		"tokenName":      graphql.String(tokenName),
+	}
+
+	if gracePeriodSeconds > 0 {
+		var mutation struct {
+			RefreshIngestToken struct {
+				// We have to make a selection, so just take __typename
+				Typename graphql.String `graphql:"__typename"`
+			} `graphql:"refreshIngestToken(input: { repositoryName: $repositoryName, name: $tokenName, gracePeriodSeconds: $gracePeriodSeconds })"`
+		}
+		variables["gracePeriodSeconds"] = Long(gracePeriodSeconds)
+		return i.client.Mutate(&mutation, variables)
+	}
+
+	var mutation struct {
+		RefreshIngestToken struct {
+			// We have to make a selection, so just take __typename
+			Typename graphql.String `graphql:"__typename"`
+		} `graphql:"refreshIngestToken(input: { repositoryName: $repositoryName, name: $tokenName })"`
+	}
+	return i.client.Mutate(&mutation, variables)
+}
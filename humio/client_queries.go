@@ -0,0 +1,151 @@
+// Copyright © 2020 Humio Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package humio
+
+import (
+	"errors"
+	"strings"
+
+	graphql "github.com/cli/shurcooL-graphql"
+
+	humio "github.com/humio/cli/api"
+)
+
+// QuerySeverityError is the severity of query diagnostics that make a query invalid.
+const QuerySeverityError = "Error"
+
+// QueryDiagnostic is a problem LogScale found when validating a query.
+type QueryDiagnostic struct {
+	Message  string
+	Code     string
+	Severity string
+	// Position is where in the query the problem was found, or nil if LogScale does not report it.
+	Position *QueryPosition
+}
+
+// QueryPosition is a position in a query string. Lines and columns start at 1.
+type QueryPosition struct {
+	Line   int
+	Column int
+}
+
+type queries struct {
+	client *humio.Client
+}
+
+func newQueries(client *humio.Client) *queries {
+	return &queries{client: client}
+}
+
+// Validate validates the query string against the view without running it, and returns the diagnostics LogScale
+// found. The query is valid if none of them has severity QuerySeverityError.
+func (q *queries) Validate(viewName, queryString string) ([]QueryDiagnostic, error) {
+	diagnostics, err := q.validateWithPositions(viewName, queryString)
+	if isUnknownFieldError(err, "position") {
+		// Versions of LogScale that do not report the positions of diagnostics reject the whole query.
+		return q.validateWithoutPositions(viewName, queryString)
+	}
+	return diagnostics, err
+}
+
+func (q *queries) validateWithPositions(viewName, queryString string) ([]QueryDiagnostic, error) {
+	var query struct {
+		AnalyzeQuery struct {
+			ValidateQuery struct {
+				Diagnostics []struct {
+					Message  string `graphql:"message"`
+					Code     string `graphql:"code"`
+					Severity string `graphql:"severity"`
+					Position *struct {
+						Line   int `graphql:"line"`
+						Column int `graphql:"column"`
+					} `graphql:"position"`
+				} `graphql:"diagnostics"`
+			} `graphql:"validateQuery"`
+		} `graphql:"analyzeQuery(input: { queryString: $queryString, viewName: $viewName, version: { name: $version } })"`
+	}
+
+	err := q.client.Query(&query, validateQueryVariables(viewName, queryString))
+	if err != nil {
+		return nil, err
+	}
+
+	diagnostics := make([]QueryDiagnostic, len(query.AnalyzeQuery.ValidateQuery.Diagnostics))
+	for i, diagnostic := range query.AnalyzeQuery.ValidateQuery.Diagnostics {
+		diagnostics[i] = QueryDiagnostic{
+			Message:  diagnostic.Message,
+			Code:     diagnostic.Code,
+			Severity: diagnostic.Severity,
+		}
+		if diagnostic.Position != nil {
+			diagnostics[i].Position = &QueryPosition{
+				Line:   diagnostic.Position.Line,
+				Column: diagnostic.Position.Column,
+			}
+		}
+	}
+	return diagnostics, nil
+}
+
+func (q *queries) validateWithoutPositions(viewName, queryString string) ([]QueryDiagnostic, error) {
+	var query struct {
+		AnalyzeQuery struct {
+			ValidateQuery struct {
+				Diagnostics []struct {
+					Message  string `graphql:"message"`
+					Code     string `graphql:"code"`
+					Severity string `graphql:"severity"`
+				} `graphql:"diagnostics"`
+			} `graphql:"validateQuery"`
+		} `graphql:"analyzeQuery(input: { queryString: $queryString, viewName: $viewName, version: { name: $version } })"`
+	}
+
+	err := q.client.Query(&query, validateQueryVariables(viewName, queryString))
+	if err != nil {
+		return nil, err
+	}
+
+	diagnostics := make([]QueryDiagnostic, len(query.AnalyzeQuery.ValidateQuery.Diagnostics))
+	for i, diagnostic := range query.AnalyzeQuery.ValidateQuery.Diagnostics {
+		diagnostics[i] = QueryDiagnostic{
+			Message:  diagnostic.Message,
+			Code:     diagnostic.Code,
+			Severity: diagnostic.Severity,
+		}
+	}
+	return diagnostics, nil
+}
+
+func validateQueryVariables(viewName, queryString string) map[string]interface{} {
+	return map[string]interface{}{
+		"queryString": graphql.String(queryString),
+		"viewName":    RepoOrViewName(viewName),
+		"version":     LanguageVersionEnum("legacy"),
+	}
+}
+
+// isUnknownFieldError returns whether LogScale rejected a GraphQL query because it does not know the field.
+func isUnknownFieldError(err error, field string) bool {
+	var graphqlErrors graphql.Errors
+	if !errors.As(err, &graphqlErrors) {
+		return false
+	}
+	for _, e := range graphqlErrors {
+		if strings.Contains(e.Message, "Cannot query field") && strings.Contains(e.Message, field) {
+			return true
+		}
+	}
+	return false
+}
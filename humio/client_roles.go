@@ -0,0 +1,185 @@
+// Copyright © 2020 Humio Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package humio
+
+import (
+	"fmt"
+
+	graphql "github.com/cli/shurcooL-graphql"
+
+	humio "github.com/humio/cli/api"
+)
+
+// Role is a named set of view, organization and system permissions. The roles support in github.com/humio/cli does
+// not work against current LogScale versions, so roles are managed through this type instead.
+type Role struct {
+	ID                      string
+	DisplayName             string
+	Description             string
+	ViewPermissions         []string
+	OrganizationPermissions []string
+	SystemPermissions       []string
+}
+
+type roleData struct {
+	ID                      string   `graphql:"id"`
+	DisplayName             string   `graphql:"displayName"`
+	Description             *string  `graphql:"description"`
+	ViewPermissions         []string `graphql:"viewPermissions"`
+	OrganizationPermissions []string `graphql:"organizationPermissions"`
+	SystemPermissions       []string `graphql:"systemPermissions"`
+}
+
+type roles struct {
+	client *humio.Client
+}
+
+func newRoles(client *humio.Client) *roles {
+	return &roles{client: client}
+}
+
+func (r *roles) List() ([]Role, error) {
+	var query struct {
+		Roles []roleData `graphql:"roles"`
+	}
+
+	err := r.client.Query(&query, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var roles []Role
+	for _, data := range query.Roles {
+		roles = append(roles, toRole(data))
+	}
+	return roles, nil
+}
+
+func (r *roles) Get(id string) (*Role, error) {
+	roles, err := r.List()
+	if err != nil {
+		return nil, fmt.Errorf("unable to list roles: %w", err)
+	}
+	for _, role := range roles {
+		if role.ID == id {
+			return &role, nil
+		}
+	}
+
+	return nil, notFoundError{entityType: "role", key: id}
+}
+
+func (r *roles) Add(role *Role) (*Role, error) {
+	if role == nil {
+		return nil, fmt.Errorf("role must not be nil")
+	}
+
+	var mutation struct {
+		CreateRole struct {
+			Role roleData `graphql:"role"`
+		} `graphql:"createRole(input: { displayName: $displayName, description: $description, viewPermissions: $viewPermissions, organizationPermissions: $organizationPermissions, systemPermissions: $systemPermissions })"`
+	}
+
+	variables := roleVariables(role)
+
+	err := r.client.Mutate(&mutation, variables)
+	if err != nil {
+		return nil, err
+	}
+
+	created := toRole(mutation.CreateRole.Role)
+	return &created, nil
+}
+
+func (r *roles) Update(role *Role) (*Role, error) {
+	if role == nil {
+		return nil, fmt.Errorf("role must not be nil")
+	}
+
+	if role.ID == "" {
+		return nil, fmt.Errorf("role must have non-empty id")
+	}
+
+	var mutation struct {
+		UpdateRole struct {
+			Role roleData `graphql:"role"`
+		} `graphql:"updateRole(input: { roleId: $roleId, displayName: $displayName, description: $description, viewPermissions: $viewPermissions, organizationPermissions: $organizationPermissions, systemPermissions: $systemPermissions })"`
+	}
+
+	variables := roleVariables(role)
+	variables["roleId"] = graphql.String(role.ID)
+
+	err := r.client.Mutate(&mutation, variables)
+	if err != nil {
+		return nil, err
+	}
+
+	updated := toRole(mutation.UpdateRole.Role)
+	return &updated, nil
+}
+
+func (r *roles) Delete(id string) error {
+	var mutation struct {
+		RemoveRole struct {
+			// We have to make a selection, so just take __typename
+			Typename graphql.String `graphql:"__typename"`
+		} `graphql:"removeRole(roleId: $roleId)"`
+	}
+
+	variables := map[string]interface{}{
+		"roleId": graphql.String(id),
+	}
+
+	return r.client.Mutate(&mutation, variables)
+}
+
+func roleVariables(role *Role) map[string]interface{} {
+	viewPermissions := make([]Permission, len(role.ViewPermissions))
+	for i, p := range role.ViewPermissions {
+		viewPermissions[i] = Permission(p)
+	}
+	organizationPermissions := make([]OrganizationPermission, len(role.OrganizationPermissions))
+	for i, p := range role.OrganizationPermissions {
+		organizationPermissions[i] = OrganizationPermission(p)
+	}
+	systemPermissions := make([]SystemPermission, len(role.SystemPermissions))
+	for i, p := range role.SystemPermissions {
+		systemPermissions[i] = SystemPermission(p)
+	}
+
+	return map[string]interface{}{
+		"displayName":             graphql.String(role.DisplayName),
+		"description":             optStringArg(role.Description),
+		"viewPermissions":         viewPermissions,
+		"organizationPermissions": organizationPermissions,
+		"systemPermissions":       systemPermissions,
+	}
+}
+
+func toRole(data roleData) Role {
+	var description string
+	if data.Description != nil {
+		description = *data.Description
+	}
+
+	return Role{
+		ID:                      data.ID,
+		DisplayName:             data.DisplayName,
+		Description:             description,
+		ViewPermissions:         data.ViewPermissions,
+		OrganizationPermissions: data.OrganizationPermissions,
+		SystemPermissions:       data.SystemPermissions,
+	}
+}
@@ -0,0 +1,202 @@
+// Copyright © 2020 Humio Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package humio
+
+import (
+	"fmt"
+
+	graphql "github.com/cli/shurcooL-graphql"
+
+	humio "github.com/humio/cli/api"
+)
+
+// SavedQuery is a saved search that can be shared between users and referenced from dashboards. github.com/humio/cli
+// does not support saved queries.
+type SavedQuery struct {
+	ID          string
+	Name        string
+	QueryString string
+	Start       string
+	End         string
+	IsLive      bool
+	WidgetType  string
+	Options     string
+	Labels      []string
+}
+
+type savedQueryData struct {
+	ID    string `graphql:"id"`
+	Name  string `graphql:"name"`
+	Query struct {
+		QueryString string `graphql:"queryString"`
+		Start       string `graphql:"start"`
+		End         string `graphql:"end"`
+		IsLive      bool   `graphql:"isLive"`
+	} `graphql:"query"`
+	WidgetType string   `graphql:"widgetType"`
+	Labels     []string `graphql:"labels"`
+}
+
+type savedQueries struct {
+	client *humio.Client
+	lists  *listCache
+}
+
+func newSavedQueries(client *humio.Client) *savedQueries {
+	return &savedQueries{client: client}
+}
+
+// List lists the saved queries in the view, reusing the list of an earlier call if it is cached.
+func (s *savedQueries) List(viewName string) ([]SavedQuery, error) {
+	return cachedList(s.lists, "saved queries", viewName, s.list)
+}
+
+func (s *savedQueries) list(viewName string) ([]SavedQuery, error) {
+	var query struct {
+		SearchDomain struct {
+			SavedQueries []savedQueryData `graphql:"savedQueries"`
+		} `graphql:"searchDomain(name: $viewName)"`
+	}
+
+	variables := map[string]interface{}{
+		"viewName": graphql.String(viewName),
+	}
+
+	err := s.client.Query(&query, variables)
+	if err != nil {
+		return nil, err
+	}
+
+	var savedQueries []SavedQuery
+	for _, data := range query.SearchDomain.SavedQueries {
+		savedQueries = append(savedQueries, toSavedQuery(data))
+	}
+	return savedQueries, nil
+}
+
+// Get returns the saved query with the given ID, or if there is none, the saved query with the given name.
+func (s *savedQueries) Get(viewName, idOrName string) (*SavedQuery, error) {
+	savedQueries, err := s.List(viewName)
+	if err != nil {
+		return nil, fmt.Errorf("unable to list saved queries: %w", err)
+	}
+	for _, savedQuery := range savedQueries {
+		if savedQuery.ID == idOrName {
+			return &savedQuery, nil
+		}
+	}
+	for _, savedQuery := range savedQueries {
+		if savedQuery.Name == idOrName {
+			return &savedQuery, nil
+		}
+	}
+
+	return nil, notFoundError{entityType: "saved query", key: idOrName}
+}
+
+func (s *savedQueries) Add(viewName string, savedQuery *SavedQuery) (*SavedQuery, error) {
+	defer s.lists.invalidate("saved queries", viewName)
+
+	if savedQuery == nil {
+		return nil, fmt.Errorf("saved query must not be nil")
+	}
+
+	var mutation struct {
+		CreateSavedQuery struct {
+			SavedQuery savedQueryData `graphql:"savedQuery"`
+		} `graphql:"createSavedQuery(input: { viewName: $viewName, name: $name, queryString: $queryString, start: $start, end: $end, isLive: $isLive, widgetType: $widgetType, options: $options, labels: $labels })"`
+	}
+
+	variables := savedQueryVariables(viewName, savedQuery)
+
+	err := s.client.Mutate(&mutation, variables)
+	if err != nil {
+		return nil, err
+	}
+
+	created := toSavedQuery(mutation.CreateSavedQuery.SavedQuery)
+	return &created, nil
+}
+
+func (s *savedQueries) Update(viewName string, savedQuery *SavedQuery) (*SavedQuery, error) {
+	defer s.lists.invalidate("saved queries", viewName)
+
+	if savedQuery == nil {
+		return nil, fmt.Errorf("saved query must not be nil")
+	}
+
+	if savedQuery.ID == "" {
+		return nil, fmt.Errorf("saved query must have non-empty id")
+	}
+
+	var mutation struct {
+		UpdateSavedQuery struct {
+			SavedQuery savedQueryData `graphql:"savedQuery"`
+		} `graphql:"updateSavedQuery(input: { viewName: $viewName, id: $id, name: $name, queryString: $queryString, start: $start, end: $end, isLive: $isLive, widgetType: $widgetType, options: $options, labels: $labels })"`
+	}
+
+	variables := savedQueryVariables(viewName, savedQuery)
+	variables["id"] = graphql.String(savedQuery.ID)
+
+	err := s.client.Mutate(&mutation, variables)
+	if err != nil {
+		return nil, err
+	}
+
+	updated := toSavedQuery(mutation.UpdateSavedQuery.SavedQuery)
+	return &updated, nil
+}
+
+func (s *savedQueries) Delete(viewName, id string) error {
+	defer s.lists.invalidate("saved queries", viewName)
+
+	var mutation struct {
+		DeleteSavedQuery bool `graphql:"deleteSavedQuery(input: { viewName: $viewName, id: $id })"`
+	}
+
+	variables := map[string]interface{}{
+		"viewName": graphql.String(viewName),
+		"id":       graphql.String(id),
+	}
+
+	return s.client.Mutate(&mutation, variables)
+}
+
+func savedQueryVariables(viewName string, savedQuery *SavedQuery) map[string]interface{} {
+	return map[string]interface{}{
+		"viewName":    graphql.String(viewName),
+		"name":        graphql.String(savedQuery.Name),
+		"queryString": graphql.String(savedQuery.QueryString),
+		"start":       optStringArg(savedQuery.Start),
+		"end":         optStringArg(savedQuery.End),
+		"isLive":      graphql.Boolean(savedQuery.IsLive),
+		"widgetType":  optStringArg(savedQuery.WidgetType),
+		"options":     optStringArg(savedQuery.Options),
+		"labels":      graphqlStringList(savedQuery.Labels),
+	}
+}
+
+func toSavedQuery(data savedQueryData) SavedQuery {
+	return SavedQuery{
+		ID:          data.ID,
+		Name:        data.Name,
+		QueryString: data.Query.QueryString,
+		Start:       data.Query.Start,
+		End:         data.Query.End,
+		IsLive:      data.Query.IsLive,
+		WidgetType:  data.WidgetType,
+		Labels:      data.Labels,
+	}
+}
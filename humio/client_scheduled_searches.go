@@ -0,0 +1,223 @@
+// Copyright © 2020 Humio Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package humio
+
+import (
+	"fmt"
+
+	graphql "github.com/cli/shurcooL-graphql"
+
+	humio "github.com/humio/cli/api"
+)
+
+// ScheduledSearch mirrors the shape of humio.Alert for scheduled searches, which github.com/humio/cli does not support.
+type ScheduledSearch struct {
+	ID                 string
+	Name               string
+	Description        string
+	QueryString        string
+	QueryStart         string
+	QueryEnd           string
+	TimeZone           string
+	Schedule           string
+	BackfillLimit      int
+	Enabled            bool
+	Actions            []string
+	Labels             []string
+	RunAsUserID        string
+	QueryOwnershipType string
+}
+
+type scheduledSearchData struct {
+	ID             string         `graphql:"id"`
+	Name           string         `graphql:"name"`
+	Description    string         `graphql:"description"`
+	QueryString    string         `graphql:"queryString"`
+	Start          string         `graphql:"start"`
+	End            string         `graphql:"end"`
+	TimeZone       string         `graphql:"timeZone"`
+	Schedule       string         `graphql:"schedule"`
+	BackfillLimit  int            `graphql:"backfillLimit"`
+	Enabled        bool           `graphql:"enabled"`
+	Actions        []string       `graphql:"actions"`
+	Labels         []string       `graphql:"labels"`
+	QueryOwnership queryOwnership `graphql:"queryOwnership"`
+	RunAsUser      *struct {
+		ID string `graphql:"id"`
+	} `graphql:"runAsUser"`
+}
+
+type scheduledSearches struct {
+	client *humio.Client
+	lists  *listCache
+}
+
+func newScheduledSearches(client *humio.Client) *scheduledSearches {
+	return &scheduledSearches{client: client}
+}
+
+// List lists the scheduled searches in the view, reusing the list of an earlier call if it is cached.
+func (s *scheduledSearches) List(viewName string) ([]ScheduledSearch, error) {
+	return cachedList(s.lists, "scheduled searches", viewName, s.list)
+}
+
+func (s *scheduledSearches) list(viewName string) ([]ScheduledSearch, error) {
+	var query struct {
+		SearchDomain struct {
+			ScheduledSearches []scheduledSearchData `graphql:"scheduledSearches"`
+		} `graphql:"searchDomain(name: $viewName)"`
+	}
+
+	variables := map[string]interface{}{
+		"viewName": graphql.String(viewName),
+	}
+
+	err := s.client.Query(&query, variables)
+	if err != nil {
+		return nil, err
+	}
+
+	var searches []ScheduledSearch
+	for _, data := range query.SearchDomain.ScheduledSearches {
+		searches = append(searches, toScheduledSearch(data))
+	}
+	return searches, nil
+}
+
+func (s *scheduledSearches) Get(viewName, name string) (*ScheduledSearch, error) {
+	searches, err := s.List(viewName)
+	if err != nil {
+		return nil, fmt.Errorf("unable to list scheduled searches: %w", err)
+	}
+	for _, search := range searches {
+		if search.Name == name {
+			return &search, nil
+		}
+	}
+
+	return nil, notFoundError{entityType: "scheduled search", key: name}
+}
+
+func (s *scheduledSearches) Add(viewName string, search *ScheduledSearch) (*ScheduledSearch, error) {
+	defer s.lists.invalidate("scheduled searches", viewName)
+
+	if search == nil {
+		return nil, fmt.Errorf("scheduled search must not be nil")
+	}
+
+	var mutation struct {
+		ScheduledSearch scheduledSearchData `graphql:"createScheduledSearch(input: { viewName: $viewName, name: $name, description: $description, queryString: $queryString, queryStart: $queryStart, queryEnd: $queryEnd, schedule: $schedule, timeZone: $timeZone, backfillLimit: $backfillLimit, enabled: $enabled, actions: $actions, labels: $labels, runAsUserId: $runAsUserId, queryOwnershipType: $queryOwnershipType })"`
+	}
+
+	variables := scheduledSearchVariables(viewName, search)
+
+	err := s.client.Mutate(&mutation, variables)
+	if err != nil {
+		return nil, err
+	}
+
+	created := toScheduledSearch(mutation.ScheduledSearch)
+	return &created, nil
+}
+
+func (s *scheduledSearches) Update(viewName string, search *ScheduledSearch) (*ScheduledSearch, error) {
+	defer s.lists.invalidate("scheduled searches", viewName)
+
+	if search == nil {
+		return nil, fmt.Errorf("scheduled search must not be nil")
+	}
+
+	if search.ID == "" {
+		return nil, fmt.Errorf("scheduled search must have non-empty id")
+	}
+
+	var mutation struct {
+		ScheduledSearch scheduledSearchData `graphql:"updateScheduledSearch(input: { viewName: $viewName, id: $id, name: $name, description: $description, queryString: $queryString, queryStart: $queryStart, queryEnd: $queryEnd, schedule: $schedule, timeZone: $timeZone, backfillLimit: $backfillLimit, enabled: $enabled, actions: $actions, labels: $labels, runAsUserId: $runAsUserId, queryOwnershipType: $queryOwnershipType })"`
+	}
+
+	variables := scheduledSearchVariables(viewName, search)
+	variables["id"] = graphql.String(search.ID)
+
+	err := s.client.Mutate(&mutation, variables)
+	if err != nil {
+		return nil, err
+	}
+
+	updated := toScheduledSearch(mutation.ScheduledSearch)
+	return &updated, nil
+}
+
+func (s *scheduledSearches) Delete(viewName, name string) error {
+	defer s.lists.invalidate("scheduled searches", viewName)
+
+	search, err := s.Get(viewName, name)
+	if err != nil {
+		return err
+	}
+
+	var mutation struct {
+		DeleteScheduledSearch bool `graphql:"deleteScheduledSearch(input: { viewName: $viewName, id: $id })"`
+	}
+
+	variables := map[string]interface{}{
+		"viewName": graphql.String(viewName),
+		"id":       graphql.String(search.ID),
+	}
+
+	return s.client.Mutate(&mutation, variables)
+}
+
+func scheduledSearchVariables(viewName string, search *ScheduledSearch) map[string]interface{} {
+	return map[string]interface{}{
+		"viewName":           graphql.String(viewName),
+		"name":               graphql.String(search.Name),
+		"description":        optStringArg(search.Description),
+		"queryString":        graphql.String(search.QueryString),
+		"queryStart":         graphql.String(search.QueryStart),
+		"queryEnd":           graphql.String(search.QueryEnd),
+		"schedule":           graphql.String(search.Schedule),
+		"timeZone":           graphql.String(search.TimeZone),
+		"backfillLimit":      graphql.Int(search.BackfillLimit),
+		"enabled":            graphql.Boolean(search.Enabled),
+		"actions":            graphqlStringList(search.Actions),
+		"labels":             graphqlStringList(search.Labels),
+		"runAsUserId":        optStringArg(search.RunAsUserID),
+		"queryOwnershipType": optQueryOwnershipTypeArg(search.QueryOwnershipType),
+	}
+}
+
+func toScheduledSearch(data scheduledSearchData) ScheduledSearch {
+	var runAsUserID string
+	if data.RunAsUser != nil {
+		runAsUserID = data.RunAsUser.ID
+	}
+
+	return ScheduledSearch{
+		ID:                 data.ID,
+		Name:               data.Name,
+		Description:        data.Description,
+		QueryString:        data.QueryString,
+		QueryStart:         data.Start,
+		QueryEnd:           data.End,
+		TimeZone:           data.TimeZone,
+		Schedule:           data.Schedule,
+		BackfillLimit:      data.BackfillLimit,
+		Enabled:            data.Enabled,
+		Actions:            data.Actions,
+		Labels:             data.Labels,
+		RunAsUserID:        runAsUserID,
+		QueryOwnershipType: queryOwnershipTypeFromTypename(string(data.QueryOwnership.Typename)),
+	}
+}
@@ -0,0 +1,302 @@
+// Copyright © 2020 Humio Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package humio
+
+import (
+	"fmt"
+
+	graphql "github.com/cli/shurcooL-graphql"
+
+	humio "github.com/humio/cli/api"
+)
+
+// The GraphQL typenames of the API tokens that can be managed with this provider.
+const (
+	TokenTypeView         = "ViewPermissionsToken"
+	TokenTypeOrganization = "OrganizationPermissionsToken"
+	TokenTypeSystem       = "SystemPermissionsToken"
+)
+
+// tokensPageSize is the number of tokens fetched per request when listing tokens.
+const tokensPageSize = 100
+
+// Token is an API token with a set of view, organization or system permissions. The token value itself is only
+// returned when the token is created or rotated.
+type Token struct {
+	ID          string
+	Type        string
+	Name        string
+	ExpireAt    int64
+	IPFilterID  string
+	Views       []string
+	Permissions []string
+}
+
+type tokenData struct {
+	ID         string `graphql:"id"`
+	Typename   string `graphql:"__typename"`
+	Name       string `graphql:"name"`
+	ExpireAt   *int64 `graphql:"expireAt"`
+	IPFilterV2 *struct {
+		ID string `graphql:"id"`
+	} `graphql:"ipFilterV2"`
+	ViewPermissionsToken struct {
+		Views []struct {
+			Name string `graphql:"name"`
+		} `graphql:"views"`
+		Permissions []string `graphql:"permissions"`
+	} `graphql:"... on ViewPermissionsToken"`
+	OrganizationPermissionsToken struct {
+		Permissions []string `graphql:"permissions"`
+	} `graphql:"... on OrganizationPermissionsToken"`
+	SystemPermissionsToken struct {
+		Permissions []string `graphql:"permissions"`
+	} `graphql:"... on SystemPermissionsToken"`
+}
+
+type tokens struct {
+	client *humio.Client
+}
+
+func newTokens(client *humio.Client) *tokens {
+	return &tokens{client: client}
+}
+
+// List returns the tokens of the given type whose name matches searchFilter.
+func (t *tokens) List(tokenType, searchFilter string) ([]Token, error) {
+	var query struct {
+		Tokens struct {
+			Results []tokenData `graphql:"results"`
+		} `graphql:"tokens(searchFilter: $searchFilter, skip: $skip, limit: $limit)"`
+	}
+
+	var tokens []Token
+	for skip := 0; ; skip += tokensPageSize {
+		variables := map[string]interface{}{
+			"searchFilter": graphql.String(searchFilter),
+			"skip":         graphql.Int(skip),
+			"limit":        graphql.Int(tokensPageSize),
+		}
+
+		err := t.client.Query(&query, variables)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, data := range query.Tokens.Results {
+			if data.Typename == tokenType {
+				tokens = append(tokens, toToken(data))
+			}
+		}
+		if len(query.Tokens.Results) < tokensPageSize {
+			return tokens, nil
+		}
+	}
+}
+
+// Get returns the token with the given ID. The name is used to narrow down the search, and may be empty.
+func (t *tokens) Get(tokenType, id, name string) (*Token, error) {
+	tokens, err := t.List(tokenType, name)
+	if err != nil {
+		return nil, fmt.Errorf("unable to list tokens: %w", err)
+	}
+	for _, token := range tokens {
+		if token.ID == id {
+			return &token, nil
+		}
+	}
+
+	return nil, notFoundError{entityType: "token", key: id}
+}
+
+// GetByName returns the token with the given name.
+func (t *tokens) GetByName(tokenType, name string) (*Token, error) {
+	tokens, err := t.List(tokenType, name)
+	if err != nil {
+		return nil, fmt.Errorf("unable to list tokens: %w", err)
+	}
+	for _, token := range tokens {
+		if token.Name == name {
+			return &token, nil
+		}
+	}
+
+	return nil, notFoundError{entityType: "token", key: name}
+}
+
+// Add creates the token and returns the token value.
+func (t *tokens) Add(token *Token) (string, error) {
+	if token == nil {
+		return "", fmt.Errorf("token must not be nil")
+	}
+
+	variables := map[string]interface{}{
+		"name":       graphql.String(token.Name),
+		"expireAt":   (*Long)(nil),
+		"ipFilterId": optStringArg(token.IPFilterID),
+	}
+	if token.ExpireAt > 0 {
+		expireAt := Long(token.ExpireAt)
+		variables["expireAt"] = &expireAt
+	}
+
+	switch token.Type {
+	case TokenTypeView:
+		var mutation struct {
+			Token string `graphql:"createViewPermissionsToken(input: { name: $name, expireAt: $expireAt, ipFilterId: $ipFilterId, viewIds: $viewIds, permissions: $permissions })"`
+		}
+		viewIDs := make([]graphql.String, len(token.Views))
+		for i, view := range token.Views {
+			viewID, err := searchDomainID(t.client, view)
+			if err != nil {
+				return "", fmt.Errorf("unable to find view %s: %w", view, err)
+			}
+			viewIDs[i] = graphql.String(viewID)
+		}
+		variables["viewIds"] = viewIDs
+		variables["permissions"] = viewPermissions(token.Permissions)
+		err := t.client.Mutate(&mutation, variables)
+		return mutation.Token, err
+	case TokenTypeOrganization:
+		var mutation struct {
+			Token string `graphql:"createOrganizationPermissionsToken(input: { name: $name, expireAt: $expireAt, ipFilterId: $ipFilterId, permissions: $permissions })"`
+		}
+		variables["permissions"] = organizationPermissions(token.Permissions)
+		err := t.client.Mutate(&mutation, variables)
+		return mutation.Token, err
+	case TokenTypeSystem:
+		var mutation struct {
+			Token string `graphql:"createSystemPermissionsToken(input: { name: $name, expireAt: $expireAt, ipFilterId: $ipFilterId, permissions: $permissions })"`
+		}
+		variables["permissions"] = systemPermissions(token.Permissions)
+		err := t.client.Mutate(&mutation, variables)
+		return mutation.Token, err
+	}
+	return "", fmt.Errorf("unsupported token type: %s", token.Type)
+}
+
+// UpdatePermissions replaces the permissions of the token.
+func (t *tokens) UpdatePermissions(token *Token) error {
+	if token == nil {
+		return fmt.Errorf("token must not be nil")
+	}
+
+	if token.ID == "" {
+		return fmt.Errorf("token must have non-empty id")
+	}
+
+	variables := map[string]interface{}{
+		"id": graphql.String(token.ID),
+	}
+
+	switch token.Type {
+	case TokenTypeView:
+		var mutation struct {
+			Token string `graphql:"updateViewPermissionsTokenPermissions(input: { id: $id, permissions: $permissions })"`
+		}
+		variables["permissions"] = viewPermissions(token.Permissions)
+		return t.client.Mutate(&mutation, variables)
+	case TokenTypeOrganization:
+		var mutation struct {
+			Token string `graphql:"updateOrganizationPermissionsTokenPermissions(input: { id: $id, permissions: $permissions })"`
+		}
+		variables["permissions"] = organizationPermissions(token.Permissions)
+		return t.client.Mutate(&mutation, variables)
+	case TokenTypeSystem:
+		var mutation struct {
+			Token string `graphql:"updateSystemPermissionsTokenPermissions(input: { id: $id, permissions: $permissions })"`
+		}
+		variables["permissions"] = systemPermissions(token.Permissions)
+		return t.client.Mutate(&mutation, variables)
+	}
+	return fmt.Errorf("unsupported token type: %s", token.Type)
+}
+
+// Rotate replaces the token value, and returns the new value.
+func (t *tokens) Rotate(id string) (string, error) {
+	var mutation struct {
+		Token string `graphql:"rotateToken(input: { id: $id })"`
+	}
+
+	variables := map[string]interface{}{
+		"id": graphql.String(id),
+	}
+
+	err := t.client.Mutate(&mutation, variables)
+	return mutation.Token, err
+}
+
+func (t *tokens) Delete(id string) error {
+	var mutation struct {
+		DeleteToken bool `graphql:"deleteToken(input: { id: $id })"`
+	}
+
+	variables := map[string]interface{}{
+		"id": graphql.String(id),
+	}
+
+	return t.client.Mutate(&mutation, variables)
+}
+
+func viewPermissions(permissions []string) []Permission {
+	list := make([]Permission, len(permissions))
+	for i, p := range permissions {
+		list[i] = Permission(p)
+	}
+	return list
+}
+
+func organizationPermissions(permissions []string) []OrganizationPermission {
+	list := make([]OrganizationPermission, len(permissions))
+	for i, p := range permissions {
+		list[i] = OrganizationPermission(p)
+	}
+	return list
+}
+
+func systemPermissions(permissions []string) []SystemPermission {
+	list := make([]SystemPermission, len(permissions))
+	for i, p := range permissions {
+		list[i] = SystemPermission(p)
+	}
+	return list
+}
+
+func toToken(data tokenData) Token {
+	token := Token{
+		ID:   data.ID,
+		Type: data.Typename,
+		Name: data.Name,
+	}
+	if data.ExpireAt != nil {
+		token.ExpireAt = *data.ExpireAt
+	}
+	if data.IPFilterV2 != nil {
+		token.IPFilterID = data.IPFilterV2.ID
+	}
+
+	switch data.Typename {
+	case TokenTypeView:
+		for _, view := range data.ViewPermissionsToken.Views {
+			token.Views = append(token.Views, view.Name)
+		}
+		token.Permissions = data.ViewPermissionsToken.Permissions
+	case TokenTypeOrganization:
+		token.Permissions = data.OrganizationPermissionsToken.Permissions
+	case TokenTypeSystem:
+		token.Permissions = data.SystemPermissionsToken.Permissions
+	}
+	return token
+}
@@ -0,0 +1,206 @@
+// Copyright © 2020 Humio Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package humio
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+
+	humio "github.com/humio/cli/api"
+)
+
+// connectionConfig configures how the provider connects to LogScale: TLS, proxy and additional request headers.
+type connectionConfig struct {
+	tlsConfig *tls.Config
+	// proxy returns the proxy requests are sent through, see http.Transport.
+	proxy   func(*http.Request) (*url.URL, error)
+	headers map[string]string
+}
+
+func connectionConfigFromResourceData(r *schema.ResourceData) (connectionConfig, error) {
+	tlsConfig := &tls.Config{
+		// #nosec G402 -- only when explicitly asked for, e.g. for lab clusters with self-signed certificates.
+		InsecureSkipVerify: r.Get("insecure_skip_verify").(bool),
+	}
+
+	rootCAs, err := certificatePoolFromResourceData(r)
+	if err != nil {
+		return connectionConfig{}, err
+	}
+	tlsConfig.RootCAs = rootCAs
+
+	certificate, err := clientCertificateFromResourceData(r)
+	if err != nil {
+		return connectionConfig{}, err
+	}
+	if certificate != nil {
+		tlsConfig.Certificates = []tls.Certificate{*certificate}
+	}
+
+	proxy := http.ProxyFromEnvironment
+	if v, ok := r.GetOk("proxy_url"); ok {
+		proxyURL, err := url.Parse(v.(string))
+		if err != nil {
+			return connectionConfig{}, fmt.Errorf("invalid proxy_url: %w", err)
+		}
+		proxy = http.ProxyURL(proxyURL)
+	}
+
+	headers := make(map[string]string)
+	if v, ok := r.GetOk("headers"); ok {
+		for name, value := range v.(tfMap) {
+			headers[name] = value.(string)
+		}
+	} else if v := os.Getenv("HUMIO_HEADERS"); v != "" {
+		headers, err = parseHeaders(v)
+		if err != nil {
+			return connectionConfig{}, fmt.Errorf("invalid HUMIO_HEADERS: %w", err)
+		}
+	}
+
+	return connectionConfig{
+		tlsConfig: tlsConfig,
+		proxy:     proxy,
+		headers:   headers,
+	}, nil
+}
+
+// transport returns the transport connecting to LogScale. It uses the defaults of github.com/humio/cli, except for
+// TLS and the proxy, which github.com/humio/cli does not support configuring to this extent.
+func (c connectionConfig) transport(config humio.Config) http.RoundTripper {
+	transport := humio.NewHttpTransport(config)
+	transport.TLSClientConfig = c.tlsConfig
+	transport.Proxy = c.proxy
+	if len(c.headers) == 0 {
+		return transport
+	}
+	return &headerTransport{base: transport, headers: c.headers}
+}
+
+// tokenClient returns the client requesting OAuth2 tokens. It connects with the same TLS settings and proxy as the
+// client of LogScale, but does not send the additional headers, which are meant for LogScale.
+func (c connectionConfig) tokenClient() *http.Client {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = c.tlsConfig
+	transport.Proxy = c.proxy
+	return &http.Client{Transport: transport, Timeout: 30 * time.Second}
+}
+
+// certificatePoolFromResourceData returns the pool of the CA certificates given in ca_certificate_pem and
+// ca_certificate_file, or nil to use the certificates of the system.
+func certificatePoolFromResourceData(r *schema.ResourceData) (*x509.CertPool, error) {
+	pemCA, pemOK := r.GetOk("ca_certificate_pem")
+	fileCA, fileOK := r.GetOk("ca_certificate_file")
+	if !pemOK && !fileOK {
+		return nil, nil
+	}
+
+	pool := x509.NewCertPool()
+	if pemOK {
+		if err := appendCertificates(pool, "ca_certificate_pem", []byte(pemCA.(string))); err != nil {
+			return nil, err
+		}
+	}
+	if fileOK {
+		content, err := os.ReadFile(fileCA.(string))
+		if err != nil {
+			return nil, fmt.Errorf("could not read ca_certificate_file: %w", err)
+		}
+		if err := appendCertificates(pool, "ca_certificate_file", content); err != nil {
+			return nil, err
+		}
+	}
+	return pool, nil
+}
+
+// clientCertificateFromResourceData returns the client certificate given either inline or as files, or nil if there
+// is none.
+func clientCertificateFromResourceData(r *schema.ResourceData) (*tls.Certificate, error) {
+	certificatePEM, err := pemFromResourceData(r, "client_certificate", "client_certificate_file")
+	if err != nil {
+		return nil, err
+	}
+	keyPEM, err := pemFromResourceData(r, "client_key", "client_key_file")
+	if err != nil {
+		return nil, err
+	}
+	if certificatePEM == nil && keyPEM == nil {
+		return nil, nil
+	}
+	if certificatePEM == nil || keyPEM == nil {
+		return nil, fmt.Errorf("a client certificate needs both client_certificate or client_certificate_file, and client_key or client_key_file")
+	}
+
+	certificate, err := tls.X509KeyPair(certificatePEM, keyPEM)
+	if err != nil {
+		return nil, fmt.Errorf("invalid client certificate: %w", err)
+	}
+	return &certificate, nil
+}
+
+// pemFromResourceData returns the PEM given either inline in one attribute or as a file in the other, or nil if
+// neither is set.
+func pemFromResourceData(r *schema.ResourceData, inlineAttribute, fileAttribute string) ([]byte, error) {
+	attribute, err := chooseAttribute(r, inlineAttribute, fileAttribute)
+	switch {
+	case err != nil:
+		return nil, err
+	case attribute == inlineAttribute:
+		return []byte(r.Get(inlineAttribute).(string)), nil
+	case attribute == fileAttribute:
+		content, err := os.ReadFile(r.Get(fileAttribute).(string))
+		if err != nil {
+			return nil, fmt.Errorf("could not read %s: %w", fileAttribute, err)
+		}
+		return content, nil
+	}
+	return nil, nil
+}
+
+// parseHeaders parses headers given as a comma separated list of name=value pairs.
+func parseHeaders(s string) (map[string]string, error) {
+	headers := make(map[string]string)
+	for _, pair := range strings.Split(s, ",") {
+		name, value, ok := strings.Cut(pair, "=")
+		name = strings.TrimSpace(name)
+		if !ok || name == "" {
+			return nil, fmt.Errorf("%q is not of the form name=value", pair)
+		}
+		headers[name] = strings.TrimSpace(value)
+	}
+	return headers, nil
+}
+
+// headerTransport adds headers to every request, replacing headers of the same name.
+type headerTransport struct {
+	base    http.RoundTripper
+	headers map[string]string
+}
+
+func (t *headerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	r := req.Clone(req.Context())
+	for name, value := range t.headers {
+		r.Header.Set(name, value)
+	}
+	return t.base.RoundTrip(r)
+}
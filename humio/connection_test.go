@@ -0,0 +1,403 @@
+// Copyright © 2020 Humio Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package humio
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+)
+
+// newTLSServer returns a server answering queries with the certificate, requiring client certificates signed by
+// clientCA if it is not nil. The handler records the headers of the requests.
+func newTLSServer(t *testing.T, certificate *testCertificate, clientCA *testCertificate) *httptest.Server {
+	t.Helper()
+	server := httptest.NewUnstartedServer(viewerHandler(nil))
+	server.TLS = &tls.Config{
+		Certificates: []tls.Certificate{{
+			Certificate: [][]byte{certificate.certificate.Raw},
+			PrivateKey:  certificate.key,
+		}},
+	}
+	if clientCA != nil {
+		pool := x509.NewCertPool()
+		pool.AddCert(clientCA.certificate)
+		server.TLS.ClientCAs = pool
+		server.TLS.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+	server.StartTLS()
+	t.Cleanup(server.Close)
+	return server
+}
+
+// viewerHandler answers the viewer query, sending the request to requests if it is not nil.
+func viewerHandler(requests chan<- *http.Request) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if requests != nil {
+			requests <- r
+		}
+		_, _ = io.WriteString(w, `{"data":{"viewer":{"username":"terraform"}}}`)
+	}
+}
+
+func writeTestFile(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// queryTestProvider configures the provider and sends a query with it.
+func queryTestProvider(t *testing.T, attributes map[string]cty.Value) (diag.Diagnostics, error) {
+	t.Helper()
+	attributes["max_retries"] = cty.NumberIntVal(0)
+	meta, diags := configureTestProvider(t, attributes)
+	if diags.HasError() {
+		return diags, nil
+	}
+	return diags, meta.client.Query(&testViewerQuery{}, nil)
+}
+
+func TestProviderCACertificates(t *testing.T) {
+	t.Setenv("HUMIO_API_TOKEN", "token")
+	otherCA := newTestCertificate(t, "other CA", nil)
+	ca := newTestCertificate(t, "CA", nil)
+	server := newTLSServer(t, newTestCertificate(t, "127.0.0.1", ca), nil)
+	// The CA of the server is not the first in the bundle.
+	bundle := otherCA.certPEM + ca.certPEM
+
+	tests := []struct {
+		name       string
+		attributes map[string]cty.Value
+		wantError  bool
+	}{
+		{
+			name:      "system CAs",
+			wantError: true,
+		},
+		{
+			name:       "pem bundle",
+			attributes: map[string]cty.Value{"ca_certificate_pem": cty.StringVal(bundle)},
+		},
+		{
+			name:       "file bundle",
+			attributes: map[string]cty.Value{"ca_certificate_file": cty.StringVal(writeTestFile(t, "ca.pem", bundle))},
+		},
+		{
+			name: "pem and file",
+			attributes: map[string]cty.Value{
+				"ca_certificate_pem":  cty.StringVal(otherCA.certPEM),
+				"ca_certificate_file": cty.StringVal(writeTestFile(t, "ca.pem", ca.certPEM)),
+			},
+		},
+		{
+			name:       "other CA",
+			attributes: map[string]cty.Value{"ca_certificate_pem": cty.StringVal(otherCA.certPEM)},
+			wantError:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			attributes := map[string]cty.Value{"addr": cty.StringVal(server.URL)}
+			for name, value := range tt.attributes {
+				attributes[name] = value
+			}
+			diags, err := queryTestProvider(t, attributes)
+			if diags.HasError() {
+				t.Fatalf("unexpected diagnostics %v", diags)
+			}
+			if (err != nil) != tt.wantError {
+				t.Errorf("got error %v, want error %v", err, tt.wantError)
+			}
+		})
+	}
+}
+
+func TestProviderInvalidCACertificates(t *testing.T) {
+	t.Setenv("HUMIO_API_TOKEN", "token")
+	ca := newTestCertificate(t, "CA", nil)
+
+	tests := []struct {
+		name      string
+		attribute string
+		value     string
+		want      string
+	}{
+		{
+			name:      "no pem",
+			attribute: "ca_certificate_pem",
+			value:     "not a certificate",
+			want:      "ca_certificate_pem specified but no pem was found",
+		},
+		{
+			name:      "key in bundle",
+			attribute: "ca_certificate_pem",
+			value:     ca.certPEM + ca.keyPEM,
+			want:      "ca_certificate_pem contains a PEM block of type EC PRIVATE KEY, but only certificates are expected",
+		},
+		{
+			name:      "invalid certificate in bundle",
+			attribute: "ca_certificate_file",
+			value:     writeTestFile(t, "ca.pem", ca.certPEM+"-----BEGIN CERTIFICATE-----\naW52YWxpZA==\n-----END CERTIFICATE-----\n"),
+			want:      "ca_certificate_file contains an invalid certificate",
+		},
+		{
+			name:      "missing file",
+			attribute: "ca_certificate_file",
+			value:     filepath.Join(t.TempDir(), "missing.pem"),
+			want:      "could not read ca_certificate_file",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, diags := configureTestProvider(t, map[string]cty.Value{tt.attribute: cty.StringVal(tt.value)})
+			if !diags.HasError() || !strings.Contains(diags[0].Summary, tt.want) {
+				t.Errorf("got diagnostics %v, want error containing %q", diags, tt.want)
+			}
+		})
+	}
+}
+
+func TestProviderClientCertificate(t *testing.T) {
+	t.Setenv("HUMIO_API_TOKEN", "token")
+	ca := newTestCertificate(t, "CA", nil)
+	client := newTestCertificate(t, "terraform", ca)
+	server := newTLSServer(t, newTestCertificate(t, "127.0.0.1", ca), ca)
+
+	tests := []struct {
+		name       string
+		env        map[string]string
+		attributes map[string]cty.Value
+		wantError  bool
+	}{
+		{
+			name:      "no client certificate",
+			wantError: true,
+		},
+		{
+			name: "inline",
+			attributes: map[string]cty.Value{
+				"client_certificate": cty.StringVal(client.certPEM),
+				"client_key":         cty.StringVal(client.keyPEM),
+			},
+		},
+		{
+			name: "files",
+			attributes: map[string]cty.Value{
+				"client_certificate_file": cty.StringVal(writeTestFile(t, "client.pem", client.certPEM)),
+				"client_key_file":         cty.StringVal(writeTestFile(t, "client.key", client.keyPEM)),
+			},
+		},
+		{
+			name: "environment",
+			env: map[string]string{
+				"HUMIO_CLIENT_CERTIFICATE_FILE": writeTestFile(t, "client.pem", client.certPEM),
+				"HUMIO_CLIENT_KEY":              client.keyPEM,
+			},
+		},
+		{
+			name: "configuration takes precedence over environment",
+			env: map[string]string{
+				"HUMIO_CLIENT_CERTIFICATE": "invalid",
+			},
+			attributes: map[string]cty.Value{
+				"client_certificate_file": cty.StringVal(writeTestFile(t, "client.pem", client.certPEM)),
+				"client_key":              cty.StringVal(client.keyPEM),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for name, value := range tt.env {
+				t.Setenv(name, value)
+			}
+			attributes := map[string]cty.Value{
+				"addr":               cty.StringVal(server.URL),
+				"ca_certificate_pem": cty.StringVal(ca.certPEM),
+			}
+			for name, value := range tt.attributes {
+				attributes[name] = value
+			}
+			diags, err := queryTestProvider(t, attributes)
+			if diags.HasError() {
+				t.Fatalf("unexpected diagnostics %v", diags)
+			}
+			if (err != nil) != tt.wantError {
+				t.Errorf("got error %v, want error %v", err, tt.wantError)
+			}
+		})
+	}
+}
+
+func TestProviderInvalidClientCertificate(t *testing.T) {
+	t.Setenv("HUMIO_API_TOKEN", "token")
+	ca := newTestCertificate(t, "CA", nil)
+	client := newTestCertificate(t, "terraform", ca)
+
+	tests := []struct {
+		name       string
+		attributes map[string]cty.Value
+		want       string
+	}{
+		{
+			name:       "certificate without key",
+			attributes: map[string]cty.Value{"client_certificate": cty.StringVal(client.certPEM)},
+			want:       "a client certificate needs both",
+		},
+		{
+			name: "inline and file",
+			attributes: map[string]cty.Value{
+				"client_certificate":      cty.StringVal(client.certPEM),
+				"client_certificate_file": cty.StringVal(writeTestFile(t, "client.pem", client.certPEM)),
+				"client_key":              cty.StringVal(client.keyPEM),
+			},
+			want: "only one of",
+		},
+		{
+			name: "key of another certificate",
+			attributes: map[string]cty.Value{
+				"client_certificate": cty.StringVal(client.certPEM),
+				"client_key":         cty.StringVal(ca.keyPEM),
+			},
+			want: "invalid client certificate",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, diags := configureTestProvider(t, tt.attributes)
+			if !diags.HasError() || !strings.Contains(diags[0].Summary, tt.want) {
+				t.Errorf("got diagnostics %v, want error containing %q", diags, tt.want)
+			}
+		})
+	}
+}
+
+func TestProviderInsecureSkipVerify(t *testing.T) {
+	t.Setenv("HUMIO_API_TOKEN", "token")
+	server := newTLSServer(t, newTestCertificate(t, "127.0.0.1", newTestCertificate(t, "CA", nil)), nil)
+
+	diags, err := queryTestProvider(t, map[string]cty.Value{
+		"addr":                 cty.StringVal(server.URL),
+		"insecure_skip_verify": cty.True,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(diags) != 1 || diags[0].Severity != diag.Warning {
+		t.Errorf("got diagnostics %v, want a warning", diags)
+	}
+
+	t.Setenv("HUMIO_INSECURE_SKIP_VERIFY", "true")
+	if _, err := queryTestProvider(t, map[string]cty.Value{"addr": cty.StringVal(server.URL)}); err != nil {
+		t.Errorf("HUMIO_INSECURE_SKIP_VERIFY: %s", err)
+	}
+}
+
+func TestProviderProxy(t *testing.T) {
+	t.Setenv("HUMIO_API_TOKEN", "token")
+	requests := make(chan *http.Request, 1)
+	proxy := httptest.NewServer(viewerHandler(requests))
+	defer proxy.Close()
+
+	_, err := queryTestProvider(t, map[string]cty.Value{
+		"addr":      cty.StringVal("http://logscale.example.com/"),
+		"proxy_url": cty.StringVal(proxy.URL),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := (<-requests).URL.String(); got != "http://logscale.example.com/graphql" {
+		t.Errorf("proxy got request for %s, want http://logscale.example.com/graphql", got)
+	}
+}
+
+func TestProviderHeaders(t *testing.T) {
+	t.Setenv("HUMIO_API_TOKEN", "token")
+	requests := make(chan *http.Request, 1)
+	server := httptest.NewServer(viewerHandler(requests))
+	defer server.Close()
+
+	tests := []struct {
+		name    string
+		env     string
+		headers cty.Value
+		want    map[string]string
+	}{
+		{
+			name:    "configuration",
+			env:     "X-Ignored=value",
+			headers: cty.MapVal(map[string]cty.Value{"X-Tenant": cty.StringVal("platform"), "User-Agent": cty.StringVal("terraform")}),
+			want:    map[string]string{"X-Tenant": "platform", "User-Agent": "terraform", "X-Ignored": ""},
+		},
+		{
+			name:    "environment",
+			env:     "X-Tenant=platform, X-Team=observability",
+			headers: cty.NullVal(cty.Map(cty.String)),
+			want:    map[string]string{"X-Tenant": "platform", "X-Team": "observability"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("HUMIO_HEADERS", tt.env)
+			_, err := queryTestProvider(t, map[string]cty.Value{
+				"addr":    cty.StringVal(server.URL),
+				"headers": tt.headers,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			request := <-requests
+			for name, want := range tt.want {
+				if got := request.Header.Get(name); got != want {
+					t.Errorf("%s = %q, want %q", name, got, want)
+				}
+			}
+			if got := request.Header.Get("Authorization"); got != "Bearer token" {
+				t.Errorf("Authorization = %q, want %q", got, "Bearer token")
+			}
+		})
+	}
+}
+
+func TestParseHeaders(t *testing.T) {
+	got, err := parseHeaders("X-Tenant=platform, X-Empty=,X-Equals=a=b")
+	want := map[string]string{"X-Tenant": "platform", "X-Empty": "", "X-Equals": "a=b"}
+	if err != nil || !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, %v, want %v", got, err, want)
+	}
+
+	for _, invalid := range []string{"X-Tenant", "=value", "X-Tenant=platform,"} {
+		if _, err := parseHeaders(invalid); err == nil {
+			t.Errorf("parseHeaders(%q): expected error", invalid)
+		}
+	}
+}
@@ -0,0 +1,92 @@
+// Copyright © 2020 Humio Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package humio
+
+import (
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// dataSourceSchemaFromResourceSchema returns a copy of a resource schema where every attribute is computed, except
+// the given attributes which are required to look up the object.
+func dataSourceSchemaFromResourceSchema(rs map[string]*schema.Schema, required ...string) map[string]*schema.Schema {
+	ds := make(map[string]*schema.Schema, len(rs))
+	for k, v := range rs {
+		ds[k] = computedSchema(v)
+	}
+	for _, k := range required {
+		ds[k].Computed = false
+		ds[k].Required = true
+	}
+	return ds
+}
+
+func computedSchema(s *schema.Schema) *schema.Schema {
+	c := &schema.Schema{
+		Type:        s.Type,
+		Description: s.Description,
+		Computed:    true,
+		Sensitive:   s.Sensitive,
+	}
+	switch s.Type {
+	case schema.TypeList, schema.TypeSet, schema.TypeMap:
+		switch elem := s.Elem.(type) {
+		case *schema.Resource:
+			c.Elem = &schema.Resource{Schema: dataSourceSchemaFromResourceSchema(elem.Schema)}
+		case *schema.Schema:
+			c.Elem = &schema.Schema{Type: elem.Type}
+		}
+	}
+	return c
+}
+
+// dataSourceListSchema returns the schema of a plural data source, listing objects with the given element schema.
+func dataSourceListSchema(listKey string, elem map[string]*schema.Schema, repositoryScoped bool) map[string]*schema.Schema {
+	s := map[string]*schema.Schema{
+		"name_regex": {
+			Type:             schema.TypeString,
+			Optional:         true,
+			ValidateDiagFunc: validation.ToDiagFunc(validation.StringIsValidRegExp),
+		},
+		listKey: {
+			Type:     schema.TypeList,
+			Computed: true,
+			Elem:     &schema.Resource{Schema: elem},
+		},
+	}
+	if repositoryScoped {
+		s["repository"] = &schema.Schema{
+			Type:     schema.TypeString,
+			Required: true,
+		}
+	}
+	return s
+}
+
+// flattenToMap runs one of the resourceDataFrom* flatteners against a scratch ResourceData built from the given
+// schema, and returns the result as a map that can be used as an element in a plural data source.
+func flattenToMap(s map[string]*schema.Schema, flatten func(d *schema.ResourceData) diag.Diagnostics) (tfMap, diag.Diagnostics) {
+	d := (&schema.Resource{Schema: s}).Data(nil)
+	if diags := flatten(d); diags.HasError() {
+		return nil, diags
+	}
+
+	m := tfMap{}
+	for k := range s {
+		m[k] = d.Get(k)
+	}
+	return m, nil
+}
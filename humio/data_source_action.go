@@ -0,0 +1,101 @@
+// Copyright © 2020 Humio Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package humio
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	humio "github.com/humio/cli/api"
+)
+
+func dataSourceAction() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceActionRead,
+		Schema:      dataSourceSchemaFromResourceSchema(actionDataSourceSchema(), "repository", "name"),
+	}
+}
+
+func dataSourceActionRead(_ context.Context, d *schema.ResourceData, client interface{}) diag.Diagnostics {
+	repository := d.Get("repository").(string)
+	name := d.Get("name").(string)
+
+	action, err := client.(*providerMeta).actions().Get(repository, name)
+	if errors.As(err, &humio.EntityNotFound{}) {
+		return diag.Errorf("action %s not found in repository %s", name, repository)
+	}
+	if err != nil {
+		return diag.Errorf("could not get action: %s", err)
+	}
+	d.SetId(fmt.Sprintf("%s+%s", repository, name))
+
+	return resourceDataFromAction(action, d)
+}
+
+func dataSourceActions() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceActionsRead,
+		Schema:      dataSourceListSchema("actions", dataSourceSchemaFromResourceSchema(actionDataSourceSchema()), true),
+	}
+}
+
+func dataSourceActionsRead(_ context.Context, d *schema.ResourceData, client interface{}) diag.Diagnostics {
+	repository := d.Get("repository").(string)
+	nameRegex := regexp.MustCompile(d.Get("name_regex").(string))
+
+	items, err := client.(*providerMeta).actions().List(repository)
+	if err != nil {
+		return diag.Errorf("could not list actions: %s", err)
+	}
+
+	elem := dataSourceSchemaFromResourceSchema(actionDataSourceSchema())
+	var actions []interface{}
+	for i := range items {
+		if !nameRegex.MatchString(items[i].Name) {
+			continue
+		}
+		m, diags := flattenToMap(elem, func(d *schema.ResourceData) diag.Diagnostics {
+			return resourceDataFromAction(&items[i], d)
+		})
+		if diags.HasError() {
+			return diags
+		}
+		m["repository"] = repository
+		actions = append(actions, m)
+	}
+
+	err = d.Set("actions", actions)
+	if err != nil {
+		return diag.Errorf("error setting actions for data source: %s", err)
+	}
+	d.SetId(fmt.Sprintf("%s+%s", repository, nameRegex))
+
+	return nil
+}
+
+// actionDataSourceSchema returns the schema of humio_action without the write-only attributes and verify_on_apply,
+// which have no value to read.
+func actionDataSourceSchema() map[string]*schema.Schema {
+	s := resourceAction().Schema
+	for _, k := range actionWriteOnlyAttributes {
+		delete(s, k)
+	}
+	delete(s, "verify_on_apply")
+	return s
+}
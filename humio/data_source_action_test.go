@@ -0,0 +1,126 @@
+// Copyright © 2020 Humio Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package humio
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func TestAccDataSourceAction(t *testing.T) {
+	accTestCase(t, []resource.TestStep{
+		{
+			Config: actionEmailBasic + dataSourceActionConfig,
+			Check: resource.ComposeTestCheckFunc(
+				resource.TestCheckResourceAttrPair("data.humio_action.test", "repository", "humio_action.test", "repository"),
+				resource.TestCheckResourceAttrPair("data.humio_action.test", "name", "humio_action.test", "name"),
+				resource.TestCheckResourceAttrPair("data.humio_action.test", "action_id", "humio_action.test", "action_id"),
+				resource.TestCheckResourceAttrPair("data.humio_action.test", "type", "humio_action.test", "type"),
+				resource.TestCheckResourceAttrPair("data.humio_action.test", "email.#", "humio_action.test", "email.#"),
+			),
+		},
+	}, testAccCheckActionDestroy)
+}
+
+func TestAccDataSourceActions(t *testing.T) {
+	accTestCase(t, []resource.TestStep{
+		{
+			Config: actionEmailBasic + dataSourceActionsConfig,
+			Check: resource.ComposeTestCheckFunc(
+				resource.TestCheckResourceAttr("data.humio_actions.test", "actions.#", "1"),
+				resource.TestCheckResourceAttrPair("data.humio_actions.test", "actions.0.repository", "humio_action.test", "repository"),
+				resource.TestCheckResourceAttrPair("data.humio_actions.test", "actions.0.name", "humio_action.test", "name"),
+				resource.TestCheckResourceAttrPair("data.humio_actions.test", "actions.0.action_id", "humio_action.test", "action_id"),
+				resource.TestCheckResourceAttrPair("data.humio_actions.test", "actions.0.type", "humio_action.test", "type"),
+			),
+		},
+	}, testAccCheckActionDestroy)
+}
+
+func TestAccDataSourceActionNotFound(t *testing.T) {
+	accTestCase(t, []resource.TestStep{
+		{Config: dataSourceActionNotFound, ExpectError: regexp.MustCompile(`action missing not found in repository sandbox`)},
+	}, nil)
+}
+
+func TestAccDataSourceActionsInvalidNameRegex(t *testing.T) {
+	accTestCase(t, []resource.TestStep{
+		{Config: dataSourceActionsInvalidNameRegex, ExpectError: regexp.MustCompile(`"name_regex": error parsing regexp`)},
+	}, nil)
+}
+
+const dataSourceActionConfig = `
+data "humio_action" "test" {
+	repository = humio_action.test.repository
+	name       = humio_action.test.name
+}
+`
+
+const dataSourceActionsConfig = `
+data "humio_actions" "test" {
+	repository = humio_action.test.repository
+	name_regex = "^${humio_action.test.name}$"
+}
+`
+
+const dataSourceActionNotFound = `
+data "humio_action" "test" {
+	repository = "sandbox"
+	name       = "missing"
+}
+`
+
+const dataSourceActionsInvalidNameRegex = `
+data "humio_actions" "test" {
+	repository = "sandbox"
+	name_regex = "("
+}
+`
+
+func TestFlattenActionToDataSourceList(t *testing.T) {
+	elem := dataSourceSchemaFromResourceSchema(actionDataSourceSchema())
+	m, diags := flattenToMap(elem, func(d *schema.ResourceData) diag.Diagnostics {
+		return resourceDataFromAction(&Action{Action: wantEmailAction}, d)
+	})
+	if diags.HasError() {
+		t.Fatal(diags)
+	}
+	m["repository"] = "sandbox"
+
+	data := dataSourceActions().TestResourceData()
+	if err := data.Set("actions", []interface{}{m}); err != nil {
+		t.Fatal(err)
+	}
+	if got := data.Get("actions.0.name"); got != wantEmailAction.Name {
+		t.Errorf("got name %q, want %q", got, wantEmailAction.Name)
+	}
+	if got := data.Get("actions.0.repository"); got != "sandbox" {
+		t.Errorf("got repository %q, want %q", got, "sandbox")
+	}
+	email := data.Get("actions.0.email").(*schema.Set).List()
+	if len(email) != 1 {
+		t.Fatalf("got %d email blocks, want 1", len(email))
+	}
+	recipients := convertInterfaceListToStringSlice(email[0].(map[string]interface{})["recipients"].([]interface{}))
+	if !cmp.Equal(recipients, wantEmailAction.EmailAction.Recipients) {
+		t.Error(cmp.Diff(wantEmailAction.EmailAction.Recipients, recipients))
+	}
+}
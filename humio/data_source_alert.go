@@ -0,0 +1,85 @@
+// Copyright © 2020 Humio Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package humio
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func dataSourceAlert() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceAlertRead,
+		Schema:      dataSourceSchemaFromResourceSchema(resourceAlert().Schema, "repository", "name"),
+	}
+}
+
+func dataSourceAlertRead(_ context.Context, d *schema.ResourceData, client interface{}) diag.Diagnostics {
+	repository := d.Get("repository").(string)
+	name := d.Get("name").(string)
+
+	alert, err := client.(*providerMeta).alerts().Get(repository, name)
+	if err != nil {
+		return diag.Errorf("could not get alert: %s", err)
+	}
+	d.SetId(fmt.Sprintf("%s+%s", repository, name))
+
+	return resourceDataFromAlert(alert, d)
+}
+
+func dataSourceAlerts() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceAlertsRead,
+		Schema:      dataSourceListSchema("alerts", dataSourceSchemaFromResourceSchema(resourceAlert().Schema), true),
+	}
+}
+
+func dataSourceAlertsRead(_ context.Context, d *schema.ResourceData, client interface{}) diag.Diagnostics {
+	repository := d.Get("repository").(string)
+	nameRegex := regexp.MustCompile(d.Get("name_regex").(string))
+
+	items, err := client.(*providerMeta).alerts().List(repository)
+	if err != nil {
+		return diag.Errorf("could not list alerts: %s", err)
+	}
+
+	elem := dataSourceSchemaFromResourceSchema(resourceAlert().Schema)
+	var alerts []interface{}
+	for i := range items {
+		if !nameRegex.MatchString(items[i].Name) {
+			continue
+		}
+		m, diags := flattenToMap(elem, func(d *schema.ResourceData) diag.Diagnostics {
+			return resourceDataFromAlert(&items[i], d)
+		})
+		if diags.HasError() {
+			return diags
+		}
+		m["repository"] = repository
+		alerts = append(alerts, m)
+	}
+
+	err = d.Set("alerts", alerts)
+	if err != nil {
+		return diag.Errorf("error setting alerts for data source: %s", err)
+	}
+	d.SetId(fmt.Sprintf("%s+%s", repository, nameRegex))
+
+	return nil
+}
@@ -0,0 +1,81 @@
+// Copyright © 2020 Humio Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package humio
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccDataSourceAlert(t *testing.T) {
+	accTestCase(t, []resource.TestStep{
+		{
+			Config: alertBasic + dataSourceAlertConfig,
+			Check: resource.ComposeTestCheckFunc(
+				resource.TestCheckResourceAttrPair("data.humio_alert.test", "repository", "humio_alert.test", "repository"),
+				resource.TestCheckResourceAttrPair("data.humio_alert.test", "name", "humio_alert.test", "name"),
+				resource.TestCheckResourceAttrPair("data.humio_alert.test", "alert_id", "humio_alert.test", "alert_id"),
+				resource.TestCheckResourceAttrPair("data.humio_alert.test", "query", "humio_alert.test", "query"),
+				resource.TestCheckResourceAttrPair("data.humio_alert.test", "start", "humio_alert.test", "start"),
+				resource.TestCheckResourceAttrPair("data.humio_alert.test", "throttle_time_millis", "humio_alert.test", "throttle_time_millis"),
+				resource.TestCheckResourceAttrPair("data.humio_alert.test", "enabled", "humio_alert.test", "enabled"),
+			),
+		},
+	}, testAccCheckAlertDestroy)
+}
+
+func TestAccDataSourceAlerts(t *testing.T) {
+	accTestCase(t, []resource.TestStep{
+		{
+			Config: alertBasic + dataSourceAlertsConfig,
+			Check: resource.ComposeTestCheckFunc(
+				resource.TestCheckResourceAttr("data.humio_alerts.test", "alerts.#", "1"),
+				resource.TestCheckResourceAttrPair("data.humio_alerts.test", "alerts.0.repository", "humio_alert.test", "repository"),
+				resource.TestCheckResourceAttrPair("data.humio_alerts.test", "alerts.0.name", "humio_alert.test", "name"),
+				resource.TestCheckResourceAttrPair("data.humio_alerts.test", "alerts.0.alert_id", "humio_alert.test", "alert_id"),
+				resource.TestCheckResourceAttrPair("data.humio_alerts.test", "alerts.0.query", "humio_alert.test", "query"),
+			),
+		},
+	}, testAccCheckAlertDestroy)
+}
+
+func TestAccDataSourceAlertsInvalidNameRegex(t *testing.T) {
+	accTestCase(t, []resource.TestStep{
+		{Config: dataSourceAlertsInvalidNameRegex, ExpectError: regexp.MustCompile(`"name_regex": error parsing regexp`)},
+	}, nil)
+}
+
+const dataSourceAlertConfig = `
+data "humio_alert" "test" {
+	repository = humio_alert.test.repository
+	name       = humio_alert.test.name
+}
+`
+
+const dataSourceAlertsConfig = `
+data "humio_alerts" "test" {
+	repository = humio_alert.test.repository
+	name_regex = "^${humio_alert.test.name}$"
+}
+`
+
+const dataSourceAlertsInvalidNameRegex = `
+data "humio_alerts" "test" {
+	repository = "sandbox"
+	name_regex = "("
+}
+`
@@ -0,0 +1,85 @@
+// Copyright © 2020 Humio Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package humio
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func dataSourceIngestToken() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceIngestTokenRead,
+		Schema:      dataSourceSchemaFromResourceSchema(resourceIngestToken().Schema, "repository", "name"),
+	}
+}
+
+func dataSourceIngestTokenRead(_ context.Context, d *schema.ResourceData, client interface{}) diag.Diagnostics {
+	repository := d.Get("repository").(string)
+	name := d.Get("name").(string)
+
+	ingestToken, err := client.(*providerMeta).client.IngestTokens().Get(repository, name)
+	if err != nil {
+		return diag.Errorf("could not get ingest token: %s", err)
+	}
+	d.SetId(fmt.Sprintf("%s+%s", repository, name))
+
+	return resourceDataFromIngestToken(ingestToken, d)
+}
+
+func dataSourceIngestTokens() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceIngestTokensRead,
+		Schema:      dataSourceListSchema("ingest_tokens", dataSourceSchemaFromResourceSchema(resourceIngestToken().Schema), true),
+	}
+}
+
+func dataSourceIngestTokensRead(_ context.Context, d *schema.ResourceData, client interface{}) diag.Diagnostics {
+	repository := d.Get("repository").(string)
+	nameRegex := regexp.MustCompile(d.Get("name_regex").(string))
+
+	items, err := client.(*providerMeta).client.IngestTokens().List(repository)
+	if err != nil {
+		return diag.Errorf("could not list ingest tokens: %s", err)
+	}
+
+	elem := dataSourceSchemaFromResourceSchema(resourceIngestToken().Schema)
+	var ingestTokens []interface{}
+	for i := range items {
+		if !nameRegex.MatchString(items[i].Name) {
+			continue
+		}
+		m, diags := flattenToMap(elem, func(d *schema.ResourceData) diag.Diagnostics {
+			return resourceDataFromIngestToken(&items[i], d)
+		})
+		if diags.HasError() {
+			return diags
+		}
+		m["repository"] = repository
+		ingestTokens = append(ingestTokens, m)
+	}
+
+	err = d.Set("ingest_tokens", ingestTokens)
+	if err != nil {
+		return diag.Errorf("error setting ingest_tokens for data source: %s", err)
+	}
+	d.SetId(fmt.Sprintf("%s+%s", repository, nameRegex))
+
+	return nil
+}
@@ -0,0 +1,77 @@
+// Copyright © 2020 Humio Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package humio
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccDataSourceIngestToken(t *testing.T) {
+	accTestCase(t, []resource.TestStep{
+		{
+			Config: ingestTokenBasic + dataSourceIngestTokenConfig,
+			Check: resource.ComposeTestCheckFunc(
+				resource.TestCheckResourceAttrPair("data.humio_ingest_token.test", "repository", "humio_ingest_token.test", "repository"),
+				resource.TestCheckResourceAttrPair("data.humio_ingest_token.test", "name", "humio_ingest_token.test", "name"),
+				resource.TestCheckResourceAttrPair("data.humio_ingest_token.test", "parser", "humio_ingest_token.test", "parser"),
+				resource.TestCheckResourceAttrPair("data.humio_ingest_token.test", "token", "humio_ingest_token.test", "token"),
+			),
+		},
+	}, testAccCheckIngestTokenDestroy)
+}
+
+func TestAccDataSourceIngestTokens(t *testing.T) {
+	accTestCase(t, []resource.TestStep{
+		{
+			Config: ingestTokenBasic + dataSourceIngestTokensConfig,
+			Check: resource.ComposeTestCheckFunc(
+				resource.TestCheckResourceAttr("data.humio_ingest_tokens.test", "ingest_tokens.#", "1"),
+				resource.TestCheckResourceAttrPair("data.humio_ingest_tokens.test", "ingest_tokens.0.repository", "humio_ingest_token.test", "repository"),
+				resource.TestCheckResourceAttrPair("data.humio_ingest_tokens.test", "ingest_tokens.0.name", "humio_ingest_token.test", "name"),
+				resource.TestCheckResourceAttrPair("data.humio_ingest_tokens.test", "ingest_tokens.0.token", "humio_ingest_token.test", "token"),
+			),
+		},
+	}, testAccCheckIngestTokenDestroy)
+}
+
+func TestAccDataSourceIngestTokensInvalidNameRegex(t *testing.T) {
+	accTestCase(t, []resource.TestStep{
+		{Config: dataSourceIngestTokensInvalidNameRegex, ExpectError: regexp.MustCompile(`"name_regex": error parsing regexp`)},
+	}, nil)
+}
+
+const dataSourceIngestTokenConfig = `
+data "humio_ingest_token" "test" {
+	repository = humio_ingest_token.test.repository
+	name       = humio_ingest_token.test.name
+}
+`
+
+const dataSourceIngestTokensConfig = `
+data "humio_ingest_tokens" "test" {
+	repository = humio_ingest_token.test.repository
+	name_regex = "^${humio_ingest_token.test.name}$"
+}
+`
+
+const dataSourceIngestTokensInvalidNameRegex = `
+data "humio_ingest_tokens" "test" {
+	repository = "sandbox"
+	name_regex = "("
+}
+`
@@ -0,0 +1,95 @@
+// Copyright © 2020 Humio Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package humio
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+
+	humio "github.com/humio/cli/api"
+)
+
+func dataSourceParser() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceParserRead,
+		Schema:      dataSourceSchemaFromResourceSchema(resourceParser().Schema, "repository", "name"),
+	}
+}
+
+func dataSourceParserRead(_ context.Context, d *schema.ResourceData, client interface{}) diag.Diagnostics {
+	repository := d.Get("repository").(string)
+	name := d.Get("name").(string)
+
+	parser, err := client.(*providerMeta).client.Parsers().Get(repository, name)
+	if errors.As(err, &humio.EntityNotFound{}) {
+		return diag.Errorf("parser %s not found in repository %s", name, repository)
+	}
+	if err != nil {
+		return diag.Errorf("could not get parser: %s", err)
+	}
+	d.SetId(fmt.Sprintf("%s+%s", repository, name))
+
+	return resourceDataFromParser(parser, d)
+}
+
+func dataSourceParsers() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceParsersRead,
+		Schema:      dataSourceListSchema("parsers", dataSourceSchemaFromResourceSchema(resourceParser().Schema), true),
+	}
+}
+
+func dataSourceParsersRead(_ context.Context, d *schema.ResourceData, client interface{}) diag.Diagnostics {
+	repository := d.Get("repository").(string)
+	nameRegex := regexp.MustCompile(d.Get("name_regex").(string))
+
+	items, err := client.(*providerMeta).client.Parsers().List(repository)
+	if err != nil {
+		return diag.Errorf("could not list parsers: %s", err)
+	}
+
+	elem := dataSourceSchemaFromResourceSchema(resourceParser().Schema)
+	var parsers []interface{}
+	for _, item := range items {
+		if !nameRegex.MatchString(item.Name) {
+			continue
+		}
+		parser, err := client.(*providerMeta).client.Parsers().Get(repository, item.Name)
+		if err != nil {
+			return diag.Errorf("could not get parser %s: %s", item.Name, err)
+		}
+		m, diags := flattenToMap(elem, func(d *schema.ResourceData) diag.Diagnostics {
+			return resourceDataFromParser(parser, d)
+		})
+		if diags.HasError() {
+			return diags
+		}
+		m["repository"] = repository
+		parsers = append(parsers, m)
+	}
+
+	err = d.Set("parsers", parsers)
+	if err != nil {
+		return diag.Errorf("error setting parsers for data source: %s", err)
+	}
+	d.SetId(fmt.Sprintf("%s+%s", repository, nameRegex))
+
+	return nil
+}
@@ -0,0 +1,91 @@
+// Copyright © 2020 Humio Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package humio
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccDataSourceParser(t *testing.T) {
+	accTestCase(t, []resource.TestStep{
+		{
+			Config: parserBasic + dataSourceParserConfig,
+			Check: resource.ComposeTestCheckFunc(
+				resource.TestCheckResourceAttrPair("data.humio_parser.test", "repository", "humio_parser.test", "repository"),
+				resource.TestCheckResourceAttrPair("data.humio_parser.test", "name", "humio_parser.test", "name"),
+				resource.TestCheckResourceAttrPair("data.humio_parser.test", "parser_script", "humio_parser.test", "parser_script"),
+				resource.TestCheckResourceAttrPair("data.humio_parser.test", "tag_fields.#", "humio_parser.test", "tag_fields.#"),
+				resource.TestCheckResourceAttrPair("data.humio_parser.test", "test_data.#", "humio_parser.test", "test_data.#"),
+			),
+		},
+	}, testAccCheckParserDestroy)
+}
+
+func TestAccDataSourceParsers(t *testing.T) {
+	accTestCase(t, []resource.TestStep{
+		{
+			Config: parserBasic + dataSourceParsersConfig,
+			Check: resource.ComposeTestCheckFunc(
+				resource.TestCheckResourceAttr("data.humio_parsers.test", "parsers.#", "1"),
+				resource.TestCheckResourceAttrPair("data.humio_parsers.test", "parsers.0.repository", "humio_parser.test", "repository"),
+				resource.TestCheckResourceAttrPair("data.humio_parsers.test", "parsers.0.name", "humio_parser.test", "name"),
+				resource.TestCheckResourceAttrPair("data.humio_parsers.test", "parsers.0.parser_script", "humio_parser.test", "parser_script"),
+			),
+		},
+	}, testAccCheckParserDestroy)
+}
+
+func TestAccDataSourceParserNotFound(t *testing.T) {
+	accTestCase(t, []resource.TestStep{
+		{Config: dataSourceParserNotFound, ExpectError: regexp.MustCompile(`parser missing not found in repository sandbox`)},
+	}, nil)
+}
+
+func TestAccDataSourceParsersInvalidNameRegex(t *testing.T) {
+	accTestCase(t, []resource.TestStep{
+		{Config: dataSourceParsersInvalidNameRegex, ExpectError: regexp.MustCompile(`"name_regex": error parsing regexp`)},
+	}, nil)
+}
+
+const dataSourceParserConfig = `
+data "humio_parser" "test" {
+	repository = humio_parser.test.repository
+	name       = humio_parser.test.name
+}
+`
+
+const dataSourceParsersConfig = `
+data "humio_parsers" "test" {
+	repository = humio_parser.test.repository
+	name_regex = "^${humio_parser.test.name}$"
+}
+`
+
+const dataSourceParserNotFound = `
+data "humio_parser" "test" {
+	repository = "sandbox"
+	name       = "missing"
+}
+`
+
+const dataSourceParsersInvalidNameRegex = `
+data "humio_parsers" "test" {
+	repository = "sandbox"
+	name_regex = "("
+}
+`
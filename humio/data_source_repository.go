@@ -0,0 +1,86 @@
+// Copyright © 2020 Humio Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package humio
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func dataSourceRepository() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceRepositoryRead,
+		Schema:      dataSourceSchemaFromResourceSchema(resourceRepository().Schema, "name"),
+	}
+}
+
+func dataSourceRepositoryRead(_ context.Context, d *schema.ResourceData, client interface{}) diag.Diagnostics {
+	name := d.Get("name").(string)
+
+	repo, err := client.(*providerMeta).client.Repositories().Get(name)
+	if err != nil {
+		return diag.Errorf("could not get repository: %s", err)
+	}
+	d.SetId(name)
+
+	return resourceDataFromRepository(&repo, d)
+}
+
+func dataSourceRepositories() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceRepositoriesRead,
+		Schema:      dataSourceListSchema("repositories", dataSourceSchemaFromResourceSchema(resourceRepository().Schema), false),
+	}
+}
+
+func dataSourceRepositoriesRead(_ context.Context, d *schema.ResourceData, client interface{}) diag.Diagnostics {
+	nameRegex := regexp.MustCompile(d.Get("name_regex").(string))
+
+	items, err := client.(*providerMeta).client.Repositories().List()
+	if err != nil {
+		return diag.Errorf("could not list repositories: %s", err)
+	}
+
+	elem := dataSourceSchemaFromResourceSchema(resourceRepository().Schema)
+	var repositories []interface{}
+	for _, item := range items {
+		if !nameRegex.MatchString(item.Name) {
+			continue
+		}
+		repo, err := client.(*providerMeta).client.Repositories().Get(item.Name)
+		if err != nil {
+			return diag.Errorf("could not get repository %s: %s", item.Name, err)
+		}
+		m, diags := flattenToMap(elem, func(d *schema.ResourceData) diag.Diagnostics {
+			return resourceDataFromRepository(&repo, d)
+		})
+		if diags.HasError() {
+			return diags
+		}
+		repositories = append(repositories, m)
+	}
+
+	err = d.Set("repositories", repositories)
+	if err != nil {
+		return diag.Errorf("error setting repositories for data source: %s", err)
+	}
+	d.SetId(fmt.Sprintf("repositories+%s", nameRegex))
+
+	return nil
+}
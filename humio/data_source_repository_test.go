@@ -0,0 +1,72 @@
+// Copyright © 2020 Humio Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package humio
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccDataSourceRepository(t *testing.T) {
+	accTestCase(t, []resource.TestStep{
+		{
+			Config: repositoryBasic + dataSourceRepositoryConfig,
+			Check: resource.ComposeTestCheckFunc(
+				resource.TestCheckResourceAttrPair("data.humio_repository.test", "name", "humio_repository.test", "name"),
+				resource.TestCheckResourceAttrPair("data.humio_repository.test", "description", "humio_repository.test", "description"),
+				resource.TestCheckResourceAttrPair("data.humio_repository.test", "retention.#", "humio_repository.test", "retention.#"),
+			),
+		},
+	}, testAccCheckRepositoryDestroy)
+}
+
+func TestAccDataSourceRepositories(t *testing.T) {
+	accTestCase(t, []resource.TestStep{
+		{
+			Config: repositoryBasic + dataSourceRepositoriesConfig,
+			Check: resource.ComposeTestCheckFunc(
+				resource.TestCheckResourceAttr("data.humio_repositories.test", "repositories.#", "1"),
+				resource.TestCheckResourceAttrPair("data.humio_repositories.test", "repositories.0.name", "humio_repository.test", "name"),
+				resource.TestCheckResourceAttrPair("data.humio_repositories.test", "repositories.0.description", "humio_repository.test", "description"),
+			),
+		},
+	}, testAccCheckRepositoryDestroy)
+}
+
+func TestAccDataSourceRepositoriesInvalidNameRegex(t *testing.T) {
+	accTestCase(t, []resource.TestStep{
+		{Config: dataSourceRepositoriesInvalidNameRegex, ExpectError: regexp.MustCompile(`"name_regex": error parsing regexp`)},
+	}, nil)
+}
+
+const dataSourceRepositoryConfig = `
+data "humio_repository" "test" {
+	name = humio_repository.test.name
+}
+`
+
+const dataSourceRepositoriesConfig = `
+data "humio_repositories" "test" {
+	name_regex = "^${humio_repository.test.name}$"
+}
+`
+
+const dataSourceRepositoriesInvalidNameRegex = `
+data "humio_repositories" "test" {
+	name_regex = "("
+}
+`
@@ -0,0 +1,87 @@
+// Copyright © 2020 Humio Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package humio
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func dataSourceView() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceViewRead,
+		Schema:      dataSourceSchemaFromResourceSchema(resourceView().Schema, "name"),
+	}
+}
+
+func dataSourceViewRead(_ context.Context, d *schema.ResourceData, client interface{}) diag.Diagnostics {
+	name := d.Get("name").(string)
+
+	view, err := client.(*providerMeta).client.Views().Get(name)
+	if err != nil {
+		return diag.Errorf("could not get view: %s", err)
+	}
+	d.SetId(name)
+
+	return resourceDataFromView(view, d)
+}
+
+func dataSourceViews() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceViewsRead,
+		Schema:      dataSourceListSchema("views", dataSourceSchemaFromResourceSchema(resourceView().Schema), false),
+	}
+}
+
+func dataSourceViewsRead(_ context.Context, d *schema.ResourceData, client interface{}) diag.Diagnostics {
+	nameRegex := regexp.MustCompile(d.Get("name_regex").(string))
+
+	items, err := client.(*providerMeta).client.Views().List()
+	if err != nil {
+		return diag.Errorf("could not list views: %s", err)
+	}
+
+	elem := dataSourceSchemaFromResourceSchema(resourceView().Schema)
+	var views []interface{}
+	for _, item := range items {
+		// Repositories are also search domains, so they are listed alongside views.
+		if item.Typename != "View" || !nameRegex.MatchString(item.Name) {
+			continue
+		}
+		view, err := client.(*providerMeta).client.Views().Get(item.Name)
+		if err != nil {
+			return diag.Errorf("could not get view %s: %s", item.Name, err)
+		}
+		m, diags := flattenToMap(elem, func(d *schema.ResourceData) diag.Diagnostics {
+			return resourceDataFromView(view, d)
+		})
+		if diags.HasError() {
+			return diags
+		}
+		views = append(views, m)
+	}
+
+	err = d.Set("views", views)
+	if err != nil {
+		return diag.Errorf("error setting views for data source: %s", err)
+	}
+	d.SetId(fmt.Sprintf("views+%s", nameRegex))
+
+	return nil
+}
@@ -0,0 +1,75 @@
+// Copyright © 2020 Humio Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package humio
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccDataSourceView(t *testing.T) {
+	accTestCase(t, []resource.TestStep{
+		{
+			Config: viewBasic + dataSourceViewConfig,
+			Check: resource.ComposeTestCheckFunc(
+				resource.TestCheckResourceAttrPair("data.humio_view.test", "name", "humio_view.test", "name"),
+				resource.TestCheckResourceAttrPair("data.humio_view.test", "description", "humio_view.test", "description"),
+				resource.TestCheckResourceAttrPair("data.humio_view.test", "repository.#", "humio_view.test", "repository.#"),
+				resource.TestCheckResourceAttrPair("data.humio_view.test", "repository.0.name", "humio_view.test", "repository.0.name"),
+				resource.TestCheckResourceAttrPair("data.humio_view.test", "repository.0.filter", "humio_view.test", "repository.0.filter"),
+			),
+		},
+	}, testAccCheckViewDestroy)
+}
+
+func TestAccDataSourceViews(t *testing.T) {
+	accTestCase(t, []resource.TestStep{
+		{
+			Config: viewBasic + dataSourceViewsConfig,
+			Check: resource.ComposeTestCheckFunc(
+				resource.TestCheckResourceAttr("data.humio_views.test", "views.#", "1"),
+				resource.TestCheckResourceAttrPair("data.humio_views.test", "views.0.name", "humio_view.test", "name"),
+				resource.TestCheckResourceAttrPair("data.humio_views.test", "views.0.description", "humio_view.test", "description"),
+				resource.TestCheckResourceAttrPair("data.humio_views.test", "views.0.repository.#", "humio_view.test", "repository.#"),
+			),
+		},
+	}, testAccCheckViewDestroy)
+}
+
+func TestAccDataSourceViewsInvalidNameRegex(t *testing.T) {
+	accTestCase(t, []resource.TestStep{
+		{Config: dataSourceViewsInvalidNameRegex, ExpectError: regexp.MustCompile(`"name_regex": error parsing regexp`)},
+	}, nil)
+}
+
+const dataSourceViewConfig = `
+data "humio_view" "test" {
+	name = humio_view.test.name
+}
+`
+
+const dataSourceViewsConfig = `
+data "humio_views" "test" {
+	name_regex = "^${humio_view.test.name}$"
+}
+`
+
+const dataSourceViewsInvalidNameRegex = `
+data "humio_views" "test" {
+	name_regex = "("
+}
+`
@@ -0,0 +1,146 @@
+// Copyright © 2020 Humio Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package humio
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"time"
+
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// durationUnits are the units LogScale accepts in relative times, e.g. the start of a query. Months count as 30 days
+// where a fixed length is needed, e.g. for throttle_time.
+var durationUnits = map[string]time.Duration{
+	"ms":           time.Millisecond,
+	"millis":       time.Millisecond,
+	"millisecond":  time.Millisecond,
+	"milliseconds": time.Millisecond,
+	"s":            time.Second,
+	"sec":          time.Second,
+	"secs":         time.Second,
+	"second":       time.Second,
+	"seconds":      time.Second,
+	"m":            time.Minute,
+	"min":          time.Minute,
+	"mins":         time.Minute,
+	"minute":       time.Minute,
+	"minutes":      time.Minute,
+	"h":            time.Hour,
+	"hr":           time.Hour,
+	"hrs":          time.Hour,
+	"hour":         time.Hour,
+	"hours":        time.Hour,
+	"d":            24 * time.Hour,
+	"day":          24 * time.Hour,
+	"days":         24 * time.Hour,
+	"w":            7 * 24 * time.Hour,
+	"week":         7 * 24 * time.Hour,
+	"weeks":        7 * 24 * time.Hour,
+	"mon":          30 * 24 * time.Hour,
+	"month":        30 * 24 * time.Hour,
+	"months":       30 * 24 * time.Hour,
+	"y":            365 * 24 * time.Hour,
+	"yr":           365 * 24 * time.Hour,
+	"yrs":          365 * 24 * time.Hour,
+	"year":         365 * 24 * time.Hour,
+	"years":        365 * 24 * time.Hour,
+}
+
+// monthUnits are the units of durationUnits that LogScale takes to be calendar months when they start a query.
+var monthUnits = map[string]bool{
+	"mon":    true,
+	"month":  true,
+	"months": true,
+}
+
+var durationRegex = regexp.MustCompile(`^(\d+) ?([a-z]+)$`)
+
+// parseDuration parses a duration given as digits followed by a unit, e.g. 5m, 24h or 2w.
+func parseDuration(s string) (time.Duration, error) {
+	matches := durationRegex.FindStringSubmatch(s)
+	if matches == nil {
+		return 0, fmt.Errorf("%q is not a duration, expected digits followed by a unit, e.g. 5m, 24h or 2w", s)
+	}
+	unit, ok := durationUnits[matches[2]]
+	if !ok {
+		return 0, fmt.Errorf("%q has unknown unit %q, expected one of ms, s, m, h, d, w, mon or y", s, matches[2])
+	}
+	n, err := strconv.ParseInt(matches[1], 10, 64)
+	if err != nil || n > int64(time.Duration(1<<63-1)/unit) {
+		return 0, fmt.Errorf("%q is too large", s)
+	}
+	return time.Duration(n) * unit, nil
+}
+
+// formatDuration formats a duration using the largest unit that represents it exactly.
+func formatDuration(d time.Duration) string {
+	for _, u := range []struct {
+		name string
+		unit time.Duration
+	}{
+		{"w", 7 * 24 * time.Hour},
+		{"d", 24 * time.Hour},
+		{"h", time.Hour},
+		{"m", time.Minute},
+		{"s", time.Second},
+	} {
+		if d != 0 && d%u.unit == 0 {
+			return fmt.Sprintf("%d%s", d/u.unit, u.name)
+		}
+	}
+	return fmt.Sprintf("%dms", d.Milliseconds())
+}
+
+func validateDuration(v interface{}, _ cty.Path) diag.Diagnostics {
+	_, err := parseDuration(v.(string))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	return nil
+}
+
+// suppressEquivalentDurations suppresses diffs between different ways of writing the same duration, e.g. 1d and 24h.
+func suppressEquivalentDurations(_, old, new string, _ *schema.ResourceData) bool {
+	return equivalentDurations(old, new)
+}
+
+func equivalentDurations(a, b string) bool {
+	if a == b {
+		return true
+	}
+	// Calendar months do not always last 30 days, so they are only equivalent to other months.
+	if isMonths(a) != isMonths(b) {
+		return false
+	}
+	da, err := parseDuration(a)
+	if err != nil {
+		return false
+	}
+	db, err := parseDuration(b)
+	if err != nil {
+		return false
+	}
+	return da == db
+}
+
+func isMonths(s string) bool {
+	matches := durationRegex.FindStringSubmatch(s)
+	return matches != nil && monthUnits[matches[2]]
+}
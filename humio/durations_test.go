@@ -0,0 +1,110 @@
+// Copyright © 2020 Humio Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package humio
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseDuration(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    time.Duration
+		wantErr bool
+	}{
+		{in: "500ms", want: 500 * time.Millisecond},
+		{in: "30s", want: 30 * time.Second},
+		{in: "5m", want: 5 * time.Minute},
+		{in: "5 minutes", want: 5 * time.Minute},
+		{in: "24h", want: 24 * time.Hour},
+		{in: "1d", want: 24 * time.Hour},
+		{in: "2w", want: 14 * 24 * time.Hour},
+		{in: "1y", want: 365 * 24 * time.Hour},
+		{in: "", wantErr: true},
+		{in: "5", wantErr: true},
+		{in: "m", wantErr: true},
+		{in: "-5m", wantErr: true},
+		{in: "1.5h", wantErr: true},
+		{in: "5M", wantErr: true},
+		{in: "1mon", want: 30 * 24 * time.Hour},
+		{in: "2 months", want: 60 * 24 * time.Hour},
+		{in: "2mons", wantErr: true},
+		{in: "99999999999999999999d", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := parseDuration(tt.in)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseDuration(%q) error = %v, wantErr %t", tt.in, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("parseDuration(%q) = %s, want %s", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatDuration(t *testing.T) {
+	tests := []struct {
+		in   time.Duration
+		want string
+	}{
+		{in: 0, want: "0ms"},
+		{in: 1500 * time.Millisecond, want: "1500ms"},
+		{in: 90 * time.Second, want: "90s"},
+		{in: 5 * time.Minute, want: "5m"},
+		{in: 36 * time.Hour, want: "36h"},
+		{in: 48 * time.Hour, want: "2d"},
+		{in: 14 * 24 * time.Hour, want: "2w"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			got := formatDuration(tt.in)
+			if got != tt.want {
+				t.Errorf("formatDuration(%s) = %q, want %q", tt.in, got, tt.want)
+			}
+			parsed, err := parseDuration(got)
+			if err != nil || parsed != tt.in {
+				t.Errorf("parseDuration(%q) = %s, %v, want %s", got, parsed, err, tt.in)
+			}
+		})
+	}
+}
+
+func TestEquivalentDurations(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want bool
+	}{
+		{a: "1d", b: "24h", want: true},
+		{a: "60m", b: "1h", want: true},
+		{a: "1 week", b: "7d", want: true},
+		{a: "1d", b: "23h", want: false},
+		{a: "1mon", b: "1 month", want: true},
+		{a: "1mon", b: "30d", want: false},
+		{a: "", b: "1h", want: false},
+		{a: "invalid", b: "invalid", want: true},
+		{a: "invalid", b: "1h", want: false},
+	}
+
+	for _, tt := range tests {
+		if got := equivalentDurations(tt.a, tt.b); got != tt.want {
+			t.Errorf("equivalentDurations(%q, %q) = %t, want %t", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
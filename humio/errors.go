@@ -0,0 +1,94 @@
+// Copyright © 2020 Humio Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package humio
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	humio "github.com/humio/cli/api"
+)
+
+// notFoundError is returned by the GraphQL layers in this package when the requested object does not exist.
+type notFoundError struct {
+	entityType string
+	key        string
+}
+
+func (e notFoundError) Error() string {
+	return fmt.Sprintf("%s %q not found", e.entityType, e.key)
+}
+
+// searchDomainNotFoundOr returns a notFoundError if there is no repository or view with the name, as LogScale only
+// reports this as a generic GraphQL error, and err otherwise.
+func searchDomainNotFoundOr(client *humio.Client, name string, err error) error {
+	list, listErr := client.Views().List()
+	if listErr != nil {
+		return err
+	}
+	for _, searchDomain := range list {
+		if searchDomain.Name == name {
+			return err
+		}
+	}
+	return notFoundError{entityType: "repository or view", key: name}
+}
+
+// userNotFoundOr returns a notFoundError if there is no user with the username, as github.com/humio/cli does not
+// report this as a humio.EntityNotFound, and err otherwise.
+func userNotFoundOr(client *humio.Client, username string, err error) error {
+	list, listErr := client.Users().List()
+	if listErr != nil {
+		return err
+	}
+	for _, user := range list {
+		if user.Username == username {
+			return err
+		}
+	}
+	return notFoundError{entityType: "user", key: username}
+}
+
+// notFoundMessages are fragments of error messages that LogScale, or github.com/humio/cli, returns for objects that
+// do not exist. They are only a fallback for errors not already turned into a humio.EntityNotFound or a
+// notFoundError, as the wording may change between versions.
+var notFoundMessages = []string{
+	"could not find an ingest token with name",
+	"user not found",
+	"entity not found",
+	"could not find a repository",
+	"could not find a view",
+	"could not find a search domain",
+}
+
+// isNotFound reports whether err means that the object being read no longer exists, as opposed to errors such as
+// failed requests or missing permissions, which must still fail the read.
+func isNotFound(err error) bool {
+	if err == nil {
+		return false
+	}
+	if errors.As(err, &humio.EntityNotFound{}) || errors.As(err, &notFoundError{}) {
+		return true
+	}
+
+	msg := strings.ToLower(err.Error())
+	for _, m := range notFoundMessages {
+		if strings.Contains(msg, m) {
+			return true
+		}
+	}
+	return false
+}
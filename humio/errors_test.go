@@ -0,0 +1,126 @@
+// Copyright © 2020 Humio Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package humio
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	humio "github.com/humio/cli/api"
+)
+
+func TestIsNotFound(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{name: "nil", err: nil, want: false},
+		{name: "alert", err: humio.AlertNotFound("my-alert"), want: true},
+		{name: "wrapped action", err: fmt.Errorf("lookup failed: %w", humio.ActionNotFound("my-action")), want: true},
+		{name: "parser", err: humio.ParserNotFound("my-parser"), want: true},
+		{name: "scheduled search", err: notFoundError{entityType: "scheduled search", key: "my-search"}, want: true},
+		{name: "ingest token", err: errors.New("could not find an ingest token with name 'my-token' in repo 'sandbox'"), want: true},
+		{name: "graphql entity", err: errors.New("Entity Not Found. Does the repo already exist?"), want: true},
+		{name: "user", err: errors.New("user not found"), want: true},
+		{name: "repository", err: errors.New("Message: Could not find a repository with name 'sandbox', Locations: []"), want: true},
+		{name: "view", err: errors.New("Message: Could not find a view with name 'sandbox', Locations: []"), want: true},
+		{name: "search domain", err: errors.New("Message: Could not find a search domain with name 'sandbox', Locations: []"), want: true},
+		{name: "unauthorized", err: errors.New("401 Unauthorized"), want: false},
+		{name: "transport", err: errors.New("dial tcp 127.0.0.1:8080: connect: connection refused"), want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isNotFound(tt.err); got != tt.want {
+				t.Errorf("isNotFound(%v) = %t, want %t", tt.err, got, tt.want)
+			}
+		})
+	}
+}
+
+// newNotFoundTestClient returns a client for a LogScale with the repository sandbox, holding the ingest token ingest,
+// and the user alice. Looking up anything else fails with a generic GraphQL error.
+func newNotFoundTestClient(t *testing.T) *humio.Client {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		switch request := string(body); {
+		case strings.Contains(request, "searchDomains"):
+			_, _ = io.WriteString(w, `{"data":{"searchDomains":[{"name":"sandbox","__typename":"Repository"}]}}`)
+		case strings.Contains(request, "users(search:"):
+			_, _ = io.WriteString(w, `{"data":{"users":[]}}`)
+		case strings.Contains(request, "users"):
+			_, _ = io.WriteString(w, `{"data":{"users":[{"username":"alice"}]}}`)
+		case strings.Contains(request, "ingestTokens") && strings.Contains(request, `"repositoryName":"sandbox"`):
+			_, _ = io.WriteString(w, `{"data":{"repository":{"ingestTokens":[{"name":"ingest","token":"secret"}]}}}`)
+		default:
+			_, _ = io.WriteString(w, `{"errors":[{"message":"Something went wrong"}]}`)
+		}
+	}))
+	t.Cleanup(server.Close)
+	address, err := url.Parse(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return humio.NewClient(humio.Config{Address: address})
+}
+
+func TestNotFoundOr(t *testing.T) {
+	client := newNotFoundTestClient(t)
+	err := errors.New("Something went wrong")
+
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{name: "missing repository", err: searchDomainNotFoundOr(client, "deleted", err), want: true},
+		{name: "existing repository", err: searchDomainNotFoundOr(client, "sandbox", err), want: false},
+		{name: "missing user", err: userNotFoundOr(client, "bob", err), want: true},
+		{name: "existing user", err: userNotFoundOr(client, "alice", err), want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isNotFound(tt.err); got != tt.want {
+				t.Errorf("isNotFound(%v) = %t, want %t", tt.err, got, tt.want)
+			}
+			if !tt.want && tt.err != err {
+				t.Errorf("got error %v, want the original error", tt.err)
+			}
+		})
+	}
+}
+
+func TestIngestTokensGet(t *testing.T) {
+	tokens := newIngestTokens(newNotFoundTestClient(t))
+
+	token, err := tokens.Get("sandbox", "ingest")
+	if err != nil || token.Token != "secret" {
+		t.Errorf("Get() = %v, %v", token, err)
+	}
+	for _, name := range [][2]string{{"sandbox", "missing"}, {"deleted", "ingest"}} {
+		if _, err := tokens.Get(name[0], name[1]); !errors.As(err, &notFoundError{}) {
+			t.Errorf("Get(%q, %q): got error %v, want a notFoundError", name[0], name[1], err)
+		}
+	}
+}
@@ -0,0 +1,115 @@
+// Copyright © 2020 Humio Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package humio
+
+import (
+	graphql "github.com/cli/shurcooL-graphql"
+
+	humio "github.com/humio/cli/api"
+)
+
+// The types in this file are used when issuing GraphQL requests that are not yet covered by github.com/humio/cli.
+// The graphql package derives variable types from the Go type names, so these must match the names in the schema.
+
+// Long is the GraphQL Long scalar.
+type Long int64
+
+// QueryOwnershipType is the GraphQL enum used when setting the ownership of a query.
+type QueryOwnershipType string
+
+// queryOwnership is the selection used when reading the ownership of a query.
+type queryOwnership struct {
+	ID       graphql.String `graphql:"id"`
+	Typename graphql.String `graphql:"__typename"`
+}
+
+// queryOwnershipTypeFromTypename maps the typename returned by LogScale to the value accepted by query_ownership_type.
+func queryOwnershipTypeFromTypename(typename string) string {
+	switch typename {
+	case "OrganizationOwnership":
+		return humio.QueryOwnershipTypeOrganization
+	case "UserOwnership":
+		return humio.QueryOwnershipTypeUser
+	}
+	return ""
+}
+
+// optQueryOwnershipTypeArg returns nil for an empty ownership type, so LogScale applies its default.
+func optQueryOwnershipTypeArg(v string) *QueryOwnershipType {
+	if v == "" {
+		return nil
+	}
+	ownership := QueryOwnershipType(v)
+	return &ownership
+}
+
+// optStringArg returns nil for an empty string, so optional fields are left unset.
+func optStringArg(v string) *graphql.String {
+	if v == "" {
+		return nil
+	}
+	return graphql.NewString(graphql.String(v))
+}
+
+func graphqlStringList(s []string) []graphql.String {
+	list := make([]graphql.String, len(s))
+	for i, v := range s {
+		list[i] = graphql.String(v)
+	}
+	return list
+}
+
+// RepoOrViewName is the GraphQL scalar used by the newer alert mutations to reference a repository or view.
+type RepoOrViewName string
+
+// LanguageVersionEnum is the GraphQL enum selecting the version of the query language when analyzing a query.
+type LanguageVersionEnum string
+
+// QueryTimestampType is the GraphQL enum used to select which timestamp an aggregate alert searches on.
+type QueryTimestampType string
+
+// TriggerMode is the GraphQL enum used to select when an aggregate alert triggers.
+type TriggerMode string
+
+// optTriggerModeArg returns nil for an empty trigger mode, so LogScale applies its default.
+func optTriggerModeArg(v string) *TriggerMode {
+	if v == "" {
+		return nil
+	}
+	mode := TriggerMode(v)
+	return &mode
+}
+
+// Permission is the GraphQL enum used for view permissions of a role.
+type Permission string
+
+// OrganizationPermission is the GraphQL enum used for organization permissions of a role.
+type OrganizationPermission string
+
+// SystemPermission is the GraphQL enum used for system permissions of a role.
+type SystemPermission string
+
+// actionReference is the selection used when reading the actions attached to a filter or aggregate alert.
+type actionReference struct {
+	ID string `graphql:"id"`
+}
+
+func actionIDs(actions []actionReference) []string {
+	var ids []string
+	for _, action := range actions {
+		ids = append(ids, action.ID)
+	}
+	return ids
+}
@@ -0,0 +1,93 @@
+// Copyright © 2020 Humio Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package humio
+
+import (
+	"sync"
+)
+
+// listCache holds the objects listed per object type and repository. The provider is configured anew for every
+// Terraform operation, such as a plan or an apply, so reading all objects of a repository during a refresh lists them
+// once rather than once per object. Writing an object invalidates the list of its type in its repository.
+type listCache struct {
+	mu      sync.Mutex
+	entries map[listCacheKey]*listCacheEntry
+}
+
+type listCacheKey struct {
+	objectType string
+	repository string
+}
+
+type listCacheEntry struct {
+	// done is closed when the list has been fetched.
+	done  chan struct{}
+	value interface{}
+	err   error
+}
+
+func newListCache() *listCache {
+	return &listCache{entries: make(map[listCacheKey]*listCacheEntry)}
+}
+
+// cachedList returns the cached list of the object type in the repository, or fetches it with list. Concurrent
+// callers wait for the same list rather than each fetching it. Failures are not cached, and a nil cache always lists.
+func cachedList[T any](c *listCache, objectType, repository string, list func(string) ([]T, error)) ([]T, error) {
+	if c == nil {
+		return list(repository)
+	}
+
+	key := listCacheKey{objectType: objectType, repository: repository}
+	c.mu.Lock()
+	entry, ok := c.entries[key]
+	if !ok {
+		entry = &listCacheEntry{done: make(chan struct{})}
+		c.entries[key] = entry
+		c.mu.Unlock()
+
+		entry.value, entry.err = list(repository)
+		close(entry.done)
+		if entry.err != nil {
+			c.remove(key, entry)
+		}
+	} else {
+		c.mu.Unlock()
+		<-entry.done
+	}
+
+	if entry.err != nil {
+		return nil, entry.err
+	}
+	return entry.value.([]T), nil
+}
+
+// invalidate removes the cached list of the object type in the repository, so the next call lists it again.
+func (c *listCache) invalidate(objectType, repository string) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.entries, listCacheKey{objectType: objectType, repository: repository})
+}
+
+// remove removes the entry if it is still the cached one.
+func (c *listCache) remove(key listCacheKey, entry *listCacheEntry) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.entries[key] == entry {
+		delete(c.entries, key)
+	}
+}
@@ -0,0 +1,179 @@
+// Copyright © 2020 Humio Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package humio
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	humio "github.com/humio/cli/api"
+)
+
+func TestCachedList(t *testing.T) {
+	cache := newListCache()
+	calls := map[string]int{}
+	list := func(repository string) ([]string, error) {
+		calls[repository]++
+		return []string{repository}, nil
+	}
+
+	for i := 0; i < 3; i++ {
+		got, err := cachedList(cache, "alerts", "sandbox", list)
+		if err != nil || len(got) != 1 || got[0] != "sandbox" {
+			t.Fatalf("cachedList() = %v, %v", got, err)
+		}
+	}
+	if calls["sandbox"] != 1 {
+		t.Errorf("listed sandbox %d times, want 1", calls["sandbox"])
+	}
+
+	if _, err := cachedList(cache, "alerts", "other", list); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := cachedList(cache, "actions", "sandbox", list); err != nil {
+		t.Fatal(err)
+	}
+	if calls["other"] != 1 || calls["sandbox"] != 2 {
+		t.Errorf("got calls %v, want each object type and repository listed once", calls)
+	}
+
+	cache.invalidate("alerts", "sandbox")
+	if _, err := cachedList(cache, "alerts", "sandbox", list); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := cachedList(cache, "alerts", "other", list); err != nil {
+		t.Fatal(err)
+	}
+	if calls["other"] != 1 || calls["sandbox"] != 3 {
+		t.Errorf("got calls %v, want only the invalidated list to be listed again", calls)
+	}
+}
+
+func TestCachedListErrorsAreNotCached(t *testing.T) {
+	cache := newListCache()
+	calls := 0
+	list := func(string) ([]string, error) {
+		calls++
+		if calls == 1 {
+			return nil, errors.New("connection refused")
+		}
+		return []string{"alert"}, nil
+	}
+
+	if _, err := cachedList(cache, "alerts", "sandbox", list); err == nil {
+		t.Fatal("expected the first list to fail")
+	}
+	got, err := cachedList(cache, "alerts", "sandbox", list)
+	if err != nil || len(got) != 1 {
+		t.Fatalf("cachedList() = %v, %v", got, err)
+	}
+	if calls != 2 {
+		t.Errorf("listed %d times, want 2", calls)
+	}
+}
+
+func TestCachedListNilCache(t *testing.T) {
+	calls := 0
+	list := func(string) ([]string, error) {
+		calls++
+		return nil, nil
+	}
+	var cache *listCache
+	for i := 0; i < 2; i++ {
+		if _, err := cachedList(cache, "alerts", "sandbox", list); err != nil {
+			t.Fatal(err)
+		}
+	}
+	cache.invalidate("alerts", "sandbox")
+	if calls != 2 {
+		t.Errorf("listed %d times, want 2", calls)
+	}
+}
+
+func TestCachedListConcurrent(t *testing.T) {
+	cache := newListCache()
+	var calls atomic.Int32
+	list := func(string) ([]string, error) {
+		calls.Add(1)
+		time.Sleep(20 * time.Millisecond)
+		return []string{"alert"}, nil
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if got, err := cachedList(cache, "alerts", "sandbox", list); err != nil || len(got) != 1 {
+				t.Errorf("cachedList() = %v, %v", got, err)
+			}
+		}()
+	}
+	wg.Wait()
+	if got := calls.Load(); got != 1 {
+		t.Errorf("listed %d times, want 1", got)
+	}
+}
+
+func TestProviderMetaListCache(t *testing.T) {
+	var lists atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if strings.Contains(string(body), "deleteFilterAlert") {
+			_, _ = io.WriteString(w, `{"data":{"deleteFilterAlert":true}}`)
+			return
+		}
+		lists.Add(1)
+		_, _ = io.WriteString(w, `{"data":{"searchDomain":{"filterAlerts":[{"id":"1","name":"a"},{"id":"2","name":"b"}]}}}`)
+	}))
+	defer server.Close()
+
+	address, err := url.Parse(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	meta := &providerMeta{
+		client: humio.NewClient(humio.Config{Address: address}),
+		lists:  newListCache(),
+	}
+
+	for _, name := range []string{"a", "b", "a"} {
+		alert, err := meta.filterAlerts().Get("sandbox", name)
+		if err != nil || alert.Name != name {
+			t.Fatalf("Get(%q) = %v, %v", name, alert, err)
+		}
+	}
+	if got := lists.Load(); got != 1 {
+		t.Errorf("listed %d times after reading, want 1", got)
+	}
+
+	if err := meta.filterAlerts().Delete("sandbox", "a"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := meta.filterAlerts().Get("sandbox", "b"); err != nil {
+		t.Fatal(err)
+	}
+	if got := lists.Load(); got != 2 {
+		t.Errorf("listed %d times after deleting, want 2", got)
+	}
+}
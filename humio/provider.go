@@ -16,14 +16,15 @@ package humio
 
 import (
 	"context"
-	"encoding/pem"
 	"fmt"
+	"log"
 	"net/url"
 	"strings"
 
 	"github.com/hashicorp/go-cty/cty"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 
 	humio "github.com/humio/cli/api"
 )
@@ -31,6 +32,46 @@ import (
 // tfMap is a shorthand alias for convenience; Terraform uses this type a *lot*.
 type tfMap = map[string]interface{}
 
+// providerMeta is the configured provider, which is passed to resources and data sources as their meta value.
+type providerMeta struct {
+	client *humio.Client
+	// skipQueryValidation disables validating queries against LogScale when planning.
+	skipQueryValidation bool
+	// lists caches the lists objects are read from, see listCache.
+	lists *listCache
+}
+
+// The clients below share the list cache of the provider, so they must be used for all reads and writes of their
+// objects.
+
+func (m *providerMeta) actions() *actions {
+	return &actions{client: m.client, lists: m.lists}
+}
+
+func (m *providerMeta) aggregateAlerts() *aggregateAlerts {
+	return &aggregateAlerts{client: m.client, lists: m.lists}
+}
+
+func (m *providerMeta) alerts() *alerts {
+	return &alerts{client: m.client, lists: m.lists}
+}
+
+func (m *providerMeta) dashboards() *dashboards {
+	return &dashboards{client: m.client, lists: m.lists}
+}
+
+func (m *providerMeta) filterAlerts() *filterAlerts {
+	return &filterAlerts{client: m.client, lists: m.lists}
+}
+
+func (m *providerMeta) savedQueries() *savedQueries {
+	return &savedQueries{client: m.client, lists: m.lists}
+}
+
+func (m *providerMeta) scheduledSearches() *scheduledSearches {
+	return &scheduledSearches{client: m.client, lists: m.lists}
+}
+
 func Provider() *schema.Provider {
 	return &schema.Provider{
 		ConfigureContextFunc: func(ctx context.Context, r *schema.ResourceData) (interface{}, diag.Diagnostics) {
@@ -40,32 +81,99 @@ func Provider() *schema.Provider {
 			if err != nil {
 				return nil, diag.FromErr(err)
 			}
-			caBundlePEM, ok := r.GetOk("ca_certificate_pem")
-			if ok {
-				pem, _ := pem.Decode([]byte(caBundlePEM.(string)))
-				if pem == nil {
-					return nil, diag.FromErr(fmt.Errorf("ca_certificate_pem specified but no pem was found"))
-				}
-				return humio.NewClient(humio.Config{
-					Address:          url,
-					Token:            r.Get("api_token").(string),
-					CACertificatePEM: caBundlePEM.(string),
-				}), diagnostics
+			meta := &providerMeta{
+				skipQueryValidation: r.Get("skip_query_validation").(bool),
+				lists:               newListCache(),
 			}
-
-			return humio.NewClient(humio.Config{
+			retries, err := retryConfigFromResourceData(r)
+			if err != nil {
+				return nil, diag.FromErr(err)
+			}
+			connection, err := connectionConfigFromResourceData(r)
+			if err != nil {
+				return nil, diag.FromErr(err)
+			}
+			auth, err := authenticationFromResourceData(ctx, r, connection.tokenClient())
+			if err != nil {
+				return nil, diag.Diagnostics{{
+					Severity: diag.Error,
+					Summary:  "Invalid authentication",
+					Detail:   err.Error(),
+				}}
+			}
+			config := humio.Config{
 				Address: url,
-				Token:   r.Get("api_token").(string),
-			}), diagnostics
+				Token:   auth.token,
+			}
+			if connection.tlsConfig.InsecureSkipVerify {
+				config.Insecure = true
+				diagnostics = append(diagnostics, diag.Diagnostic{
+					Severity: diag.Warning,
+					Summary:  "TLS certificate verification is disabled",
+					Detail:   "insecure_skip_verify is set, so the certificate of LogScale is not verified. Only use this for clusters where that is acceptable, e.g. lab clusters.",
+				})
+			}
+
+			limits := limitConfig{
+				maxConcurrentRequests: r.Get("max_concurrent_requests").(int),
+				requestsPerSecond:     r.Get("requests_per_second").(float64),
+			}
+			// Retries are limited as well, so they count towards the limits.
+			base := newLimitTransport(connection.transport(config), limits)
+			if auth.oauth2 != nil {
+				base = &oauth2Transport{base: base, source: auth.oauth2}
+			}
+			transport := newRetryTransport(ctx, base, retries)
+			meta.client = humio.NewClientWithTransport(config, transport)
+			return meta, diagnostics
+		},
+		DataSourcesMap: map[string]*schema.Resource{
+			"humio_action":        dataSourceAction(),
+			"humio_actions":       dataSourceActions(),
+			"humio_alert":         dataSourceAlert(),
+			"humio_alerts":        dataSourceAlerts(),
+			"humio_ingest_token":  dataSourceIngestToken(),
+			"humio_ingest_tokens": dataSourceIngestTokens(),
+			"humio_parser":        dataSourceParser(),
+			"humio_parsers":       dataSourceParsers(),
+			"humio_repository":    dataSourceRepository(),
+			"humio_repositories":  dataSourceRepositories(),
+			"humio_view":          dataSourceView(),
+			"humio_views":         dataSourceViews(),
 		},
 		ResourcesMap: map[string]*schema.Resource{
-			"humio_alert":        resourceAlert(),
-			"humio_ingest_token": resourceIngestToken(),
-			"humio_action":       resourceAction(),
-			"humio_parser":       resourceParser(),
-			"humio_repository":   resourceRepository(),
-			"humio_view":         resourceView(),
+			"humio_action":                    resourceAction(),
+			"humio_action_test_result":        resourceActionTestResult(),
+			"humio_aggregate_alert":           resourceAggregateAlert(),
+			"humio_alert":                     resourceAlert(),
+			"humio_dashboard":                 resourceDashboard(),
+			"humio_email_action":              resourceEmailAction(),
+			"humio_filter_alert":              resourceFilterAlert(),
+			"humio_group":                     resourceGroup(),
+			"humio_group_membership":          resourceGroupMembership(),
+			"humio_group_view_role":           resourceGroupViewRole(),
+			"humio_ingest_token":              resourceIngestToken(),
+			"humio_lookup_file":               resourceLookupFile(),
+			"humio_opsgenie_action":           resourceOpsGenieAction(),
+			"humio_organization_token":        resourceOrganizationToken(),
+			"humio_pagerduty_action":          resourcePagerDutyAction(),
+			"humio_parser":                    resourceParser(),
+			"humio_repository":                resourceRepository(),
+			"humio_repository_action":         resourceRepositoryAction(),
+			"humio_role":                      resourceRole(),
+			"humio_saved_query":               resourceSavedQuery(),
+			"humio_scheduled_search":          resourceScheduledSearch(),
+			"humio_slack_action":              resourceSlackAction(),
+			"humio_slack_post_message_action": resourceSlackPostMessageAction(),
+			"humio_system_token":              resourceSystemToken(),
+			"humio_upload_file_action":        resourceUploadFileAction(),
+			"humio_user":                      resourceUser(),
+			"humio_view":                      resourceView(),
+			"humio_view_token":                resourceViewToken(),
+			"humio_victorops_action":          resourceVictorOpsAction(),
+			"humio_webhook_action":            resourceWebhookAction(),
 		},
+
 		Schema: map[string]*schema.Schema{
 			"addr": {
 				Type:             schema.TypeString,
@@ -75,19 +183,160 @@ func Provider() *schema.Provider {
 			},
 			"api_token": {
 				Type:        schema.TypeString,
-				Required:    true,
+				Optional:    true,
 				Sensitive:   true,
 				DefaultFunc: schema.EnvDefaultFunc("HUMIO_API_TOKEN", nil),
 			},
+			"api_token_file": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("HUMIO_API_TOKEN_FILE", nil),
+			},
+			"api_token_command": {
+				Type:     schema.TypeList,
+				Optional: true,
+				MinItems: 1,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+			"oauth2": {
+				Type:     schema.TypeList,
+				Optional: true,
+				MaxItems: 1,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"token_url": {
+							Type:             schema.TypeString,
+							Required:         true,
+							ValidateDiagFunc: validateURL,
+						},
+						"client_id": {
+							Type:     schema.TypeString,
+							Required: true,
+						},
+						"client_secret": {
+							Type:      schema.TypeString,
+							Required:  true,
+							Sensitive: true,
+						},
+						"scopes": {
+							Type:     schema.TypeList,
+							Optional: true,
+							Elem:     &schema.Schema{Type: schema.TypeString},
+						},
+					},
+				},
+			},
 			"ca_certificate_pem": {
 				Type:        schema.TypeString,
 				Optional:    true,
 				DefaultFunc: schema.EnvDefaultFunc("HUMIO_CA_CERTIFICATE_PEM", nil),
 			},
+			"ca_certificate_file": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("HUMIO_CA_CERTIFICATE_FILE", nil),
+			},
+			"client_certificate": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("HUMIO_CLIENT_CERTIFICATE", nil),
+			},
+			"client_certificate_file": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("HUMIO_CLIENT_CERTIFICATE_FILE", nil),
+			},
+			"client_key": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Sensitive:   true,
+				DefaultFunc: schema.EnvDefaultFunc("HUMIO_CLIENT_KEY", nil),
+			},
+			"client_key_file": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("HUMIO_CLIENT_KEY_FILE", nil),
+			},
+			"insecure_skip_verify": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("HUMIO_INSECURE_SKIP_VERIFY", false),
+			},
+			"proxy_url": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				DefaultFunc:      schema.EnvDefaultFunc("HUMIO_PROXY_URL", nil),
+				ValidateDiagFunc: validateURL,
+			},
+			"headers": {
+				Type:     schema.TypeMap,
+				Optional: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+			"skip_query_validation": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("HUMIO_SKIP_QUERY_VALIDATION", false),
+			},
+			"max_retries": {
+				Type:             schema.TypeInt,
+				Optional:         true,
+				Default:          5,
+				ValidateDiagFunc: validation.ToDiagFunc(validation.IntAtLeast(0)),
+			},
+			"min_backoff": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				Default:          "1s",
+				ValidateDiagFunc: validateDuration,
+			},
+			"max_backoff": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				Default:          "10s",
+				ValidateDiagFunc: validateDuration,
+			},
+			"respect_retry_after": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  true,
+			},
+			"max_concurrent_requests": {
+				Type:             schema.TypeInt,
+				Optional:         true,
+				Default:          0,
+				ValidateDiagFunc: validation.ToDiagFunc(validation.IntAtLeast(0)),
+			},
+			"requests_per_second": {
+				Type:             schema.TypeFloat,
+				Optional:         true,
+				Default:          0.0,
+				ValidateDiagFunc: validation.ToDiagFunc(validation.FloatAtLeast(0)),
+			},
 		},
 	}
 }
 
+func retryConfigFromResourceData(r *schema.ResourceData) (retryConfig, error) {
+	minBackoff, err := parseDuration(r.Get("min_backoff").(string))
+	if err != nil {
+		return retryConfig{}, fmt.Errorf("invalid min_backoff: %w", err)
+	}
+	maxBackoff, err := parseDuration(r.Get("max_backoff").(string))
+	if err != nil {
+		return retryConfig{}, fmt.Errorf("invalid max_backoff: %w", err)
+	}
+	if maxBackoff < minBackoff {
+		return retryConfig{}, fmt.Errorf("max_backoff %s must not be less than min_backoff %s", r.Get("max_backoff"), r.Get("min_backoff"))
+	}
+	return retryConfig{
+		maxRetries:        r.Get("max_retries").(int),
+		minBackoff:        minBackoff,
+		maxBackoff:        maxBackoff,
+		respectRetryAfter: r.Get("respect_retry_after").(bool),
+	}, nil
+}
+
 func validateURL(val interface{}, key cty.Path) diag.Diagnostics {
 	var diagnostics diag.Diagnostics
 	v := val.(string)
@@ -117,6 +366,54 @@ func validateURL(val interface{}, key cty.Path) diag.Diagnostics {
 	return diagnostics
 }
 
+// validateQueryDiff returns a CustomizeDiffFunc that validates the query in the given attribute against the
+// repository of the resource whenever either changes, unless the provider is configured with skip_query_validation.
+// Queries are not validated while the repository is unknown or does not exist yet.
+func validateQueryDiff(attribute string) schema.CustomizeDiffFunc {
+	return func(_ context.Context, d *schema.ResourceDiff, meta interface{}) error {
+		m, ok := meta.(*providerMeta)
+		if !ok || m.skipQueryValidation {
+			return nil
+		}
+		if !d.HasChanges("repository", attribute) || !d.NewValueKnown("repository") || !d.NewValueKnown(attribute) {
+			return nil
+		}
+
+		repository := d.Get("repository").(string)
+		diagnostics, err := newQueries(m.client).Validate(repository, d.Get(attribute).(string))
+		if err != nil {
+			if isNotFound(searchDomainNotFoundOr(m.client, repository, err)) {
+				// The repository is created in the same apply, so LogScale validates the query when it is created.
+				log.Printf("[WARN] repository %s not found, not validating %s", repository, attribute)
+				return nil
+			}
+			return fmt.Errorf("could not validate %s against repository %s, set skip_query_validation on the provider to skip validation: %w", attribute, repository, err)
+		}
+		return queryValidationError(attribute, repository, diagnostics)
+	}
+}
+
+// queryValidationError returns an error listing the diagnostics that make the query in the attribute invalid, or nil
+// if there are none. Other diagnostics are only logged.
+func queryValidationError(attribute, repository string, diagnostics []QueryDiagnostic) error {
+	var problems []string
+	for _, diagnostic := range diagnostics {
+		if diagnostic.Severity != QuerySeverityError {
+			log.Printf("[WARN] %s in repository %s: %s (%s)", attribute, repository, diagnostic.Message, diagnostic.Code)
+			continue
+		}
+		problem := fmt.Sprintf("%s (%s)", diagnostic.Message, diagnostic.Code)
+		if diagnostic.Position != nil {
+			problem = fmt.Sprintf("line %d, column %d: %s", diagnostic.Position.Line, diagnostic.Position.Column, problem)
+		}
+		problems = append(problems, problem)
+	}
+	if len(problems) == 0 {
+		return nil
+	}
+	return fmt.Errorf("%s is not a valid query in repository %s:\n  %s", attribute, repository, strings.Join(problems, "\n  "))
+}
+
 func parseRepositoryAndID(fullIdentifier string) [2]string {
 	var repository, id string
 	parts := strings.SplitN(fullIdentifier, "+", 2)
@@ -0,0 +1,147 @@
+// Copyright © 2020 Humio Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package humio
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/hashicorp/go-cty/cty"
+	ctyjson "github.com/hashicorp/go-cty/cty/json"
+	"github.com/hashicorp/go-cty/cty/msgpack"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// ProviderServer returns the gRPC server of the provider. On top of the server of the plugin SDK, it supports moving
+// humio_action resources to the typed action resources with moved blocks, which the plugin SDK does not.
+func ProviderServer() tfprotov5.ProviderServer {
+	provider := Provider()
+	return &providerServer{
+		GRPCProviderServer: schema.NewGRPCProviderServer(provider),
+		provider:           provider,
+	}
+}
+
+type providerServer struct {
+	*schema.GRPCProviderServer
+	provider *schema.Provider
+}
+
+func (s *providerServer) GetMetadata(ctx context.Context, req *tfprotov5.GetMetadataRequest) (*tfprotov5.GetMetadataResponse, error) {
+	resp, err := s.GRPCProviderServer.GetMetadata(ctx, req)
+	if resp != nil {
+		resp.ServerCapabilities = withMoveResourceState(resp.ServerCapabilities)
+	}
+	return resp, err
+}
+
+func (s *providerServer) GetProviderSchema(ctx context.Context, req *tfprotov5.GetProviderSchemaRequest) (*tfprotov5.GetProviderSchemaResponse, error) {
+	resp, err := s.GRPCProviderServer.GetProviderSchema(ctx, req)
+	if resp != nil {
+		resp.ServerCapabilities = withMoveResourceState(resp.ServerCapabilities)
+	}
+	return resp, err
+}
+
+func withMoveResourceState(capabilities *tfprotov5.ServerCapabilities) *tfprotov5.ServerCapabilities {
+	if capabilities == nil {
+		capabilities = &tfprotov5.ServerCapabilities{}
+	}
+	capabilities.MoveResourceState = true
+	return capabilities
+}
+
+func (s *providerServer) MoveResourceState(_ context.Context, req *tfprotov5.MoveResourceStateRequest) (*tfprotov5.MoveResourceStateResponse, error) {
+	resp := &tfprotov5.MoveResourceStateResponse{}
+
+	actionType, ok := typedActionResources[req.TargetTypeName]
+	if req.SourceTypeName != "humio_action" || !ok {
+		resp.Diagnostics = append(resp.Diagnostics, &tfprotov5.Diagnostic{
+			Severity: tfprotov5.DiagnosticSeverityError,
+			Summary:  "Unsupported resource move",
+			Detail:   fmt.Sprintf("Moving %s to %s is not supported. Only humio_action can be moved, to the typed action resources.", req.SourceTypeName, req.TargetTypeName),
+		})
+		return resp, nil
+	}
+	if req.SourceState == nil || req.SourceState.JSON == nil {
+		resp.Diagnostics = append(resp.Diagnostics, &tfprotov5.Diagnostic{
+			Severity: tfprotov5.DiagnosticSeverityError,
+			Summary:  "Unsupported resource move",
+			Detail:   "The state of the humio_action resource is missing or in an unsupported format.",
+		})
+		return resp, nil
+	}
+
+	ty := s.provider.ResourcesMap[req.TargetTypeName].CoreConfigSchema().ImpliedType()
+	state, err := moveActionState(req.SourceState.JSON, actionType, ty)
+	if err == nil {
+		resp.TargetState, err = encodeDynamicValue(state, ty)
+	}
+	if err != nil {
+		resp.Diagnostics = append(resp.Diagnostics, &tfprotov5.Diagnostic{
+			Severity: tfprotov5.DiagnosticSeverityError,
+			Summary:  "Could not move humio_action",
+			Detail:   err.Error(),
+		})
+		return resp, nil
+	}
+	resp.TargetPrivate = req.SourcePrivate
+
+	return resp, nil
+}
+
+// moveActionState converts the JSON state of a humio_action to the JSON state of the typed action resource of the
+// given action type, whose state has the given type.
+func moveActionState(source []byte, actionType string, ty cty.Type) ([]byte, error) {
+	var action map[string]interface{}
+	if err := json.Unmarshal(source, &action); err != nil {
+		return nil, fmt.Errorf("could not decode the state of humio_action: %w", err)
+	}
+	if action["type"] != actionType {
+		return nil, fmt.Errorf("the action is of type %v, but the target resource only manages actions of type %s", action["type"], actionType)
+	}
+
+	state := map[string]interface{}{}
+	for _, k := range []string{"id", "action_id", "repository", "name", "secret_wo_version", "verify_on_apply"} {
+		state[k] = action[k]
+	}
+	if settings, ok := action[actionBlocks[actionType].name].([]interface{}); ok && len(settings) > 0 {
+		for k, v := range settings[0].(map[string]interface{}) {
+			state[k] = v
+		}
+	}
+	// Attributes humio_action has but the typed resource does not are dropped.
+	for k := range state {
+		if !ty.HasAttribute(k) {
+			delete(state, k)
+		}
+	}
+
+	return json.Marshal(state)
+}
+
+func encodeDynamicValue(state []byte, ty cty.Type) (*tfprotov5.DynamicValue, error) {
+	val, err := ctyjson.Unmarshal(state, ty)
+	if err != nil {
+		return nil, err
+	}
+	b, err := msgpack.Marshal(val, ty)
+	if err != nil {
+		return nil, err
+	}
+	return &tfprotov5.DynamicValue{MsgPack: b}, nil
+}
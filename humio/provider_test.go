@@ -15,10 +15,22 @@
 package humio
 
 import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
 	"os"
 	"strconv"
+	"strings"
+	"sync/atomic"
 	"testing"
 
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	humio "github.com/humio/cli/api"
+
 	"github.com/humio/terraform-provider-humio/humio/acceptance"
 )
 
@@ -28,6 +40,126 @@ func TestProviderInternalValidation(t *testing.T) {
 	}
 }
 
+func TestQueryValidationError(t *testing.T) {
+	if err := queryValidationError("query", "sandbox", nil); err != nil {
+		t.Errorf("expected no error without diagnostics, got %s", err)
+	}
+
+	warnings := []QueryDiagnostic{{Message: "Deprecated function", Code: "DeprecatedFunction", Severity: "Warning"}}
+	if err := queryValidationError("query", "sandbox", warnings); err != nil {
+		t.Errorf("expected no error for warnings, got %s", err)
+	}
+
+	errors := append(warnings,
+		QueryDiagnostic{Message: "Unknown function nosuchfunction", Code: "UnknownFunction", Severity: QuerySeverityError},
+		QueryDiagnostic{Message: "Unexpected end of query", Code: "ParseError", Severity: QuerySeverityError},
+	)
+	err := queryValidationError("query", "sandbox", errors)
+	want := "query is not a valid query in repository sandbox:\n" +
+		"  Unknown function nosuchfunction (UnknownFunction)\n" +
+		"  Unexpected end of query (ParseError)"
+	if err == nil || err.Error() != want {
+		t.Errorf("got error %v, want %q", err, want)
+	}
+}
+
+func TestQueryValidationErrorPositions(t *testing.T) {
+	errors := []QueryDiagnostic{
+		{Message: "Unknown function nosuchfunction", Code: "UnknownFunction", Severity: QuerySeverityError, Position: &QueryPosition{Line: 2, Column: 3}},
+	}
+	err := queryValidationError("query", "sandbox", errors)
+	want := "query is not a valid query in repository sandbox:\n" +
+		"  line 2, column 3: Unknown function nosuchfunction (UnknownFunction)"
+	if err == nil || err.Error() != want {
+		t.Errorf("got error %v, want %q", err, want)
+	}
+}
+
+// newQueryValidationTestMeta returns provider meta for a LogScale with the repository sandbox, in which only the query
+// "count()" is valid. If positions is false, LogScale does not know the positions of diagnostics. It also returns the
+// number of queries LogScale validated.
+func newQueryValidationTestMeta(t *testing.T, positions bool) (*providerMeta, *atomic.Int32) {
+	t.Helper()
+	var validations atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		switch request := string(body); {
+		case strings.Contains(request, "searchDomains"):
+			_, _ = io.WriteString(w, `{"data":{"searchDomains":[{"name":"sandbox","__typename":"Repository"}]}}`)
+		case strings.Contains(request, "position") && !positions:
+			_, _ = io.WriteString(w, `{"errors":[{"message":"Cannot query field 'position' on type 'QueryDiagnostic'."}]}`)
+		case !strings.Contains(request, `"viewName":"sandbox"`):
+			validations.Add(1)
+			_, _ = io.WriteString(w, `{"errors":[{"message":"Could not find a view or repository"}]}`)
+		case strings.Contains(request, `"queryString":"count()"`):
+			validations.Add(1)
+			_, _ = io.WriteString(w, `{"data":{"analyzeQuery":{"validateQuery":{"diagnostics":[]}}}}`)
+		case positions:
+			validations.Add(1)
+			_, _ = io.WriteString(w, `{"data":{"analyzeQuery":{"validateQuery":{"diagnostics":[{"message":"Unknown function","code":"UnknownFunction","severity":"Error","position":{"line":1,"column":1}}]}}}}`)
+		default:
+			validations.Add(1)
+			_, _ = io.WriteString(w, `{"data":{"analyzeQuery":{"validateQuery":{"diagnostics":[{"message":"Unknown function","code":"UnknownFunction","severity":"Error"}]}}}}`)
+		}
+	}))
+	t.Cleanup(server.Close)
+	address, err := url.Parse(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &providerMeta{client: humio.NewClient(humio.Config{Address: address})}, &validations
+}
+
+func TestValidateQueryDiff(t *testing.T) {
+	r := resourceFilterAlert()
+	block := schema.InternalMap(r.Schema).CoreConfigSchema()
+	diff := func(meta *providerMeta, repository cty.Value, query string) error {
+		t.Helper()
+		values := map[string]cty.Value{}
+		for name, attributeType := range block.ImpliedType().AttributeTypes() {
+			values[name] = cty.NullVal(attributeType)
+		}
+		values["repository"] = repository
+		values["name"] = cty.StringVal("test")
+		values["query"] = cty.StringVal(query)
+		_, err := r.Diff(context.Background(), nil, terraform.NewResourceConfigShimmed(cty.ObjectVal(values), block), meta)
+		return err
+	}
+
+	tests := []struct {
+		name       string
+		positions  bool
+		repository cty.Value
+		query      string
+		wantErr    string
+		validated  bool
+	}{
+		{name: "valid query", positions: true, repository: cty.StringVal("sandbox"), query: "count()", validated: true},
+		{name: "invalid query", positions: true, repository: cty.StringVal("sandbox"), query: "nosuchfunction()", validated: true,
+			wantErr: "line 1, column 1: Unknown function (UnknownFunction)"},
+		{name: "invalid query without positions", positions: false, repository: cty.StringVal("sandbox"), query: "nosuchfunction()", validated: true,
+			wantErr: "query is not a valid query in repository sandbox:\n  Unknown function (UnknownFunction)"},
+		// The repository is created in the same apply.
+		{name: "missing repository", positions: true, repository: cty.StringVal("new"), query: "nosuchfunction()", validated: true},
+		{name: "unknown repository", positions: true, repository: cty.UnknownVal(cty.String), query: "nosuchfunction()"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			meta, validations := newQueryValidationTestMeta(t, test.positions)
+			err := diff(meta, test.repository, test.query)
+			if test.wantErr == "" && err != nil {
+				t.Errorf("expected no error, got %s", err)
+			}
+			if test.wantErr != "" && (err == nil || !strings.Contains(err.Error(), test.wantErr)) {
+				t.Errorf("got error %v, want it to contain %q", err, test.wantErr)
+			}
+			if got := validations.Load() > 0; got != test.validated {
+				t.Errorf("got validated %v, want %v", got, test.validated)
+			}
+		})
+	}
+}
+
 func TestMain(m *testing.M) {
 	if tfAccVal, ok := os.LookupEnv("TF_ACC"); ok {
 		// Check for presence in the environment
@@ -48,6 +180,10 @@ func TestMain(m *testing.M) {
 					return m.Run()
 				})
 			}
+			return
 		}
 	}
+
+	// Without TF_ACC the acceptance tests skip themselves, so only the unit tests run.
+	m.Run()
 }
@@ -17,9 +17,11 @@ package humio
 import (
 	"context"
 	"fmt"
+	"log"
 	"net/http"
 	"reflect"
 	"regexp"
+	"sort"
 
 	"github.com/hashicorp/go-cty/cty"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
@@ -29,6 +31,19 @@ import (
 	humio "github.com/humio/cli/api"
 )
 
+// actionTypes are the action types supported by humio_action.
+var actionTypes = []string{
+	humio.ActionTypeEmail,
+	humio.ActionTypeHumioRepo,
+	humio.ActionTypeOpsGenie,
+	humio.ActionTypePagerDuty,
+	humio.ActionTypeSlack,
+	humio.ActionTypeSlackPostMessage,
+	humio.ActionTypeVictorOps,
+	humio.ActionTypeWebhook,
+	ActionTypeUploadFile,
+}
+
 var rxEmail = regexp.MustCompile("^[a-zA-Z0-9.!#$%&'*+\\/=?^_`{|}~-]+@[a-zA-Z0-9](?:[a-zA-Z0-9-]{0,61}[a-zA-Z0-9])?(?:\\.[a-zA-Z0-9](?:[a-zA-Z0-9-]{0,61}[a-zA-Z0-9])?)*$")
 
 func resourceAction() *schema.Resource {
@@ -40,6 +55,7 @@ func resourceAction() *schema.Resource {
 		Importer: &schema.ResourceImporter{
 			StateContext: schema.ImportStatePassthroughContext,
 		},
+		CustomizeDiff: resourceActionCustomizeDiff,
 
 		Schema: map[string]*schema.Schema{
 			"action_id": {
@@ -51,28 +67,49 @@ func resourceAction() *schema.Resource {
 				Required: true,
 			},
 			"type": {
-				Type:     schema.TypeString,
-				Required: true,
-				ForceNew: true,
-				ValidateDiagFunc: validation.ToDiagFunc(validation.StringInSlice([]string{
-					humio.ActionTypeEmail,
-					humio.ActionTypeHumioRepo,
-					humio.ActionTypeOpsGenie,
-					humio.ActionTypePagerDuty,
-					humio.ActionTypeSlack,
-					humio.ActionTypeSlackPostMessage,
-					humio.ActionTypeVictorOps,
-					humio.ActionTypeWebhook,
-				}, false)),
+				Type:             schema.TypeString,
+				Required:         true,
+				ForceNew:         true,
+				ValidateDiagFunc: validation.ToDiagFunc(validation.StringInSlice(actionTypes, false)),
 			},
 			"name": {
 				Type:     schema.TypeString,
 				Required: true,
 			},
+			// secret_wo and webhook_headers_wo are write-only: they are read from the configuration on create and
+			// update, but their diffs are always suppressed so they are never stored in state. Changing
+			// secret_wo_version is what makes Terraform send them again.
+			"secret_wo": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				Sensitive:        true,
+				RequiredWith:     []string{"secret_wo_version"},
+				DiffSuppressFunc: suppressWriteOnlyDiff,
+			},
+			"webhook_headers_wo": {
+				Type:             schema.TypeMap,
+				Optional:         true,
+				Sensitive:        true,
+				RequiredWith:     []string{"secret_wo_version"},
+				DiffSuppressFunc: suppressWriteOnlyDiff,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"secret_wo_version": {
+				Type:             schema.TypeInt,
+				Optional:         true,
+				ValidateDiagFunc: validation.ToDiagFunc(validation.IntAtLeast(1)),
+			},
+			"verify_on_apply": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
 			"email": {
 				Type:          schema.TypeSet,
 				MaxItems:      1,
-				ConflictsWith: []string{"humiorepo", "opsgenie", "pagerduty", "slack", "slackpostmessage", "victorops", "webhook"},
+				ConflictsWith: []string{"humiorepo", "opsgenie", "pagerduty", "slack", "slackpostmessage", "victorops", "webhook", "upload_file"},
 				Optional:      true,
 				Elem: &schema.Resource{
 					Schema: map[string]*schema.Schema{
@@ -99,19 +136,30 @@ func resourceAction() *schema.Resource {
 							Type:     schema.TypeString,
 							Optional: true,
 						},
+						"attach_csv": {
+							Type:     schema.TypeBool,
+							Optional: true,
+							Default:  false,
+						},
+						"use_proxy": {
+							Type:     schema.TypeBool,
+							Optional: true,
+							Default:  false,
+						},
 					},
 				},
 			},
 			"humiorepo": {
 				Type:          schema.TypeSet,
 				MaxItems:      1,
-				ConflictsWith: []string{"email", "opsgenie", "pagerduty", "slack", "slackpostmessage", "victorops", "webhook"},
+				ConflictsWith: []string{"email", "opsgenie", "pagerduty", "slack", "slackpostmessage", "victorops", "webhook", "upload_file"},
 				Optional:      true,
 				Elem: &schema.Resource{
 					Schema: map[string]*schema.Schema{
 						"ingest_token": {
-							Type:     schema.TypeString,
-							Required: true,
+							Type:      schema.TypeString,
+							Optional:  true,
+							Sensitive: true,
 						},
 					},
 				},
@@ -119,7 +167,7 @@ func resourceAction() *schema.Resource {
 			"opsgenie": {
 				Type:          schema.TypeSet,
 				MaxItems:      1,
-				ConflictsWith: []string{"email", "humiorepo", "pagerduty", "slack", "slackpostmessage", "victorops", "webhook"},
+				ConflictsWith: []string{"email", "humiorepo", "pagerduty", "slack", "slackpostmessage", "victorops", "webhook", "upload_file"},
 				Optional:      true,
 				Elem: &schema.Resource{
 					Schema: map[string]*schema.Schema{
@@ -130,8 +178,14 @@ func resourceAction() *schema.Resource {
 							ValidateDiagFunc: validateURL,
 						},
 						"genie_key": {
-							Type:     schema.TypeString,
-							Required: true,
+							Type:      schema.TypeString,
+							Optional:  true,
+							Sensitive: true,
+						},
+						"use_proxy": {
+							Type:     schema.TypeBool,
+							Optional: true,
+							Default:  false,
 						},
 					},
 				},
@@ -139,13 +193,14 @@ func resourceAction() *schema.Resource {
 			"pagerduty": {
 				Type:          schema.TypeSet,
 				MaxItems:      1,
-				ConflictsWith: []string{"email", "humiorepo", "opsgenie", "slack", "slackpostmessage", "victorops", "webhook"},
+				ConflictsWith: []string{"email", "humiorepo", "opsgenie", "slack", "slackpostmessage", "victorops", "webhook", "upload_file"},
 				Optional:      true,
 				Elem: &schema.Resource{
 					Schema: map[string]*schema.Schema{
 						"routing_key": {
-							Type:     schema.TypeString,
-							Required: true,
+							Type:      schema.TypeString,
+							Optional:  true,
+							Sensitive: true,
 						},
 						"severity": {
 							Type:     schema.TypeString,
@@ -157,13 +212,18 @@ func resourceAction() *schema.Resource {
 								"info",
 							}, false)),
 						},
+						"use_proxy": {
+							Type:     schema.TypeBool,
+							Optional: true,
+							Default:  false,
+						},
 					},
 				},
 			},
 			"slack": {
 				Type:          schema.TypeSet,
 				MaxItems:      1,
-				ConflictsWith: []string{"email", "humiorepo", "opsgenie", "pagerduty", "slackpostmessage", "victorops", "webhook"},
+				ConflictsWith: []string{"email", "humiorepo", "opsgenie", "pagerduty", "slackpostmessage", "victorops", "webhook", "upload_file"},
 				Optional:      true,
 				Elem: &schema.Resource{
 					Schema: map[string]*schema.Schema{
@@ -176,22 +236,29 @@ func resourceAction() *schema.Resource {
 						},
 						"url": {
 							Type:             schema.TypeString,
-							Required:         true,
+							Optional:         true,
+							Sensitive:        true,
 							ValidateDiagFunc: validateURL,
 						},
+						"use_proxy": {
+							Type:     schema.TypeBool,
+							Optional: true,
+							Default:  false,
+						},
 					},
 				},
 			},
 			"slackpostmessage": {
 				Type:          schema.TypeSet,
 				MaxItems:      1,
-				ConflictsWith: []string{"email", "humiorepo", "opsgenie", "pagerduty", "slack", "victorops", "webhook"},
+				ConflictsWith: []string{"email", "humiorepo", "opsgenie", "pagerduty", "slack", "victorops", "webhook", "upload_file"},
 				Optional:      true,
 				Elem: &schema.Resource{
 					Schema: map[string]*schema.Schema{
 						"api_token": {
-							Type:     schema.TypeString,
-							Required: true,
+							Type:      schema.TypeString,
+							Optional:  true,
+							Sensitive: true,
 						},
 						"channels": {
 							Type:     schema.TypeList,
@@ -216,10 +283,33 @@ func resourceAction() *schema.Resource {
 					},
 				},
 			},
+			"upload_file": {
+				Type:          schema.TypeSet,
+				MaxItems:      1,
+				ConflictsWith: []string{"email", "humiorepo", "opsgenie", "pagerduty", "slack", "slackpostmessage", "victorops", "webhook"},
+				Optional:      true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"file_name": {
+							Type:     schema.TypeString,
+							Required: true,
+						},
+						"update_mode": {
+							Type:     schema.TypeString,
+							Optional: true,
+							Default:  "Overwrite",
+							ValidateDiagFunc: validation.ToDiagFunc(validation.StringInSlice([]string{
+								"Overwrite",
+								"Append",
+							}, false)),
+						},
+					},
+				},
+			},
 			"victorops": {
 				Type:          schema.TypeSet,
 				MaxItems:      1,
-				ConflictsWith: []string{"email", "humiorepo", "opsgenie", "pagerduty", "slack", "slackpostmessage", "webhook"},
+				ConflictsWith: []string{"email", "humiorepo", "opsgenie", "pagerduty", "slack", "slackpostmessage", "webhook", "upload_file"},
 				Optional:      true,
 				Elem: &schema.Resource{
 					Schema: map[string]*schema.Schema{
@@ -232,13 +322,18 @@ func resourceAction() *schema.Resource {
 							Required:         true,
 							ValidateDiagFunc: validateURL,
 						},
+						"use_proxy": {
+							Type:     schema.TypeBool,
+							Optional: true,
+							Default:  false,
+						},
 					},
 				},
 			},
 			"webhook": {
 				Type:          schema.TypeSet,
 				MaxItems:      1,
-				ConflictsWith: []string{"email", "humiorepo", "opsgenie", "pagerduty", "slack", "slackpostmessage", "victorops"},
+				ConflictsWith: []string{"email", "humiorepo", "opsgenie", "pagerduty", "slack", "slackpostmessage", "victorops", "upload_file"},
 				Optional:      true,
 				Elem: &schema.Resource{
 					Schema: map[string]*schema.Schema{
@@ -248,8 +343,9 @@ func resourceAction() *schema.Resource {
 							Default:  "{\n  \"repository\": \"{repo_name}\",\n  \"timestamp\": \"{alert_triggered_timestamp}\",\n  \"alert\": {\n    \"name\": \"{alert_name}\",\n    \"description\": \"{alert_description}\",\n    \"query\": {\n      \"queryString\": \"{query_string} \",\n      \"end\": \"{query_time_end}\",\n      \"start\": \"{query_time_start}\"\n    },\n    \"actionID\": \"{alert_action_id}\",\n    \"id\": \"{alert_id}\"\n  },\n  \"warnings\": \"{warnings}\",\n  \"events\": {events},\n  \"numberOfEvents\": {event_count}\n  }",
 						},
 						"headers": {
-							Type:     schema.TypeMap,
-							Required: true,
+							Type:      schema.TypeMap,
+							Optional:  true,
+							Sensitive: true,
 							Elem: &schema.Schema{
 								Type: schema.TypeString,
 							},
@@ -269,6 +365,21 @@ func resourceAction() *schema.Resource {
 							Required:         true,
 							ValidateDiagFunc: validateURL,
 						},
+						"ignore_ssl": {
+							Type:     schema.TypeBool,
+							Optional: true,
+							Default:  false,
+						},
+						"use_proxy": {
+							Type:     schema.TypeBool,
+							Optional: true,
+							Default:  false,
+						},
+						"ca_certificate": {
+							Type:             schema.TypeString,
+							Optional:         true,
+							ValidateDiagFunc: validateCertificatePEM,
+						},
 					},
 				},
 			},
@@ -282,7 +393,7 @@ func resourceActionCreate(ctx context.Context, d *schema.ResourceData, client in
 		return diag.Errorf("could not obtain action from resource data: %s", err)
 	}
 
-	a, err := client.(*humio.Client).Actions().Add(
+	a, err := client.(*providerMeta).actions().Add(
 		d.Get("repository").(string),
 		&action,
 	)
@@ -291,6 +402,12 @@ func resourceActionCreate(ctx context.Context, d *schema.ResourceData, client in
 	}
 	d.SetId(fmt.Sprintf("%s+%s", d.Get("repository").(string), a.Name))
 
+	if d.Get("verify_on_apply").(bool) {
+		if diags := verifyAction(client.(*providerMeta).client, d.Get("repository").(string), a); diags.HasError() {
+			return diags
+		}
+	}
+
 	return resourceActionRead(ctx, d, client)
 }
 
@@ -312,17 +429,22 @@ func resourceActionRead(_ context.Context, d *schema.ResourceData, client interf
 		}
 	}
 
-	action, err := client.(*humio.Client).Actions().Get(
+	action, err := client.(*providerMeta).actions().Get(
 		d.Get("repository").(string),
 		d.Get("name").(string),
 	)
-	if err != nil || reflect.DeepEqual(*action, humio.Action{}) {
+	if isNotFound(err) {
+		log.Printf("[WARN] action %s not found, removing from state", d.Id())
+		d.SetId("")
+		return nil
+	}
+	if err != nil || reflect.DeepEqual(*action, Action{}) {
 		return diag.Errorf("could not get action: %s", err)
 	}
 	return resourceDataFromAction(action, d)
 }
 
-func resourceDataFromAction(a *humio.Action, d *schema.ResourceData) diag.Diagnostics {
+func resourceDataFromAction(a *Action, d *schema.ResourceData) diag.Diagnostics {
 	err := d.Set("action_id", a.ID)
 	if err != nil {
 		return diag.Errorf("could not set action_id for action: %s", err)
@@ -336,43 +458,25 @@ func resourceDataFromAction(a *humio.Action, d *schema.ResourceData) diag.Diagno
 		return diag.Errorf("could not set type for action: %s", err)
 	}
 
-	switch a.Type {
-	case humio.ActionTypeEmail:
-		if err := d.Set("email", emailFromAction(a)); err != nil {
-			return diag.Errorf("error setting email settings for resource %s: %s", d.Id(), err)
-		}
-	case humio.ActionTypeHumioRepo:
-		if err := d.Set("humiorepo", humiorepoFromAction(a)); err != nil {
-			return diag.Errorf("error setting humiorepo settings for resource %s: %s", d.Id(), err)
-		}
-	case humio.ActionTypeOpsGenie:
-		if err := d.Set("opsgenie", opsgenieFromAction(a)); err != nil {
-			return diag.Errorf("error setting opsgenie settings for resource %s: %s", d.Id(), err)
-		}
-	case humio.ActionTypePagerDuty:
-		if err := d.Set("pagerduty", pagerdutyFromAction(a)); err != nil {
-			return diag.Errorf("error setting pagerduty settings for resource %s: %s", d.Id(), err)
-		}
-	case humio.ActionTypeSlack:
-		if err := d.Set("slack", slackFromAction(a)); err != nil {
-			return diag.Errorf("error setting slack settings for resource %s: %s", d.Id(), err)
-		}
-	case humio.ActionTypeSlackPostMessage:
-		if err := d.Set("slackpostmessage", slackpostmessageFromAction(a)); err != nil {
-			return diag.Errorf("error setting slackpostmessage settings for resource %s: %s", d.Id(), err)
-		}
-	case humio.ActionTypeVictorOps:
-		if err := d.Set("victorops", victoropsFromAction(a)); err != nil {
-			return diag.Errorf("error setting victorops settings for resource %s: %s", d.Id(), err)
-		}
-	case humio.ActionTypeWebhook:
-		if err := d.Set("webhook", webhookFromAction(a)); err != nil {
-			return diag.Errorf("error setting webhook settings for resource %s: %s", d.Id(), err)
-		}
-	default:
+	block, ok := actionBlocks[a.Type]
+	if !ok {
 		return diag.Errorf("unsupported action type: %s", a.Type)
 	}
 
+	// Secrets given through write-only attributes must not end up in state, so once those are in use we keep the
+	// secrets we already have in state instead of the ones returned by the server.
+	if version, ok := d.Get("secret_wo_version").(int); ok && version != 0 {
+		properties := tfMap{}
+		if list := d.Get(block.name).(*schema.Set).List(); len(list) > 0 {
+			properties = list[0].(tfMap)
+		}
+		a = withoutWriteOnlySecrets(a, properties)
+	}
+
+	if err := d.Set(block.name, block.fromAction(a)); err != nil {
+		return diag.Errorf("error setting %s settings for resource %s: %s", block.name, d.Id(), err)
+	}
+
 	return nil
 }
 
@@ -382,7 +486,7 @@ func resourceActionUpdate(ctx context.Context, d *schema.ResourceData, client in
 		return diag.Errorf("could not obtain action from resource data: %s", err)
 	}
 
-	_, err = client.(*humio.Client).Actions().Update(
+	a, err := client.(*providerMeta).actions().Update(
 		d.Get("repository").(string),
 		&action,
 	)
@@ -390,6 +494,12 @@ func resourceActionUpdate(ctx context.Context, d *schema.ResourceData, client in
 		return diag.Errorf("could not update action: %s", err)
 	}
 
+	if d.Get("verify_on_apply").(bool) {
+		if diags := verifyAction(client.(*providerMeta).client, d.Get("repository").(string), a); diags.HasError() {
+			return diags
+		}
+	}
+
 	return resourceActionRead(ctx, d, client)
 }
 
@@ -399,7 +509,7 @@ func resourceActionDelete(_ context.Context, d *schema.ResourceData, client inte
 		return diag.Errorf("could not obtain action from resource data: %s", err)
 	}
 
-	err = client.(*humio.Client).Actions().Delete(
+	err = client.(*providerMeta).actions().Delete(
 		d.Get("repository").(string),
 		action.Name,
 	)
@@ -409,101 +519,256 @@ func resourceActionDelete(_ context.Context, d *schema.ResourceData, client inte
 	return nil
 }
 
-// actionFromResourceData returns a humio.Action based on either the new change or the current state depending on update bool.
-func actionFromResourceData(d *schema.ResourceData) (humio.Action, error) {
-	action := humio.Action{
-		Type: d.Get("type").(string),
-		ID:   d.Get("action_id").(string),
-		Name: d.Get("name").(string),
+// verifyAction sends a test notification through the action, and fails if it could not be delivered.
+func verifyAction(client *humio.Client, repository string, a *Action) diag.Diagnostics {
+	result, err := newActions(client).Test(repository, a.ID)
+	if err != nil {
+		return diag.Errorf("could not send test notification through action %s: %s", a.Name, err)
+	}
+	if !result.Success {
+		return diag.Errorf("action %s was saved, but sending a test notification through it failed: %s", a.Name, result.Message)
 	}
+	return nil
+}
 
-	switch d.Get("type") {
+// actionFromResourceData returns an Action based on either the new change or the current state depending on update bool.
+func actionFromResourceData(d *schema.ResourceData) (Action, error) {
+	action := Action{
+		Action: humio.Action{
+			Type: d.Get("type").(string),
+			ID:   d.Get("action_id").(string),
+			Name: d.Get("name").(string),
+		},
+	}
+
+	block, ok := actionBlocks[action.Type]
+	if !ok {
+		return Action{}, fmt.Errorf("unsupported action type: %s", d.Get("type"))
+	}
+	properties := getActionPropertiesFromResourceData(d, block.name, block.requiredProperty)
+	actionSettingsFromProperties(&action, properties[0])
+
+	writeOnlySecretsFromConfig(d.GetRawConfig(), &action, "secret_wo", "webhook_headers_wo")
+
+	return action, nil
+}
+
+// actionSettingsFromProperties sets the settings of the action from the properties of the block of its action type,
+// which are also the top-level attributes of the typed action resources.
+func actionSettingsFromProperties(action *Action, properties tfMap) {
+	switch action.Type {
 	case humio.ActionTypeEmail:
-		properties := getActionPropertiesFromResourceData(d, "email", "recipients")
 		var recipients []string
-		for _, recipient := range properties[0]["recipients"].([]interface{}) {
+		for _, recipient := range properties["recipients"].([]interface{}) {
 			recipients = append(recipients, recipient.(string))
 		}
 		action.EmailAction = humio.EmailAction{
 			Recipients:      recipients,
-			BodyTemplate:    properties[0]["body_template"].(string),
-			SubjectTemplate: properties[0]["subject_template"].(string),
+			BodyTemplate:    properties["body_template"].(string),
+			SubjectTemplate: properties["subject_template"].(string),
+			UseProxy:        properties["use_proxy"].(bool),
 		}
+		action.EmailAttachCSV = properties["attach_csv"].(bool)
 	case humio.ActionTypeHumioRepo:
-		properties := getActionPropertiesFromResourceData(d, "humiorepo", "ingest_token")
 		action.HumioRepoAction = humio.HumioRepoAction{
-			IngestToken: properties[0]["ingest_token"].(string),
+			IngestToken: properties["ingest_token"].(string),
 		}
 	case humio.ActionTypeOpsGenie:
-		properties := getActionPropertiesFromResourceData(d, "opsgenie", "genie_key")
 		action.OpsGenieAction = humio.OpsGenieAction{
-			ApiUrl:   properties[0]["api_url"].(string),
-			GenieKey: properties[0]["genie_key"].(string),
+			ApiUrl:   properties["api_url"].(string),
+			GenieKey: properties["genie_key"].(string),
+			UseProxy: properties["use_proxy"].(bool),
 		}
 	case humio.ActionTypePagerDuty:
-		properties := getActionPropertiesFromResourceData(d, "pagerduty", "routing_key")
 		action.PagerDutyAction = humio.PagerDutyAction{
-			RoutingKey: properties[0]["routing_key"].(string),
-			Severity:   properties[0]["severity"].(string),
+			RoutingKey: properties["routing_key"].(string),
+			Severity:   properties["severity"].(string),
+			UseProxy:   properties["use_proxy"].(bool),
 		}
 	case humio.ActionTypeSlack:
-		properties := getActionPropertiesFromResourceData(d, "slack", "url")
 		fields := []humio.SlackFieldEntryInput{}
-		for fieldName, value := range properties[0]["fields"].(map[string]interface{}) {
+		for fieldName, value := range properties["fields"].(map[string]interface{}) {
 			fields = append(fields, humio.SlackFieldEntryInput{
 				FieldName: fieldName,
 				Value:     value.(string),
 			})
 		}
+		sortSlackFields(fields)
 		action.SlackAction = humio.SlackAction{
-			Url:    properties[0]["url"].(string),
-			Fields: fields,
+			Url:      properties["url"].(string),
+			Fields:   fields,
+			UseProxy: properties["use_proxy"].(bool),
 		}
 	case humio.ActionTypeSlackPostMessage:
-		properties := getActionPropertiesFromResourceData(d, "slackpostmessage", "api_token")
 		fields := []humio.SlackFieldEntryInput{}
-		for fieldName, value := range properties[0]["fields"].(map[string]interface{}) {
+		for fieldName, value := range properties["fields"].(map[string]interface{}) {
 			fields = append(fields, humio.SlackFieldEntryInput{
 				FieldName: fieldName,
 				Value:     value.(string),
 			})
 		}
+		sortSlackFields(fields)
 		channels := []string{}
-		for _, channel := range properties[0]["fields"].(map[string]interface{}) {
-			channels = append(channels, channel.(string));
+		for _, channel := range properties["channels"].([]interface{}) {
+			channels = append(channels, channel.(string))
 		}
 		action.SlackPostMessageAction = humio.SlackPostMessageAction{
-			ApiToken: properties[0]["api_token"].(string),
+			ApiToken: properties["api_token"].(string),
 			Channels: channels,
 			Fields:   fields,
-			UseProxy: properties[0]["use_proxy"].(bool),
+			UseProxy: properties["use_proxy"].(bool),
+		}
+	case ActionTypeUploadFile:
+		action.UploadFileAction = UploadFileAction{
+			FileName:   properties["file_name"].(string),
+			UpdateMode: properties["update_mode"].(string),
 		}
 	case humio.ActionTypeVictorOps:
-		properties := getActionPropertiesFromResourceData(d, "victorops", "notify_url")
 		action.VictorOpsAction = humio.VictorOpsAction{
-			MessageType: properties[0]["message_type"].(string),
-			NotifyUrl:   properties[0]["notify_url"].(string),
+			MessageType: properties["message_type"].(string),
+			NotifyUrl:   properties["notify_url"].(string),
+			UseProxy:    properties["use_proxy"].(bool),
 		}
 	case humio.ActionTypeWebhook:
-		properties := getActionPropertiesFromResourceData(d, "webhook", "url")
 		headers := []humio.HttpHeaderEntryInput{}
-		for header, value := range properties[0]["headers"].(map[string]interface{}) {
+		for header, value := range properties["headers"].(map[string]interface{}) {
 			headers = append(headers, humio.HttpHeaderEntryInput{
 				Header: header,
 				Value:  value.(string),
 			})
 		}
+		sort.Slice(headers, func(i, j int) bool { return headers[i].Header < headers[j].Header })
 		action.WebhookAction = humio.WebhookAction{
-			BodyTemplate: properties[0]["body_template"].(string),
+			BodyTemplate: properties["body_template"].(string),
 			Headers:      headers,
-			Method:       properties[0]["method"].(string),
-			Url:          properties[0]["url"].(string),
+			Method:       properties["method"].(string),
+			Url:          properties["url"].(string),
+			IgnoreSSL:    properties["ignore_ssl"].(bool),
+			UseProxy:     properties["use_proxy"].(bool),
 		}
-	default:
-		return humio.Action{}, fmt.Errorf("unsupported action type: %s", d.Get("type"))
+		action.WebhookCACertificate = properties["ca_certificate"].(string)
 	}
+}
 
-	return action, nil
+// sortSlackFields sorts slack fields by name, as they are stored in a map and would otherwise be sent in random order.
+func sortSlackFields(fields []humio.SlackFieldEntryInput) {
+	sort.Slice(fields, func(i, j int) bool { return fields[i].FieldName < fields[j].FieldName })
+}
+
+// actionBlock describes the block of humio_action holding the settings of an action type.
+type actionBlock struct {
+	name string
+	// requiredProperty is used to pick the right element in getActionPropertiesFromResourceData.
+	requiredProperty string
+	// secret is the property holding the secret of the action type that write-only attributes replace, if any.
+	secret     string
+	fromAction func(*Action) []tfMap
+}
+
+var actionBlocks = map[string]actionBlock{
+	humio.ActionTypeEmail:            {name: "email", requiredProperty: "recipients", fromAction: emailFromAction},
+	humio.ActionTypeHumioRepo:        {name: "humiorepo", requiredProperty: "ingest_token", secret: "ingest_token", fromAction: humiorepoFromAction},
+	humio.ActionTypeOpsGenie:         {name: "opsgenie", requiredProperty: "genie_key", secret: "genie_key", fromAction: opsgenieFromAction},
+	humio.ActionTypePagerDuty:        {name: "pagerduty", requiredProperty: "routing_key", secret: "routing_key", fromAction: pagerdutyFromAction},
+	humio.ActionTypeSlack:            {name: "slack", requiredProperty: "url", secret: "url", fromAction: slackFromAction},
+	humio.ActionTypeSlackPostMessage: {name: "slackpostmessage", requiredProperty: "api_token", secret: "api_token", fromAction: slackpostmessageFromAction},
+	ActionTypeUploadFile:             {name: "upload_file", requiredProperty: "file_name", fromAction: uploadFileFromAction},
+	humio.ActionTypeVictorOps:        {name: "victorops", requiredProperty: "notify_url", fromAction: victoropsFromAction},
+	humio.ActionTypeWebhook:          {name: "webhook", requiredProperty: "url", fromAction: webhookFromAction},
+}
+
+// actionWriteOnlyAttributes are the attributes of humio_action that are never stored in state.
+var actionWriteOnlyAttributes = []string{"secret_wo", "webhook_headers_wo", "secret_wo_version"}
+
+func suppressWriteOnlyDiff(_, _, _ string, _ *schema.ResourceData) bool {
+	return true
+}
+
+func resourceActionCustomizeDiff(_ context.Context, d *schema.ResourceDiff, _ interface{}) error {
+	block := actionBlocks[d.Get("type").(string)]
+	if block.secret == "" || !d.NewValueKnown(block.name) {
+		return nil
+	}
+
+	config := d.GetRawConfig()
+	if !config.IsNull() && !config.GetAttr("secret_wo").IsNull() {
+		return nil
+	}
+	for _, properties := range d.Get(block.name).(*schema.Set).List() {
+		if properties.(tfMap)[block.secret] != "" {
+			return nil
+		}
+	}
+	return fmt.Errorf("%s.%s or secret_wo must be set for actions of type %s", block.name, block.secret, d.Get("type"))
+}
+
+// writeOnlySecretsFromConfig sets the secrets given through the write-only attributes secretKey and headersKey on the
+// action. As their diffs are suppressed, they are only available from the raw configuration.
+func writeOnlySecretsFromConfig(config cty.Value, a *Action, secretKey, headersKey string) {
+	if config.IsNull() || !config.IsKnown() {
+		return
+	}
+
+	if config.Type().HasAttribute(secretKey) {
+		if v := config.GetAttr(secretKey); !v.IsNull() && v.IsKnown() {
+			setActionSecret(a, v.AsString())
+		}
+	}
+
+	if a.Type != humio.ActionTypeWebhook || !config.Type().HasAttribute(headersKey) {
+		return
+	}
+	if v := config.GetAttr(headersKey); !v.IsNull() && v.IsKnown() {
+		for header, value := range v.AsValueMap() {
+			if value.IsNull() || !value.IsKnown() {
+				continue
+			}
+			a.WebhookAction.Headers = append(a.WebhookAction.Headers, humio.HttpHeaderEntryInput{
+				Header: header,
+				Value:  value.AsString(),
+			})
+		}
+	}
+}
+
+// withoutWriteOnlySecrets returns a copy of the action where the secrets that may have been given through write-only
+// attributes are replaced by the values in the given properties from state, so they are not written to state when
+// reading the action.
+func withoutWriteOnlySecrets(a *Action, properties tfMap) *Action {
+	redacted := *a
+
+	if block := actionBlocks[a.Type]; block.secret != "" {
+		value, _ := properties[block.secret].(string)
+		setActionSecret(&redacted, value)
+	}
+
+	if a.Type == humio.ActionTypeWebhook {
+		headers, _ := properties["headers"].(map[string]interface{})
+		redacted.WebhookAction.Headers = nil
+		for _, header := range a.WebhookAction.Headers {
+			if _, ok := headers[header.Header]; ok {
+				redacted.WebhookAction.Headers = append(redacted.WebhookAction.Headers, header)
+			}
+		}
+	}
+
+	return &redacted
+}
+
+// setActionSecret sets the secret of the action that write-only attributes replace.
+func setActionSecret(a *Action, secret string) {
+	switch a.Type {
+	case humio.ActionTypeHumioRepo:
+		a.HumioRepoAction.IngestToken = secret
+	case humio.ActionTypeOpsGenie:
+		a.OpsGenieAction.GenieKey = secret
+	case humio.ActionTypePagerDuty:
+		a.PagerDutyAction.RoutingKey = secret
+	case humio.ActionTypeSlack:
+		a.SlackAction.Url = secret
+	case humio.ActionTypeSlackPostMessage:
+		a.SlackPostMessageAction.ApiToken = secret
+	}
 }
 
 // getActionPropertiesFromResourceData returns the first non-empty set of action properties related to a given action.
@@ -522,38 +787,43 @@ func getActionPropertiesFromResourceData(d *schema.ResourceData, actionName, req
 		}
 	}
 
-	return []tfMap{}
+	// The required property is empty when its value is given through a write-only attribute instead.
+	return []tfMap{newPropertiesList[0].(tfMap)}
 }
 
-func emailFromAction(a *humio.Action) []tfMap {
+func emailFromAction(a *Action) []tfMap {
 	s := tfMap{}
 	s["recipients"] = a.EmailAction.Recipients
 	s["body_template"] = a.EmailAction.BodyTemplate
 	s["subject_template"] = a.EmailAction.SubjectTemplate
+	s["attach_csv"] = a.EmailAttachCSV
+	s["use_proxy"] = a.EmailAction.UseProxy
 	return []tfMap{s}
 }
 
-func humiorepoFromAction(a *humio.Action) []tfMap {
+func humiorepoFromAction(a *Action) []tfMap {
 	s := tfMap{}
 	s["ingest_token"] = a.HumioRepoAction.IngestToken
 	return []tfMap{s}
 }
 
-func opsgenieFromAction(a *humio.Action) []tfMap {
+func opsgenieFromAction(a *Action) []tfMap {
 	s := tfMap{}
 	s["api_url"] = a.OpsGenieAction.ApiUrl
 	s["genie_key"] = a.OpsGenieAction.GenieKey
+	s["use_proxy"] = a.OpsGenieAction.UseProxy
 	return []tfMap{s}
 }
 
-func pagerdutyFromAction(a *humio.Action) []tfMap {
+func pagerdutyFromAction(a *Action) []tfMap {
 	s := tfMap{}
 	s["routing_key"] = a.PagerDutyAction.RoutingKey
 	s["severity"] = a.PagerDutyAction.Severity
+	s["use_proxy"] = a.PagerDutyAction.UseProxy
 	return []tfMap{s}
 }
 
-func slackFromAction(a *humio.Action) []tfMap {
+func slackFromAction(a *Action) []tfMap {
 	s := tfMap{}
 	fields := make(map[string]string)
 	for _, field := range a.SlackAction.Fields {
@@ -561,10 +831,11 @@ func slackFromAction(a *humio.Action) []tfMap {
 	}
 	s["fields"] = fields
 	s["url"] = a.SlackAction.Url
+	s["use_proxy"] = a.SlackAction.UseProxy
 	return []tfMap{s}
 }
 
-func slackpostmessageFromAction(a *humio.Action) []tfMap {
+func slackpostmessageFromAction(a *Action) []tfMap {
 	s := tfMap{}
 	fields := make(map[string]string)
 	for _, field := range a.SlackPostMessageAction.Fields {
@@ -577,14 +848,22 @@ func slackpostmessageFromAction(a *humio.Action) []tfMap {
 	return []tfMap{s}
 }
 
-func victoropsFromAction(a *humio.Action) []tfMap {
+func uploadFileFromAction(a *Action) []tfMap {
+	s := tfMap{}
+	s["file_name"] = a.UploadFileAction.FileName
+	s["update_mode"] = a.UploadFileAction.UpdateMode
+	return []tfMap{s}
+}
+
+func victoropsFromAction(a *Action) []tfMap {
 	s := tfMap{}
 	s["message_type"] = a.VictorOpsAction.MessageType
 	s["notify_url"] = a.VictorOpsAction.NotifyUrl
+	s["use_proxy"] = a.VictorOpsAction.UseProxy
 	return []tfMap{s}
 }
 
-func webhookFromAction(a *humio.Action) []tfMap {
+func webhookFromAction(a *Action) []tfMap {
 	s := tfMap{}
 	headers := make(map[string]string)
 	for _, pair := range a.WebhookAction.Headers {
@@ -594,5 +873,8 @@ func webhookFromAction(a *humio.Action) []tfMap {
 	s["headers"] = headers
 	s["method"] = a.WebhookAction.Method
 	s["url"] = a.WebhookAction.Url
+	s["ignore_ssl"] = a.WebhookAction.IgnoreSSL
+	s["use_proxy"] = a.WebhookAction.UseProxy
+	s["ca_certificate"] = a.WebhookCACertificate
 	return []tfMap{s}
 }
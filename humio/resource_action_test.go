@@ -121,6 +121,13 @@ func TestAccActionInvalidVictorOpsSettings(t *testing.T) {
 	}, nil)
 }
 
+func TestAccActionInvalidUploadFileSettings(t *testing.T) {
+	config := actionInvalidUploadFileSettings
+	accTestCase(t, []resource.TestStep{
+		{Config: config, ExpectError: regexp.MustCompile(`expected update_mode to be one of \["Overwrite" "Append"\]`)},
+	}, nil)
+}
+
 func TestAccActionInvalidWebHookSettings(t *testing.T) {
 	config := actionInvalidWebHookSettings
 	accTestCase(t, []resource.TestStep{
@@ -195,6 +202,8 @@ func TestAccActionEmailBasicToFull(t *testing.T) {
 				resource.TestCheckResourceAttr("humio_action.test", "email.0.recipients.0", "ops@example.org"),
 				resource.TestCheckResourceAttr("humio_action.test", "email.0.body_template", "this is the body"),
 				resource.TestCheckResourceAttr("humio_action.test", "email.0.subject_template", "this is the subject"),
+				resource.TestCheckResourceAttr("humio_action.test", "email.0.attach_csv", "true"),
+				resource.TestCheckResourceAttr("humio_action.test", "email.0.use_proxy", "true"),
 
 				resource.TestCheckResourceAttr("humio_action.test", "humiorepo.#", "0"),
 				resource.TestCheckResourceAttr("humio_action.test", "opsgenie.#", "0"),
@@ -220,6 +229,8 @@ func TestAccActionEmailBasicToFull(t *testing.T) {
 				resource.TestCheckResourceAttr("humio_action.test", "email.0.recipients.1", "ops@example.org"),
 				resource.TestCheckResourceAttr("humio_action.test", "email.0.body_template", "this is the body"),
 				resource.TestCheckResourceAttr("humio_action.test", "email.0.subject_template", "this is the subject"),
+				resource.TestCheckResourceAttr("humio_action.test", "email.0.attach_csv", "true"),
+				resource.TestCheckResourceAttr("humio_action.test", "email.0.use_proxy", "true"),
 
 				resource.TestCheckResourceAttr("humio_action.test", "humiorepo.#", "0"),
 				resource.TestCheckResourceAttr("humio_action.test", "opsgenie.#", "0"),
@@ -248,6 +259,8 @@ func TestAccActionEmailFull(t *testing.T) {
 				resource.TestCheckResourceAttr("humio_action.test", "email.0.recipients.1", "ops@example.org"),
 				resource.TestCheckResourceAttr("humio_action.test", "email.0.body_template", "this is the body"),
 				resource.TestCheckResourceAttr("humio_action.test", "email.0.subject_template", "this is the subject"),
+				resource.TestCheckResourceAttr("humio_action.test", "email.0.attach_csv", "true"),
+				resource.TestCheckResourceAttr("humio_action.test", "email.0.use_proxy", "true"),
 
 				resource.TestCheckResourceAttr("humio_action.test", "humiorepo.#", "0"),
 				resource.TestCheckResourceAttr("humio_action.test", "opsgenie.#", "0"),
@@ -402,6 +415,67 @@ func TestAccActionOpsGenieFull(t *testing.T) {
 	}, testAccCheckActionDestroy)
 }
 
+func TestAccActionOpsGenieWriteOnly(t *testing.T) {
+	accTestCase(t, []resource.TestStep{
+		{
+			Config: fmt.Sprintf(actionOpsGenieWriteOnly, "secretgeniekey", 1),
+			Check: resource.ComposeTestCheckFunc(
+				resource.TestCheckResourceAttrSet("humio_action.test", "action_id"),
+				resource.TestCheckResourceAttr("humio_action.test", "opsgenie.#", "1"),
+				resource.TestCheckResourceAttr("humio_action.test", "opsgenie.0.genie_key", ""),
+				resource.TestCheckNoResourceAttr("humio_action.test", "secret_wo"),
+				resource.TestCheckResourceAttr("humio_action.test", "secret_wo_version", "1"),
+			),
+		},
+		{
+			Config:   fmt.Sprintf(actionOpsGenieWriteOnly, "othergeniekey", 1),
+			PlanOnly: true,
+		},
+		{
+			Config: fmt.Sprintf(actionOpsGenieWriteOnly, "othergeniekey", 2),
+			Check: resource.ComposeTestCheckFunc(
+				resource.TestCheckResourceAttr("humio_action.test", "opsgenie.0.genie_key", ""),
+				resource.TestCheckNoResourceAttr("humio_action.test", "secret_wo"),
+				resource.TestCheckResourceAttr("humio_action.test", "secret_wo_version", "2"),
+				testAccCheckActionOpsGenieKey("humio_action.test", "othergeniekey"),
+			),
+		},
+	}, testAccCheckActionDestroy)
+}
+
+func TestAccActionOpsGenieMissingSecret(t *testing.T) {
+	accTestCase(t, []resource.TestStep{
+		{Config: actionOpsGenieMissingSecret, ExpectError: regexp.MustCompile(`opsgenie.genie_key or secret_wo must be set`)},
+	}, nil)
+}
+
+func testAccCheckActionOpsGenieKey(name, want string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[name]
+		if !ok {
+			return fmt.Errorf("resource not found: %s", name)
+		}
+		conn := testAccProviders["humio"].Meta().(*providerMeta).client
+		action, err := newActions(conn).Get(rs.Primary.Attributes["repository"], rs.Primary.Attributes["name"])
+		if err != nil {
+			return err
+		}
+		if action.OpsGenieAction.GenieKey != want {
+			return fmt.Errorf("expected genie key %q, got %q", want, action.OpsGenieAction.GenieKey)
+		}
+		return nil
+	}
+}
+
+func TestAccActionVerifyOnApply(t *testing.T) {
+	accTestCase(t, []resource.TestStep{
+		{
+			Config:      actionWebHookVerifyOnApply,
+			ExpectError: regexp.MustCompile(`sending a test notification through it failed`),
+		},
+	}, testAccCheckActionDestroy)
+}
+
 func TestAccActionPagerDutyFull(t *testing.T) {
 	accTestCase(t, []resource.TestStep{
 		{
@@ -730,6 +804,32 @@ func TestAccActionVictorOpsFull(t *testing.T) {
 	}, testAccCheckActionDestroy)
 }
 
+func TestAccActionUploadFileFull(t *testing.T) {
+	accTestCase(t, []resource.TestStep{
+		{
+			Config: actionUploadFileFull,
+			Check: resource.ComposeTestCheckFunc(
+				resource.TestCheckResourceAttrSet("humio_action.test", "action_id"),
+				resource.TestCheckResourceAttr("humio_action.test", "repository", "sandbox"),
+				resource.TestCheckResourceAttr("humio_action.test", "type", "UploadFileAction"),
+				resource.TestCheckResourceAttr("humio_action.test", "name", "action-uploadfile-test"),
+				resource.TestCheckResourceAttr("humio_action.test", "upload_file.#", "1"),
+				resource.TestCheckResourceAttr("humio_action.test", "upload_file.0.file_name", "blocklist.csv"),
+				resource.TestCheckResourceAttr("humio_action.test", "upload_file.0.update_mode", "Append"),
+
+				resource.TestCheckResourceAttr("humio_action.test", "email.#", "0"),
+				resource.TestCheckResourceAttr("humio_action.test", "humiorepo.#", "0"),
+				resource.TestCheckResourceAttr("humio_action.test", "opsgenie.#", "0"),
+				resource.TestCheckResourceAttr("humio_action.test", "pagerduty.#", "0"),
+				resource.TestCheckResourceAttr("humio_action.test", "slack.#", "0"),
+				resource.TestCheckResourceAttr("humio_action.test", "slackpostmessage.#", "0"),
+				resource.TestCheckResourceAttr("humio_action.test", "victorops.#", "0"),
+				resource.TestCheckResourceAttr("humio_action.test", "webhook.#", "0"),
+			),
+		},
+	}, testAccCheckActionDestroy)
+}
+
 func TestAccActionWebHookBasic(t *testing.T) {
 	accTestCase(t, []resource.TestStep{
 		{
@@ -745,6 +845,8 @@ func TestAccActionWebHookBasic(t *testing.T) {
 				resource.TestCheckResourceAttr("humio_action.test", "webhook.0.headers.Content-Type", "application/json"),
 				resource.TestCheckResourceAttr("humio_action.test", "webhook.0.method", "POST"),
 				resource.TestCheckResourceAttr("humio_action.test", "webhook.0.url", "https://127.0.0.1/iasjdojaoijdioajd"),
+				resource.TestCheckResourceAttr("humio_action.test", "webhook.0.ignore_ssl", "true"),
+				resource.TestCheckResourceAttr("humio_action.test", "webhook.0.use_proxy", "true"),
 
 				resource.TestCheckResourceAttr("humio_action.test", "email.#", "0"),
 				resource.TestCheckResourceAttr("humio_action.test", "humiorepo.#", "0"),
@@ -773,6 +875,8 @@ func TestAccActionWebHookBasicToFull(t *testing.T) {
 				resource.TestCheckResourceAttr("humio_action.test", "webhook.0.headers.Content-Type", "application/json"),
 				resource.TestCheckResourceAttr("humio_action.test", "webhook.0.method", "POST"),
 				resource.TestCheckResourceAttr("humio_action.test", "webhook.0.url", "https://127.0.0.1/iasjdojaoijdioajd"),
+				resource.TestCheckResourceAttr("humio_action.test", "webhook.0.ignore_ssl", "true"),
+				resource.TestCheckResourceAttr("humio_action.test", "webhook.0.use_proxy", "true"),
 
 				resource.TestCheckResourceAttr("humio_action.test", "email.#", "0"),
 				resource.TestCheckResourceAttr("humio_action.test", "humiorepo.#", "0"),
@@ -797,6 +901,8 @@ func TestAccActionWebHookBasicToFull(t *testing.T) {
 				resource.TestCheckResourceAttr("humio_action.test", "webhook.0.headers.custom2", "this2"),
 				resource.TestCheckResourceAttr("humio_action.test", "webhook.0.method", "GET"),
 				resource.TestCheckResourceAttr("humio_action.test", "webhook.0.url", "https://127.0.0.1/iasjdojaoijdioajd"),
+				resource.TestCheckResourceAttr("humio_action.test", "webhook.0.ignore_ssl", "true"),
+				resource.TestCheckResourceAttr("humio_action.test", "webhook.0.use_proxy", "true"),
 
 				resource.TestCheckResourceAttr("humio_action.test", "email.#", "0"),
 				resource.TestCheckResourceAttr("humio_action.test", "humiorepo.#", "0"),
@@ -823,6 +929,8 @@ func TestAccActionWebHookBasicToFull(t *testing.T) {
 				resource.TestCheckResourceAttr("humio_action.test", "webhook.0.headers.custom2", "this2"),
 				resource.TestCheckResourceAttr("humio_action.test", "webhook.0.method", "GET"),
 				resource.TestCheckResourceAttr("humio_action.test", "webhook.0.url", "https://127.0.0.1/iasjdojaoijdioajd"),
+				resource.TestCheckResourceAttr("humio_action.test", "webhook.0.ignore_ssl", "true"),
+				resource.TestCheckResourceAttr("humio_action.test", "webhook.0.use_proxy", "true"),
 
 				resource.TestCheckResourceAttr("humio_action.test", "email.#", "0"),
 				resource.TestCheckResourceAttr("humio_action.test", "humiorepo.#", "0"),
@@ -852,6 +960,8 @@ func TestAccActionWebHookFull(t *testing.T) {
 				resource.TestCheckResourceAttr("humio_action.test", "webhook.0.headers.custom2", "this2"),
 				resource.TestCheckResourceAttr("humio_action.test", "webhook.0.method", "GET"),
 				resource.TestCheckResourceAttr("humio_action.test", "webhook.0.url", "https://127.0.0.1/iasjdojaoijdioajd"),
+				resource.TestCheckResourceAttr("humio_action.test", "webhook.0.ignore_ssl", "true"),
+				resource.TestCheckResourceAttr("humio_action.test", "webhook.0.use_proxy", "true"),
 
 				resource.TestCheckResourceAttr("humio_action.test", "email.#", "0"),
 				resource.TestCheckResourceAttr("humio_action.test", "humiorepo.#", "0"),
@@ -865,8 +975,26 @@ func TestAccActionWebHookFull(t *testing.T) {
 	}, testAccCheckActionDestroy)
 }
 
+func TestAccActionWebHookCACertificate(t *testing.T) {
+	ca := newTestCertificate(t, "CA", nil)
+	accTestCase(t, []resource.TestStep{
+		{
+			Config: fmt.Sprintf(actionWebHookCACertificate, ca.certPEM),
+			Check: resource.ComposeTestCheckFunc(
+				resource.TestCheckResourceAttr("humio_action.test", "webhook.0.ca_certificate", ca.certPEM),
+			),
+		},
+		{
+			Config: actionWebHookBasic,
+			Check: resource.ComposeTestCheckFunc(
+				resource.TestCheckResourceAttr("humio_action.test", "webhook.0.ca_certificate", ""),
+			),
+		},
+	}, testAccCheckActionDestroy)
+}
+
 func testAccCheckActionDestroy(s *terraform.State) error {
-	conn := testAccProviders["humio"].Meta().(*humio.Client)
+	conn := testAccProviders["humio"].Meta().(*providerMeta).client
 
 	for _, rs := range s.RootModule().Resources {
 		if rs.Type != "humio_action" {
@@ -985,6 +1113,18 @@ resource "humio_action" "test" {
 }
 `
 
+const actionInvalidUploadFileSettings = `
+resource "humio_action" "test" {
+    repository = "sandbox"
+    type       = "UploadFileAction"
+    name       = "action-uploadfile-test"
+    upload_file {
+        file_name   = "blocklist.csv"
+        update_mode = "invalid"
+    }
+}
+`
+
 const actionInvalidVictorOpsSettings = `
 resource "humio_action" "test" {
     repository = "sandbox"
@@ -1031,6 +1171,8 @@ resource "humio_action" "test" {
         body_template    = "this is the body"
         recipients       = ["test@example.org", "ops@example.org"]
         subject_template = "this is the subject"
+        attach_csv       = true
+        use_proxy        = true
     }
 }
 `
@@ -1069,6 +1211,30 @@ resource "humio_action" "test" {
 }
 `
 
+const actionOpsGenieWriteOnly = `
+resource "humio_action" "test" {
+    repository        = "sandbox"
+    type              = "OpsGenieAction"
+    name              = "action-opsgenie-test"
+    secret_wo         = "%s"
+    secret_wo_version = %d
+    opsgenie {
+        api_url = "https://127.0.0.1/iasjdojaoijdioajd"
+    }
+}
+`
+
+const actionOpsGenieMissingSecret = `
+resource "humio_action" "test" {
+    repository = "sandbox"
+    type       = "OpsGenieAction"
+    name       = "action-opsgenie-test"
+    opsgenie {
+        api_url = "https://127.0.0.1/iasjdojaoijdioajd"
+    }
+}
+`
+
 const actionPagerDutyFull = `
 resource "humio_action" "test" {
     repository = "sandbox"
@@ -1158,6 +1324,18 @@ resource "humio_action" "test" {
 }
 `
 
+const actionUploadFileFull = `
+resource "humio_action" "test" {
+    repository = "sandbox"
+    type       = "UploadFileAction"
+    name       = "action-uploadfile-test"
+    upload_file {
+        file_name   = "blocklist.csv"
+        update_mode = "Append"
+    }
+}
+`
+
 const actionWebHookBasic = `
 resource "humio_action" "test" {
     repository = "sandbox"
@@ -1183,8 +1361,35 @@ resource "humio_action" "test" {
             "custom/header1" = "this1"
             custom2          = "this2"
         }
-        method = "GET"
-        url    = "https://127.0.0.1/iasjdojaoijdioajd"
+        method     = "GET"
+        url        = "https://127.0.0.1/iasjdojaoijdioajd"
+        ignore_ssl = true
+        use_proxy  = true
+    }
+}
+`
+
+const actionWebHookCACertificate = `
+resource "humio_action" "test" {
+    repository = "sandbox"
+    type       = "WebhookAction"
+    name       = "action-webhook-test"
+    webhook {
+        url            = "https://127.0.0.1/iasjdojaoijdioajd"
+        ca_certificate = <<EOT
+%sEOT
+    }
+}
+`
+
+const actionWebHookVerifyOnApply = `
+resource "humio_action" "test" {
+    repository      = "sandbox"
+    type            = "WebhookAction"
+    name            = "action-webhook-test"
+    verify_on_apply = true
+    webhook {
+        url = "https://127.0.0.1/iasjdojaoijdioajd"
     }
 }
 `
@@ -1197,9 +1402,15 @@ var wantEmailAction = humio.Action{
 		Recipients:      []string{"test@example.org", "ops@example.org"},
 		BodyTemplate:    "this is the subject",
 		SubjectTemplate: "this is the body",
+		UseProxy:        true,
 	},
 }
 
+var wantEmailActionAttachCSV = Action{
+	Action:         wantEmailAction,
+	EmailAttachCSV: true,
+}
+
 var wantHumioRepoAction = humio.Action{
 	ID:     "",
 	Type: "HumioRepoAction",
@@ -1216,6 +1427,7 @@ var wantOpsGenieAction = humio.Action{
 	OpsGenieAction: humio.OpsGenieAction{
 		ApiUrl:   "https://example.org",
 		GenieKey: "12345678901234567890123456789012",
+		UseProxy: true,
 	},
 }
 
@@ -1226,6 +1438,7 @@ var wantPagerDutyAction = humio.Action{
 	PagerDutyAction: humio.PagerDutyAction{
 		RoutingKey: "12345678901234567890123456789012",
 		Severity:   "critical",
+		UseProxy:   true,
 	},
 }
 
@@ -1239,6 +1452,7 @@ var wantSlackAction = humio.Action{
 			{"Link", "{url}" },
 			{"Query", "{query_string}" },
 		},
+		UseProxy: true,
 	},
 }
 
@@ -1264,6 +1478,7 @@ var wantVictorOpsAction = humio.Action{
 	VictorOpsAction: humio.VictorOpsAction{
 		MessageType: "12345678901234567890123456789012",
 		NotifyUrl:   "https://example.org",
+		UseProxy:    true,
 	},
 }
 
@@ -1276,111 +1491,236 @@ var wantWebhookAction = humio.Action{
 		Headers: []humio.HttpHeaderEntryInput{
 			{"Token", "abcdefghij123456678"},
 		},
-		Method: "POST",
-		Url:    "https://example.org",
+		Method:    "POST",
+		Url:       "https://example.org",
+		IgnoreSSL: true,
+		UseProxy:  true,
 	},
 }
 
 func TestEncodeDecodeEmailActionResource(t *testing.T) {
 	res := resourceAction()
 	data := res.TestResourceData()
-	resourceDataFromAction(&wantEmailAction, data)
+	resourceDataFromAction(&wantEmailActionAttachCSV, data)
 	got, err := actionFromResourceData(data)
 	if err != nil {
 		t.Fatal(err)
 	}
-	if !cmp.Equal(wantEmailAction, got) {
-		t.Error(cmp.Diff(wantEmailAction, got))
+	if !cmp.Equal(wantEmailActionAttachCSV, got) {
+		t.Error(cmp.Diff(wantEmailActionAttachCSV, got))
 	}
 }
 
 func TestEncodeDecodeHumioRepoActionResource(t *testing.T) {
 	res := resourceAction()
 	data := res.TestResourceData()
-	resourceDataFromAction(&wantHumioRepoAction, data)
+	resourceDataFromAction(&Action{Action: wantHumioRepoAction}, data)
 	got, err := actionFromResourceData(data)
 	if err != nil {
 		t.Fatal(err)
 	}
-	if !cmp.Equal(wantHumioRepoAction, got) {
-		t.Error(cmp.Diff(wantHumioRepoAction, got))
+	if !cmp.Equal(wantHumioRepoAction, got.Action) {
+		t.Error(cmp.Diff(wantHumioRepoAction, got.Action))
 	}
 }
 
 func TestEncodeDecodeOpsGenieActionResource(t *testing.T) {
 	res := resourceAction()
 	data := res.TestResourceData()
-	resourceDataFromAction(&wantOpsGenieAction, data)
+	resourceDataFromAction(&Action{Action: wantOpsGenieAction}, data)
 	got, err := actionFromResourceData(data)
 	if err != nil {
 		t.Fatal(err)
 	}
-	if !cmp.Equal(wantOpsGenieAction, got) {
-		t.Error(cmp.Diff(wantOpsGenieAction, got))
+	if !cmp.Equal(wantOpsGenieAction, got.Action) {
+		t.Error(cmp.Diff(wantOpsGenieAction, got.Action))
 	}
 }
 
 func TestEncodeDecodePagerDutyActionResource(t *testing.T) {
 	res := resourceAction()
 	data := res.TestResourceData()
-	resourceDataFromAction(&wantPagerDutyAction, data)
+	resourceDataFromAction(&Action{Action: wantPagerDutyAction}, data)
 	got, err := actionFromResourceData(data)
 	if err != nil {
 		t.Fatal(err)
 	}
-	if !cmp.Equal(wantPagerDutyAction, got) {
-		t.Error(cmp.Diff(wantPagerDutyAction, got))
+	if !cmp.Equal(wantPagerDutyAction, got.Action) {
+		t.Error(cmp.Diff(wantPagerDutyAction, got.Action))
 	}
 }
 
 func TestEncodeDecodeSlackActionResource(t *testing.T) {
 	res := resourceAction()
 	data := res.TestResourceData()
-	resourceDataFromAction(&wantSlackAction, data)
+	resourceDataFromAction(&Action{Action: wantSlackAction}, data)
 	got, err := actionFromResourceData(data)
 	if err != nil {
 		t.Fatal(err)
 	}
-	if !cmp.Equal(wantSlackAction, got) {
-		t.Error(cmp.Diff(wantSlackAction, got))
+	if !cmp.Equal(wantSlackAction, got.Action) {
+		t.Error(cmp.Diff(wantSlackAction, got.Action))
 	}
 }
 
 func TestEncodeDecodeSlackPostMessageActionResource(t *testing.T) {
 	res := resourceAction()
 	data := res.TestResourceData()
-	resourceDataFromAction(&wantSlackPostMessageAction, data)
+	resourceDataFromAction(&Action{Action: wantSlackPostMessageAction}, data)
 	got, err := actionFromResourceData(data)
 	if err != nil {
 		t.Fatal(err)
 	}
-	if !cmp.Equal(wantSlackPostMessageAction, got) {
-		t.Error(cmp.Diff(wantSlackPostMessageAction, got))
+	if !cmp.Equal(wantSlackPostMessageAction, got.Action) {
+		t.Error(cmp.Diff(wantSlackPostMessageAction, got.Action))
 	}
 }
 
 func TestEncodeDecodeVictorOpsActionResource(t *testing.T) {
 	res := resourceAction()
 	data := res.TestResourceData()
-	resourceDataFromAction(&wantVictorOpsAction, data)
+	resourceDataFromAction(&Action{Action: wantVictorOpsAction}, data)
+	got, err := actionFromResourceData(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !cmp.Equal(wantVictorOpsAction, got.Action) {
+		t.Error(cmp.Diff(wantVictorOpsAction, got.Action))
+	}
+}
+
+var wantUploadFileAction = Action{
+	Action: humio.Action{
+		ID:   "",
+		Type: "UploadFileAction",
+		Name: "test-action",
+	},
+	UploadFileAction: UploadFileAction{
+		FileName:   "blocklist.csv",
+		UpdateMode: "Append",
+	},
+}
+
+func TestEncodeDecodeUploadFileActionResource(t *testing.T) {
+	res := resourceAction()
+	data := res.TestResourceData()
+	resourceDataFromAction(&wantUploadFileAction, data)
 	got, err := actionFromResourceData(data)
 	if err != nil {
 		t.Fatal(err)
 	}
-	if !cmp.Equal(wantVictorOpsAction, got) {
-		t.Error(cmp.Diff(wantVictorOpsAction, got))
+	if !cmp.Equal(wantUploadFileAction, got) {
+		t.Error(cmp.Diff(wantUploadFileAction, got))
 	}
 }
 
 func TestEncodeDecodeWebhookActionResource(t *testing.T) {
 	res := resourceAction()
 	data := res.TestResourceData()
-	resourceDataFromAction(&wantWebhookAction, data)
+	want := Action{
+		Action:               wantWebhookAction,
+		WebhookCACertificate: newTestCertificate(t, "CA", nil).certPEM,
+	}
+	resourceDataFromAction(&want, data)
 	got, err := actionFromResourceData(data)
 	if err != nil {
 		t.Fatal(err)
 	}
-	if !cmp.Equal(wantWebhookAction, got) {
-		t.Error(cmp.Diff(wantWebhookAction, got))
+	if !cmp.Equal(want, got) {
+		t.Error(cmp.Diff(want, got))
+	}
+}
+
+func TestWithoutWriteOnlySecrets(t *testing.T) {
+	res := resourceAction()
+	data := res.TestResourceData()
+	if err := data.Set("secret_wo_version", 1); err != nil {
+		t.Fatal(err)
+	}
+	if err := data.Set("webhook", []tfMap{{"headers": map[string]interface{}{"Token": "in-state"}}}); err != nil {
+		t.Fatal(err)
+	}
+
+	server := wantWebhookAction
+	server.WebhookAction.Headers = []humio.HttpHeaderEntryInput{
+		{Header: "Token", Value: "abcdefghij123456678"},
+		{Header: "Authorization", Value: "Bearer write-only"},
+	}
+	resourceDataFromAction(&Action{Action: server}, data)
+	got, err := actionFromResourceData(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !cmp.Equal(wantWebhookAction, got.Action) {
+		t.Error(cmp.Diff(wantWebhookAction, got.Action))
+	}
+
+	data = res.TestResourceData()
+	if err := data.Set("secret_wo_version", 1); err != nil {
+		t.Fatal(err)
+	}
+	resourceDataFromAction(&Action{Action: wantOpsGenieAction}, data)
+	got, err = actionFromResourceData(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.OpsGenieAction.GenieKey != "" {
+		t.Errorf("expected the genie key not to be stored in state, got %q", got.OpsGenieAction.GenieKey)
+	}
+}
+
+// wantActionsByType returns an action of every supported action type, by action type.
+func wantActionsByType() map[string]Action {
+	return map[string]Action{
+		humio.ActionTypeEmail:            wantEmailActionAttachCSV,
+		humio.ActionTypeHumioRepo:        {Action: wantHumioRepoAction},
+		humio.ActionTypeOpsGenie:         {Action: wantOpsGenieAction},
+		humio.ActionTypePagerDuty:        {Action: wantPagerDutyAction},
+		humio.ActionTypeSlack:            {Action: wantSlackAction},
+		humio.ActionTypeSlackPostMessage: {Action: wantSlackPostMessageAction},
+		humio.ActionTypeVictorOps:        {Action: wantVictorOpsAction},
+		humio.ActionTypeWebhook:          {Action: wantWebhookAction},
+		ActionTypeUploadFile:             wantUploadFileAction,
+	}
+}
+
+// TestEncodeDecodeActionResources round-trips every action type through actionFromResourceData and
+// resourceDataFromAction, and fails if an action type supported by the resource is missing from the table.
+func TestEncodeDecodeActionResources(t *testing.T) {
+	tests := wantActionsByType()
+
+	for _, actionType := range actionTypes {
+		if _, ok := tests[actionType]; !ok {
+			t.Errorf("no round-trip test for action type %s", actionType)
+		}
+	}
+
+	for actionType, want := range tests {
+		want := want
+		t.Run(actionType, func(t *testing.T) {
+			res := resourceAction()
+			data := res.TestResourceData()
+			data.SetId("sandbox+test-action")
+			if diags := resourceDataFromAction(&want, data); diags.HasError() {
+				t.Fatal(diags)
+			}
+
+			got, err := actionFromResourceData(data)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !cmp.Equal(want, got) {
+				t.Error(cmp.Diff(want, got))
+			}
+
+			roundTripped := res.TestResourceData()
+			roundTripped.SetId("sandbox+test-action")
+			if diags := resourceDataFromAction(&got, roundTripped); diags.HasError() {
+				t.Fatal(diags)
+			}
+			if !cmp.Equal(data.State().Attributes, roundTripped.State().Attributes) {
+				t.Error(cmp.Diff(data.State().Attributes, roundTripped.State().Attributes))
+			}
+		})
 	}
 }
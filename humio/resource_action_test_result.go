@@ -0,0 +1,97 @@
+// Copyright © 2020 Humio Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package humio
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// resourceActionTestResult sends a test notification through an existing action when it is created, and keeps whether
+// it was delivered in state, so it can be checked with a check block or a postcondition. Reading it does not send
+// anything; a new notification is only sent when the resource is replaced, i.e. when the action or one of the triggers
+// changes.
+func resourceActionTestResult() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceActionTestResultCreate,
+		ReadContext:   resourceActionTestResultRead,
+		DeleteContext: resourceActionTestResultDelete,
+
+		Schema: map[string]*schema.Schema{
+			"repository": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"action_id": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"triggers": {
+				Type:        schema.TypeMap,
+				Optional:    true,
+				ForceNew:    true,
+				Description: "Arbitrary values that send a new test notification when they change.",
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+			},
+			"success": {
+				Type:     schema.TypeBool,
+				Computed: true,
+			},
+			"message": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+	}
+}
+
+func resourceActionTestResultCreate(ctx context.Context, d *schema.ResourceData, client interface{}) diag.Diagnostics {
+	repository := d.Get("repository").(string)
+	actionID := d.Get("action_id").(string)
+
+	result, err := client.(*providerMeta).actions().Test(repository, actionID)
+	if err != nil {
+		return diag.Errorf("could not send test notification through action %s: %s", actionID, err)
+	}
+	d.SetId(fmt.Sprintf("%s+%s", repository, actionID))
+
+	err = d.Set("success", result.Success)
+	if err != nil {
+		return diag.Errorf("error setting success for resource %s: %s", d.Id(), err)
+	}
+	err = d.Set("message", result.Message)
+	if err != nil {
+		return diag.Errorf("error setting message for resource %s: %s", d.Id(), err)
+	}
+	return resourceActionTestResultRead(ctx, d, client)
+}
+
+// resourceActionTestResultRead keeps the result of the test notification sent on create, as sending another one on
+// every refresh would notify the receiver of the action on every plan.
+func resourceActionTestResultRead(_ context.Context, _ *schema.ResourceData, _ interface{}) diag.Diagnostics {
+	return nil
+}
+
+func resourceActionTestResultDelete(_ context.Context, d *schema.ResourceData, _ interface{}) diag.Diagnostics {
+	d.SetId("")
+	return nil
+}
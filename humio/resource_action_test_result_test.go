@@ -0,0 +1,109 @@
+// Copyright © 2020 Humio Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package humio
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	humio "github.com/humio/cli/api"
+)
+
+func TestAccActionTestResult(t *testing.T) {
+	accTestCase(t, []resource.TestStep{
+		{
+			// The webhook points at a port nothing listens on, so the test notification cannot be delivered.
+			Config: actionWebHookBasic + fmt.Sprintf(actionTestResultConfig, "1"),
+			Check: resource.ComposeTestCheckFunc(
+				resource.TestCheckResourceAttr("humio_action_test_result.test", "success", "false"),
+				resource.TestCheckResourceAttrSet("humio_action_test_result.test", "message"),
+			),
+		},
+		{
+			Config: actionWebHookBasic + fmt.Sprintf(actionTestResultConfig, "2"),
+			Check: resource.ComposeTestCheckFunc(
+				resource.TestCheckResourceAttr("humio_action_test_result.test", "triggers.run", "2"),
+				resource.TestCheckResourceAttr("humio_action_test_result.test", "success", "false"),
+			),
+		},
+	}, testAccCheckActionDestroy)
+}
+
+const actionTestResultConfig = `
+resource "humio_action_test_result" "test" {
+	repository = humio_action.test.repository
+	action_id  = humio_action.test.action_id
+	triggers = {
+		run = "%s"
+	}
+}
+`
+
+func TestActionTestResultSendsOnlyOnCreate(t *testing.T) {
+	var tests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if !strings.Contains(string(body), "testActionFromId") {
+			t.Errorf("unexpected request: %s", body)
+		}
+		tests.Add(1)
+		_, _ = io.WriteString(w, `{"data":{"testActionFromId":{"success":false,"message":"connection refused"}}}`)
+	}))
+	defer server.Close()
+
+	address, err := url.Parse(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	meta := &providerMeta{client: humio.NewClient(humio.Config{Address: address})}
+
+	r := resourceActionTestResult()
+	d := schema.TestResourceDataRaw(t, r.Schema, map[string]interface{}{
+		"repository": "sandbox",
+		"action_id":  "abc",
+	})
+	if diags := r.CreateContext(context.Background(), d, meta); diags.HasError() {
+		t.Fatalf("create: %v", diags)
+	}
+	for i := 0; i < 3; i++ {
+		if diags := r.ReadContext(context.Background(), d, meta); diags.HasError() {
+			t.Fatalf("read: %v", diags)
+		}
+	}
+
+	if got := tests.Load(); got != 1 {
+		t.Errorf("sent %d test notifications, want 1", got)
+	}
+	if d.Id() != "sandbox+abc" || d.Get("success").(bool) || d.Get("message").(string) != "connection refused" {
+		t.Errorf("got id %q, success %v, message %q", d.Id(), d.Get("success"), d.Get("message"))
+	}
+}
+
+func TestActionTestResultInputsForceNew(t *testing.T) {
+	for k, s := range resourceActionTestResult().Schema {
+		if !s.Computed && !s.ForceNew {
+			t.Errorf("%s does not force a new test notification when it changes", k)
+		}
+	}
+}
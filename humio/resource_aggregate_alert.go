@@ -0,0 +1,278 @@
+// Copyright © 2020 Humio Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package humio
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+func resourceAggregateAlert() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceAggregateAlertCreate,
+		ReadContext:   resourceAggregateAlertRead,
+		UpdateContext: resourceAggregateAlertUpdate,
+		DeleteContext: resourceAggregateAlertDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+		CustomizeDiff: validateQueryDiff("query"),
+
+		Schema: map[string]*schema.Schema{
+			"aggregate_alert_id": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"repository": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"name": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"description": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"enabled": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+			"query": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"search_interval_seconds": {
+				Type:             schema.TypeInt,
+				Required:         true,
+				ValidateDiagFunc: validation.ToDiagFunc(validation.IntAtLeast(60)),
+			},
+			"query_timestamp_type": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				Default:          "EventTimestamp",
+				ValidateDiagFunc: validation.ToDiagFunc(validation.StringInSlice([]string{"EventTimestamp", "IngestTimestamp"}, false)),
+			},
+			"trigger_mode": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				Default:          "CompleteMode",
+				ValidateDiagFunc: validation.ToDiagFunc(validation.StringInSlice([]string{"CompleteMode", "ImmediateMode"}, false)),
+			},
+			"throttle_time_seconds": {
+				Type:             schema.TypeInt,
+				Required:         true,
+				ValidateDiagFunc: validation.ToDiagFunc(validation.IntAtLeast(0)),
+			},
+			"throttle_field": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"actions": {
+				Type:     schema.TypeList,
+				Optional: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+			"labels": {
+				Type:     schema.TypeList,
+				Optional: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+			"run_as_user_id": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+			},
+			"query_ownership_type": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				Computed:         true,
+				ValidateDiagFunc: validateQueryOwnershipType,
+			},
+		},
+	}
+}
+
+func resourceAggregateAlertCreate(ctx context.Context, d *schema.ResourceData, client interface{}) diag.Diagnostics {
+	aggregateAlert, err := aggregateAlertFromResourceData(d)
+	if err != nil {
+		return diag.Errorf("could not obtain aggregate alert from resource data: %s", err)
+	}
+
+	_, err = client.(*providerMeta).aggregateAlerts().Add(
+		d.Get("repository").(string),
+		&aggregateAlert,
+	)
+	if err != nil {
+		return diag.Errorf("could not create aggregate alert: %s", err)
+	}
+	d.SetId(fmt.Sprintf("%s+%s", d.Get("repository"), d.Get("name")))
+
+	return resourceAggregateAlertRead(ctx, d, client)
+}
+
+func resourceAggregateAlertRead(_ context.Context, d *schema.ResourceData, client interface{}) diag.Diagnostics {
+	// If we don't have a repository when importing, we parse it from the ID.
+	if _, ok := d.GetOk("repository"); !ok {
+		parts := parseRepositoryAndID(d.Id())
+		//we check that we have parsed the id into the correct number of segments
+		if parts[0] == "" || parts[1] == "" {
+			return diag.Errorf("error importing humio_aggregate_alert. Please make sure the ID is in the form REPOSITORYNAME+AGGREGATEALERTNAME (i.e. myRepoName+myAggregateAlertName")
+		}
+		err := d.Set("repository", parts[0])
+		if err != nil {
+			return diag.Errorf("error setting repository for resource %s: %s", d.Id(), err)
+		}
+		err = d.Set("name", parts[1])
+		if err != nil {
+			return diag.Errorf("error setting name for resource %s: %s", d.Id(), err)
+		}
+	}
+
+	aggregateAlert, err := client.(*providerMeta).aggregateAlerts().Get(
+		d.Get("repository").(string),
+		d.Get("name").(string),
+	)
+	if isNotFound(err) {
+		log.Printf("[WARN] aggregate alert %s not found, removing from state", d.Id())
+		d.SetId("")
+		return nil
+	}
+	if err != nil {
+		return diag.Errorf("could not get aggregate alert: %s", err)
+	}
+	return resourceDataFromAggregateAlert(aggregateAlert, d)
+}
+
+func resourceDataFromAggregateAlert(a *AggregateAlert, d *schema.ResourceData) diag.Diagnostics {
+	err := d.Set("aggregate_alert_id", a.ID)
+	if err != nil {
+		return diag.Errorf("error setting aggregate_alert_id for resource %s: %s", d.Id(), err)
+	}
+	err = d.Set("name", a.Name)
+	if err != nil {
+		return diag.Errorf("error setting name for resource %s: %s", d.Id(), err)
+	}
+	err = d.Set("description", a.Description)
+	if err != nil {
+		return diag.Errorf("error setting description for resource %s: %s", d.Id(), err)
+	}
+	err = d.Set("enabled", a.Enabled)
+	if err != nil {
+		return diag.Errorf("error setting enabled for resource %s: %s", d.Id(), err)
+	}
+	err = d.Set("query", a.QueryString)
+	if err != nil {
+		return diag.Errorf("error setting query for resource %s: %s", d.Id(), err)
+	}
+	err = d.Set("search_interval_seconds", a.SearchIntervalSeconds)
+	if err != nil {
+		return diag.Errorf("error setting search_interval_seconds for resource %s: %s", d.Id(), err)
+	}
+	err = d.Set("query_timestamp_type", a.QueryTimestampType)
+	if err != nil {
+		return diag.Errorf("error setting query_timestamp_type for resource %s: %s", d.Id(), err)
+	}
+	err = d.Set("trigger_mode", a.TriggerMode)
+	if err != nil {
+		return diag.Errorf("error setting trigger_mode for resource %s: %s", d.Id(), err)
+	}
+	err = d.Set("throttle_time_seconds", a.ThrottleTimeSeconds)
+	if err != nil {
+		return diag.Errorf("error setting throttle_time_seconds for resource %s: %s", d.Id(), err)
+	}
+	err = d.Set("throttle_field", a.ThrottleField)
+	if err != nil {
+		return diag.Errorf("error setting throttle_field for resource %s: %s", d.Id(), err)
+	}
+	err = d.Set("actions", a.Actions)
+	if err != nil {
+		return diag.Errorf("error setting actions for resource %s: %s", d.Id(), err)
+	}
+	err = d.Set("labels", a.Labels)
+	if err != nil {
+		return diag.Errorf("error setting labels for resource %s: %s", d.Id(), err)
+	}
+	err = d.Set("run_as_user_id", a.RunAsUserID)
+	if err != nil {
+		return diag.Errorf("error setting run_as_user_id for resource %s: %s", d.Id(), err)
+	}
+	err = d.Set("query_ownership_type", a.QueryOwnershipType)
+	if err != nil {
+		return diag.Errorf("error setting query_ownership_type for resource %s: %s", d.Id(), err)
+	}
+	return nil
+}
+
+func resourceAggregateAlertUpdate(ctx context.Context, d *schema.ResourceData, client interface{}) diag.Diagnostics {
+	aggregateAlert, err := aggregateAlertFromResourceData(d)
+	if err != nil {
+		return diag.Errorf("could not obtain aggregate alert from resource data: %s", err)
+	}
+
+	_, err = client.(*providerMeta).aggregateAlerts().Update(
+		d.Get("repository").(string),
+		&aggregateAlert,
+	)
+	if err != nil {
+		return diag.Errorf("could not update aggregate alert: %s", err)
+	}
+
+	return resourceAggregateAlertRead(ctx, d, client)
+}
+
+func resourceAggregateAlertDelete(_ context.Context, d *schema.ResourceData, client interface{}) diag.Diagnostics {
+	aggregateAlert, err := aggregateAlertFromResourceData(d)
+	if err != nil {
+		return diag.Errorf("could not obtain aggregate alert from resource data: %s", err)
+	}
+
+	err = client.(*providerMeta).aggregateAlerts().Delete(
+		d.Get("repository").(string),
+		aggregateAlert.Name,
+	)
+	if err != nil {
+		return diag.Errorf("could not delete aggregate alert: %s", err)
+	}
+	return nil
+}
+
+func aggregateAlertFromResourceData(d *schema.ResourceData) (AggregateAlert, error) {
+	return AggregateAlert{
+		ID:                    d.Get("aggregate_alert_id").(string),
+		Name:                  d.Get("name").(string),
+		Description:           d.Get("description").(string),
+		QueryString:           d.Get("query").(string),
+		Actions:               convertInterfaceListToStringSlice(d.Get("actions").([]interface{})),
+		Labels:                convertInterfaceListToStringSlice(d.Get("labels").([]interface{})),
+		Enabled:               d.Get("enabled").(bool),
+		ThrottleTimeSeconds:   d.Get("throttle_time_seconds").(int),
+		ThrottleField:         d.Get("throttle_field").(string),
+		SearchIntervalSeconds: d.Get("search_interval_seconds").(int),
+		QueryTimestampType:    d.Get("query_timestamp_type").(string),
+		TriggerMode:           d.Get("trigger_mode").(string),
+		RunAsUserID:           d.Get("run_as_user_id").(string),
+		QueryOwnershipType:    d.Get("query_ownership_type").(string),
+	}, nil
+}
@@ -0,0 +1,248 @@
+// Copyright © 2020 Humio Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package humio
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	humio "github.com/humio/cli/api"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccAggregateAlertRequiredFields(t *testing.T) {
+	config := aggregateAlertEmpty
+	accTestCase(t, []resource.TestStep{
+		{Config: config, ExpectError: regexp.MustCompile(`The argument "repository" is required, but no definition was found.`)},
+		{Config: config, ExpectError: regexp.MustCompile(`The argument "name" is required, but no definition was found.`)},
+		{Config: config, ExpectError: regexp.MustCompile(`The argument "query" is required, but no definition was found.`)},
+		{Config: config, ExpectError: regexp.MustCompile(`The argument "search_interval_seconds" is required, but no definition was found.`)},
+		{Config: config, ExpectError: regexp.MustCompile(`The argument "throttle_time_seconds" is required, but no definition was found.`)},
+	}, nil)
+}
+
+func TestAccAggregateAlertInvalidInputs(t *testing.T) {
+	config := aggregateAlertInvalidInputs
+	accTestCase(t, []resource.TestStep{
+		{Config: config, ExpectError: regexp.MustCompile(`Inappropriate value for attribute "repository"`)},
+		{Config: config, ExpectError: regexp.MustCompile(`Inappropriate value for attribute "name"`)},
+		{Config: config, ExpectError: regexp.MustCompile(`Inappropriate value for attribute "query"`)},
+		{Config: config, ExpectError: regexp.MustCompile(`Inappropriate value for attribute "search_interval_seconds"`)},
+		{Config: config, ExpectError: regexp.MustCompile(`Inappropriate value for attribute "throttle_time_seconds"`)},
+		{Config: config, ExpectError: regexp.MustCompile(`Inappropriate value for attribute "labels"`)},
+		{Config: config, ExpectError: regexp.MustCompile(`Inappropriate value for attribute "actions"`)},
+	}, nil)
+}
+
+func TestAccAggregateAlertInvalidEnums(t *testing.T) {
+	config := aggregateAlertInvalidEnums
+	accTestCase(t, []resource.TestStep{
+		{Config: config, ExpectError: regexp.MustCompile(`expected query_timestamp_type to be one of \["EventTimestamp" "IngestTimestamp"\]`)},
+		{Config: config, ExpectError: regexp.MustCompile(`expected trigger_mode to be one of \["CompleteMode" "ImmediateMode"\]`)},
+		{Config: config, ExpectError: regexp.MustCompile(`query_ownership_type must be 'User' or 'Organization'`)},
+	}, nil)
+}
+
+func TestAccAggregateAlertBasic(t *testing.T) {
+	accTestCase(t, []resource.TestStep{
+		{
+			Config: aggregateAlertBasic,
+			Check: resource.ComposeTestCheckFunc(
+				resource.TestCheckResourceAttrSet("humio_aggregate_alert.test", "aggregate_alert_id"),
+				resource.TestCheckResourceAttr("humio_aggregate_alert.test", "repository", "sandbox"),
+				resource.TestCheckResourceAttr("humio_aggregate_alert.test", "name", "aggregate-alert-test"),
+				resource.TestCheckResourceAttr("humio_aggregate_alert.test", "query", "loglevel=ERROR | count()"),
+				resource.TestCheckResourceAttr("humio_aggregate_alert.test", "search_interval_seconds", "3600"),
+				resource.TestCheckResourceAttr("humio_aggregate_alert.test", "throttle_time_seconds", "3600"),
+				resource.TestCheckResourceAttr("humio_aggregate_alert.test", "query_timestamp_type", "EventTimestamp"),
+				resource.TestCheckResourceAttr("humio_aggregate_alert.test", "trigger_mode", "CompleteMode"),
+				resource.TestCheckResourceAttr("humio_aggregate_alert.test", "enabled", "false"),
+				resource.TestCheckResourceAttr("humio_aggregate_alert.test", "query_ownership_type", "Organization"),
+				resource.TestCheckNoResourceAttr("humio_aggregate_alert.test", "actions"),
+				resource.TestCheckNoResourceAttr("humio_aggregate_alert.test", "labels"),
+			),
+		},
+	}, testAccCheckAggregateAlertDestroy)
+}
+
+func TestAccAggregateAlertBasicToFull(t *testing.T) {
+	accTestCase(t, []resource.TestStep{
+		{
+			Config: aggregateAlertBasic,
+			Check: resource.ComposeTestCheckFunc(
+				resource.TestCheckResourceAttr("humio_aggregate_alert.test", "repository", "sandbox"),
+				resource.TestCheckResourceAttr("humio_aggregate_alert.test", "name", "aggregate-alert-test"),
+				resource.TestCheckResourceAttr("humio_aggregate_alert.test", "enabled", "false"),
+				resource.TestCheckNoResourceAttr("humio_aggregate_alert.test", "actions"),
+				resource.TestCheckNoResourceAttr("humio_aggregate_alert.test", "labels"),
+			),
+		},
+		{
+			Config:             aggregateAlertFull,
+			PlanOnly:           true,
+			ExpectNonEmptyPlan: true,
+		},
+		{
+			Config: aggregateAlertFull,
+			Check: resource.ComposeTestCheckFunc(
+				resource.TestCheckResourceAttr("humio_aggregate_alert.test", "repository", "sandbox"),
+				resource.TestCheckResourceAttr("humio_aggregate_alert.test", "name", "aggregate-alert-test"),
+				resource.TestCheckResourceAttr("humio_aggregate_alert.test", "description", "error count by host"),
+				resource.TestCheckResourceAttr("humio_aggregate_alert.test", "query", "loglevel=ERROR | groupBy(host)"),
+				resource.TestCheckResourceAttr("humio_aggregate_alert.test", "enabled", "true"),
+				resource.TestCheckResourceAttr("humio_aggregate_alert.test", "search_interval_seconds", "900"),
+				resource.TestCheckResourceAttr("humio_aggregate_alert.test", "throttle_time_seconds", "1800"),
+				resource.TestCheckResourceAttr("humio_aggregate_alert.test", "throttle_field", "host"),
+				resource.TestCheckResourceAttr("humio_aggregate_alert.test", "query_timestamp_type", "IngestTimestamp"),
+				resource.TestCheckResourceAttr("humio_aggregate_alert.test", "trigger_mode", "ImmediateMode"),
+				resource.TestCheckResourceAttr("humio_aggregate_alert.test", "labels.#", "2"),
+				resource.TestCheckResourceAttr("humio_aggregate_alert.test", "labels.0", "errors"),
+				resource.TestCheckResourceAttr("humio_aggregate_alert.test", "labels.1", "hosts"),
+				resource.TestCheckResourceAttr("humio_aggregate_alert.test", "actions.#", "1"),
+				resource.TestCheckResourceAttrSet("humio_aggregate_alert.test", "actions.0"),
+			),
+		},
+	}, testAccCheckAggregateAlertDestroy)
+}
+
+func TestAccAggregateAlertImport(t *testing.T) {
+	accTestCase(t, []resource.TestStep{
+		{
+			Config: aggregateAlertBasic,
+		},
+		{
+			ResourceName:      "humio_aggregate_alert.test",
+			ImportState:       true,
+			ImportStateId:     "sandbox+aggregate-alert-test",
+			ImportStateVerify: true,
+		},
+	}, testAccCheckAggregateAlertDestroy)
+}
+
+func testAccCheckAggregateAlertDestroy(s *terraform.State) error {
+	conn := testAccProviders["humio"].Meta().(*providerMeta).client
+
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "humio_aggregate_alert" {
+			continue
+		}
+		parts := parseRepositoryAndID(rs.Primary.ID)
+		resp, err := newAggregateAlerts(conn).Get(parts[0], parts[1])
+		if err == nil {
+			return fmt.Errorf("aggregate alert still exists: %#+v", resp)
+		}
+	}
+	return nil
+}
+
+const aggregateAlertEmpty = `
+resource "humio_aggregate_alert" "test" {}
+`
+
+const aggregateAlertInvalidInputs = `
+resource "humio_aggregate_alert" "test" {
+	repository              = ["invalid"]
+	name                    = ["invalid"]
+	query                   = ["invalid"]
+	search_interval_seconds = "invalid"
+	throttle_time_seconds   = "invalid"
+	labels                  = "invalid"
+	actions                 = "invalid"
+}
+`
+
+const aggregateAlertInvalidEnums = `
+resource "humio_aggregate_alert" "test" {
+	repository              = "sandbox"
+	name                    = "aggregate-alert-invalid"
+	query                   = "count()"
+	search_interval_seconds = 3600
+	throttle_time_seconds   = 3600
+	query_timestamp_type    = "eventtimestamp"
+	trigger_mode            = "complete"
+	query_ownership_type    = "organization"
+}
+`
+
+const aggregateAlertBasic = `
+resource "humio_aggregate_alert" "test" {
+	repository              = "sandbox"
+	name                    = "aggregate-alert-test"
+	query                   = "loglevel=ERROR | count()"
+	search_interval_seconds = 3600
+	throttle_time_seconds   = 3600
+}
+`
+
+const aggregateAlertFull = `
+resource "humio_action" "test" {
+    repository = "sandbox"
+    type       = "EmailAction"
+    name       = "aggregate-alert-email-test"
+    email {
+        recipients = ["test@example.org"]
+    }
+}
+
+resource "humio_aggregate_alert" "test" {
+	repository              = "sandbox"
+	name                    = "aggregate-alert-test"
+	description             = "error count by host"
+	query                   = "loglevel=ERROR | groupBy(host)"
+	enabled                 = true
+	search_interval_seconds = 900
+	throttle_time_seconds   = 1800
+	throttle_field          = "host"
+	query_timestamp_type    = "IngestTimestamp"
+	trigger_mode            = "ImmediateMode"
+	labels                  = ["errors", "hosts"]
+	actions                 = [humio_action.test.action_id]
+}
+`
+
+var wantAggregateAlert = AggregateAlert{
+	ID:                    "",
+	Name:                  "error count",
+	Description:           "error count by host",
+	QueryString:           "loglevel=ERROR | groupBy(host)",
+	Actions:               []string{"action1", "action2"},
+	Labels:                []string{"important", "error"},
+	Enabled:               true,
+	ThrottleTimeSeconds:   1800,
+	ThrottleField:         "host",
+	SearchIntervalSeconds: 900,
+	QueryTimestampType:    "IngestTimestamp",
+	TriggerMode:           "ImmediateMode",
+	RunAsUserID:           "",
+	QueryOwnershipType:    humio.QueryOwnershipTypeOrganization,
+}
+
+func TestEncodeDecodeAggregateAlertResource(t *testing.T) {
+	res := resourceAggregateAlert()
+	data := res.TestResourceData()
+	resourceDataFromAggregateAlert(&wantAggregateAlert, data)
+	got, err := aggregateAlertFromResourceData(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !cmp.Equal(wantAggregateAlert, got) {
+		t.Error(cmp.Diff(wantAggregateAlert, got))
+	}
+}
@@ -17,9 +17,12 @@ package humio
 import (
 	"context"
 	"fmt"
+	"log"
+	"time"
 
 	"github.com/hashicorp/go-cty/cty"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/customdiff"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 
 	humio "github.com/humio/cli/api"
@@ -34,6 +37,10 @@ func resourceAlert() *schema.Resource {
 		Importer: &schema.ResourceImporter{
 			StateContext: schema.ImportStatePassthroughContext,
 		},
+		CustomizeDiff: customdiff.All(
+			validateQueryDiff("query"),
+			resourceAlertCustomizeDiff,
+		),
 
 		Schema: map[string]*schema.Schema{
 			"alert_id": {
@@ -59,20 +66,29 @@ func resourceAlert() *schema.Resource {
 				Optional: true,
 				Default:  false,
 			},
+			"throttle_time": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				Computed:         true,
+				ExactlyOneOf:     []string{"throttle_time", "throttle_time_millis"},
+				ValidateDiagFunc: validateDuration,
+				DiffSuppressFunc: suppressEquivalentDurations,
+			},
 			"throttle_time_millis": {
-				Type:     schema.TypeInt,
-				Required: true,
-				// TODO: Figure out if we want to accept similar input as "start", if yes reuse the ValidateDiagFunc for "start" and rename this field.
+				Type:       schema.TypeInt,
+				Optional:   true,
+				Computed:   true,
+				Deprecated: "use throttle_time instead, e.g. throttle_time = \"5m\"",
 			},
 			"throttle_field": {
 				Type:     schema.TypeString,
 				Optional: true,
 			},
 			"start": {
-				Type:     schema.TypeString,
-				Required: true,
-				// TODO: Add ValidateDiagFunc, we accept only digits followed by a unit, e.g. 5m, 24h, 2w
-				// ValidateDiagFunc:
+				Type:             schema.TypeString,
+				Required:         true,
+				ValidateDiagFunc: validateDuration,
+				DiffSuppressFunc: suppressEquivalentDurations,
 			},
 			"query": {
 				Type:     schema.TypeString,
@@ -94,28 +110,42 @@ func resourceAlert() *schema.Resource {
 				Elem:     &schema.Schema{Type: schema.TypeString},
 			},
 			"query_ownership_type": {
-				Type:     schema.TypeString,
-				Optional: true,
-				Elem:     &schema.Schema{Type: schema.TypeString},
-				ValidateDiagFunc: func(v interface{}, path cty.Path) diag.Diagnostics {
-					value := v.(string)
-					if value == "Organization" || value == "User" {
-						return nil
-					}
-					return diag.Errorf("query_ownership_type must be 'User' or 'Organization' (case sensitive)")
-				},
+				Type:             schema.TypeString,
+				Optional:         true,
+				Elem:             &schema.Schema{Type: schema.TypeString},
+				ValidateDiagFunc: validateQueryOwnershipType,
 			},
 		},
 	}
 }
 
+func validateQueryOwnershipType(v interface{}, _ cty.Path) diag.Diagnostics {
+	value := v.(string)
+	if value == humio.QueryOwnershipTypeOrganization || value == humio.QueryOwnershipTypeUser {
+		return nil
+	}
+	return diag.Errorf("query_ownership_type must be 'User' or 'Organization' (case sensitive)")
+}
+
+// resourceAlertCustomizeDiff marks the throttle time attribute not in the configuration as changing whenever the
+// configured one changes, as both hold the same value.
+func resourceAlertCustomizeDiff(_ context.Context, d *schema.ResourceDiff, _ interface{}) error {
+	if d.HasChange("throttle_time_millis") {
+		return d.SetNewComputed("throttle_time")
+	}
+	if d.HasChange("throttle_time") {
+		return d.SetNewComputed("throttle_time_millis")
+	}
+	return nil
+}
+
 func resourceAlertCreate(ctx context.Context, d *schema.ResourceData, client interface{}) diag.Diagnostics {
 	alert, err := alertFromResourceData(d)
 	if err != nil {
 		return diag.Errorf("could not obtain alert from resource data: %s", err)
 	}
 
-	_, err = client.(*humio.Client).Alerts().Add(
+	_, err = client.(*providerMeta).alerts().Add(
 		d.Get("repository").(string),
 		&alert,
 	)
@@ -145,10 +175,15 @@ func resourceAlertRead(_ context.Context, d *schema.ResourceData, client interfa
 		}
 	}
 
-	alert, err := client.(*humio.Client).Alerts().Get(
+	alert, err := client.(*providerMeta).alerts().Get(
 		d.Get("repository").(string),
 		d.Get("name").(string),
 	)
+	if isNotFound(err) {
+		log.Printf("[WARN] alert %s not found, removing from state", d.Id())
+		d.SetId("")
+		return nil
+	}
 	if err != nil {
 		return diag.Errorf("could not get alert: %s", err)
 	}
@@ -172,6 +207,14 @@ func resourceDataFromAlert(a *humio.Alert, d *schema.ResourceData) diag.Diagnost
 	if err != nil {
 		return diag.Errorf("error setting throttle_time_millis for resource %s: %s", d.Id(), err)
 	}
+	throttleTime := time.Duration(a.ThrottleTimeMillis) * time.Millisecond
+	// Keep the throttle time as written in the configuration, if it is equivalent to the one in LogScale.
+	if old, err := parseDuration(d.Get("throttle_time").(string)); err != nil || old != throttleTime {
+		err = d.Set("throttle_time", formatDuration(throttleTime))
+		if err != nil {
+			return diag.Errorf("error setting throttle_time for resource %s: %s", d.Id(), err)
+		}
+	}
 	err = d.Set("throttle_field", a.ThrottleField)
 	if err != nil {
 		return diag.Errorf("error setting throttle_field for resource %s: %s", d.Id(), err)
@@ -213,7 +256,7 @@ func resourceAlertUpdate(ctx context.Context, d *schema.ResourceData, client int
 		return diag.Errorf("could not obtain alert from resource data: %s", err)
 	}
 
-	_, err = client.(*humio.Client).Alerts().Update(
+	_, err = client.(*providerMeta).alerts().Update(
 		d.Get("repository").(string),
 		&alert,
 	)
@@ -230,7 +273,7 @@ func resourceAlertDelete(_ context.Context, d *schema.ResourceData, client inter
 		return diag.Errorf("could not obtain alert from resource data: %s", err)
 	}
 
-	err = client.(*humio.Client).Alerts().Delete(
+	err = client.(*providerMeta).alerts().Delete(
 		d.Get("repository").(string),
 		alert.Name,
 	)
@@ -241,11 +284,23 @@ func resourceAlertDelete(_ context.Context, d *schema.ResourceData, client inter
 }
 
 func alertFromResourceData(d *schema.ResourceData) (humio.Alert, error) {
+	throttleTimeMillis := d.Get("throttle_time_millis").(int)
+	// Both throttle time attributes are computed, so the value from the one set in the configuration is used.
+	if config := d.GetRawConfig(); !config.IsNull() && config.IsKnown() {
+		if v := config.GetAttr("throttle_time"); !v.IsNull() && v.IsKnown() {
+			throttleTime, err := parseDuration(v.AsString())
+			if err != nil {
+				return humio.Alert{}, fmt.Errorf("invalid throttle_time: %w", err)
+			}
+			throttleTimeMillis = int(throttleTime.Milliseconds())
+		}
+	}
+
 	return humio.Alert{
 		ID:                 d.Get("alert_id").(string),
 		Name:               d.Get("name").(string),
 		Description:        d.Get("description").(string),
-		ThrottleTimeMillis: d.Get("throttle_time_millis").(int),
+		ThrottleTimeMillis: throttleTimeMillis,
 		ThrottleField:      d.Get("throttle_field").(string),
 		Enabled:            d.Get("enabled").(bool),
 		Actions:            convertInterfaceListToStringSlice(d.Get("actions").([]interface{})),
@@ -32,7 +32,7 @@ func TestAccAlertRequiredFields(t *testing.T) {
 	accTestCase(t, []resource.TestStep{
 		{Config: config, ExpectError: regexp.MustCompile(`The argument "repository" is required, but no definition was found.`)},
 		{Config: config, ExpectError: regexp.MustCompile(`The argument "name" is required, but no definition was found.`)},
-		{Config: config, ExpectError: regexp.MustCompile(`The argument "throttle_time_millis" is required, but no definition was found.`)},
+		{Config: config, ExpectError: regexp.MustCompile("one of `throttle_time,throttle_time_millis` must be specified")},
 		{Config: config, ExpectError: regexp.MustCompile(`The argument "start" is required, but no definition was found.`)},
 		{Config: config, ExpectError: regexp.MustCompile(`The argument "query" is required, but no definition was found.`)},
 	}, nil)
@@ -72,6 +72,23 @@ func TestAccAlertBasic(t *testing.T) {
 	}, testAccCheckAlertDestroy)
 }
 
+func TestAccAlertDisappears(t *testing.T) {
+	accTestCase(t, []resource.TestStep{
+		{
+			Config: alertBasic,
+			Check: resource.ComposeTestCheckFunc(
+				resource.TestCheckResourceAttr("humio_alert.test", "name", "alert-test"),
+				testAccCheckAlertDisappears("humio_alert.test"),
+			),
+			ExpectNonEmptyPlan: true,
+		},
+		{
+			Config: alertBasic,
+			Check:  resource.TestCheckResourceAttr("humio_alert.test", "name", "alert-test"),
+		},
+	}, testAccCheckAlertDestroy)
+}
+
 func TestAccAlertBasicToFull(t *testing.T) {
 	accTestCase(t, []resource.TestStep{
 		{
@@ -150,7 +167,7 @@ func TestAccAlertFull(t *testing.T) {
 }
 
 func testAccCheckAlertDestroy(s *terraform.State) error {
-	conn := testAccProviders["humio"].Meta().(*humio.Client)
+	conn := testAccProviders["humio"].Meta().(*providerMeta).client
 
 	for _, rs := range s.RootModule().Resources {
 		if rs.Type != "humio_alert" {
@@ -167,6 +184,73 @@ func testAccCheckAlertDestroy(s *terraform.State) error {
 	return nil
 }
 
+func testAccCheckAlertDisappears(name string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[name]
+		if !ok {
+			return fmt.Errorf("resource not found: %s", name)
+		}
+		conn := testAccProviders["humio"].Meta().(*providerMeta).client
+		parts := parseRepositoryAndID(rs.Primary.ID)
+		return conn.Alerts().Delete(parts[0], parts[1])
+	}
+}
+
+func TestAccAlertInvalidQuery(t *testing.T) {
+	accTestCase(t, []resource.TestStep{
+		{
+			Config:      alertInvalidQuery,
+			PlanOnly:    true,
+			ExpectError: regexp.MustCompile(`query is not a valid query in repository sandbox`),
+		},
+	}, nil)
+}
+
+func TestAccAlertThrottleTime(t *testing.T) {
+	accTestCase(t, []resource.TestStep{
+		{
+			Config: fmt.Sprintf(alertThrottleTime, "1h", "1d"),
+			Check: resource.ComposeTestCheckFunc(
+				resource.TestCheckResourceAttr("humio_alert.test", "throttle_time", "1h"),
+				resource.TestCheckResourceAttr("humio_alert.test", "throttle_time_millis", "3600000"),
+				resource.TestCheckResourceAttr("humio_alert.test", "start", "1d"),
+			),
+		},
+		// Equivalent durations written differently must not cause a diff.
+		{
+			Config:   fmt.Sprintf(alertThrottleTime, "60m", "24h"),
+			PlanOnly: true,
+		},
+		// Switching to the deprecated attribute with the same value must not cause a diff either.
+		{
+			Config:   alertBasic,
+			PlanOnly: true,
+		},
+		{
+			Config: fmt.Sprintf(alertThrottleTime, "2h", "1w"),
+			Check: resource.ComposeTestCheckFunc(
+				resource.TestCheckResourceAttr("humio_alert.test", "throttle_time", "2h"),
+				resource.TestCheckResourceAttr("humio_alert.test", "throttle_time_millis", "7200000"),
+				resource.TestCheckResourceAttr("humio_alert.test", "start", "1w"),
+			),
+		},
+		{
+			Config: fmt.Sprintf(alertThrottleTime, "2h", "1mon"),
+			Check:  resource.TestCheckResourceAttr("humio_alert.test", "start", "1mon"),
+		},
+		{
+			Config:      fmt.Sprintf(alertThrottleTime, "1h", "2fortnights"),
+			PlanOnly:    true,
+			ExpectError: regexp.MustCompile(`unknown unit "fortnights"`),
+		},
+		{
+			Config:      fmt.Sprintf(alertThrottleTime, "one hour", "24h"),
+			PlanOnly:    true,
+			ExpectError: regexp.MustCompile(`"one hour" is not a duration`),
+		},
+	}, testAccCheckAlertDestroy)
+}
+
 const alertEmpty = `
 resource "humio_alert" "test" {}
 `
@@ -195,6 +279,26 @@ resource "humio_alert" "test" {
 }
 `
 
+const alertThrottleTime = `
+resource "humio_alert" "test" {
+	repository    = "sandbox"
+	name          = "alert-test"
+	throttle_time = "%s"
+	start         = "%s"
+	query         = "loglevel=ERROR"
+}
+`
+
+const alertInvalidQuery = `
+resource "humio_alert" "test" {
+	repository           = "sandbox"
+	name                 = "alert-test"
+	throttle_time_millis = 3600000
+	start                = "24h"
+	query                = "loglevel=ERROR | nosuchfunction()"
+}
+`
+
 const alertFull = `
 resource "humio_action" "test" {
     repository = "sandbox"
@@ -246,4 +350,7 @@ func TestEncodeDecodeAlertResource(t *testing.T) {
 	if !cmp.Equal(wantAlert, got) {
 		t.Error(cmp.Diff(wantAlert, got))
 	}
+	if got := data.Get("throttle_time").(string); got != "1h" {
+		t.Errorf("throttle_time = %q, want %q", got, "1h")
+	}
 }
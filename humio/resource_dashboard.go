@@ -0,0 +1,236 @@
+// Copyright © 2020 Humio Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package humio
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"gopkg.in/yaml.v3"
+)
+
+func resourceDashboard() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceDashboardCreate,
+		ReadContext:   resourceDashboardRead,
+		DeleteContext: resourceDashboardDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"dashboard_id": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"repository": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"name": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			// LogScale can only create dashboards from templates, so changing the template replaces the dashboard.
+			"template": {
+				Type:             schema.TypeString,
+				Required:         true,
+				ForceNew:         true,
+				ValidateDiagFunc: validateDashboardTemplate,
+				StateFunc: func(v interface{}) string {
+					template, err := normalizeDashboardTemplate(v.(string))
+					if err != nil {
+						return v.(string)
+					}
+					return template
+				},
+			},
+		},
+	}
+}
+
+func resourceDashboardCreate(ctx context.Context, d *schema.ResourceData, client interface{}) diag.Diagnostics {
+	dashboard, err := dashboardFromResourceData(d)
+	if err != nil {
+		return diag.Errorf("could not obtain dashboard from resource data: %s", err)
+	}
+
+	_, err = client.(*providerMeta).dashboards().Add(
+		d.Get("repository").(string),
+		&dashboard,
+	)
+	if err != nil {
+		return diag.Errorf("could not create dashboard: %s", err)
+	}
+	d.SetId(fmt.Sprintf("%s+%s", d.Get("repository"), d.Get("name")))
+
+	return resourceDashboardRead(ctx, d, client)
+}
+
+func resourceDashboardRead(_ context.Context, d *schema.ResourceData, client interface{}) diag.Diagnostics {
+	// If we don't have a repository when importing, we parse it from the ID.
+	if _, ok := d.GetOk("repository"); !ok {
+		parts := parseRepositoryAndID(d.Id())
+		//we check that we have parsed the id into the correct number of segments
+		if parts[0] == "" || parts[1] == "" {
+			return diag.Errorf("error importing humio_dashboard. Please make sure the ID is in the form REPOSITORYNAME+DASHBOARDNAME (i.e. myRepoName+myDashboardName")
+		}
+		err := d.Set("repository", parts[0])
+		if err != nil {
+			return diag.Errorf("error setting repository for resource %s: %s", d.Id(), err)
+		}
+		err = d.Set("name", parts[1])
+		if err != nil {
+			return diag.Errorf("error setting name for resource %s: %s", d.Id(), err)
+		}
+	}
+
+	dashboard, err := client.(*providerMeta).dashboards().Get(
+		d.Get("repository").(string),
+		d.Get("name").(string),
+	)
+	if isNotFound(err) {
+		log.Printf("[WARN] dashboard %s not found, removing from state", d.Id())
+		d.SetId("")
+		return nil
+	}
+	if err != nil {
+		return diag.Errorf("could not get dashboard: %s", err)
+	}
+	return resourceDataFromDashboard(dashboard, d)
+}
+
+func resourceDataFromDashboard(a *Dashboard, d *schema.ResourceData) diag.Diagnostics {
+	err := d.Set("dashboard_id", a.ID)
+	if err != nil {
+		return diag.Errorf("error setting dashboard_id for resource %s: %s", d.Id(), err)
+	}
+	err = d.Set("name", a.Name)
+	if err != nil {
+		return diag.Errorf("error setting name for resource %s: %s", d.Id(), err)
+	}
+	// LogScale adds fields and defaults to the templates it exports, so the template in the state is kept as long as
+	// the exported one still has every field it sets. Otherwise every plan would replace the dashboard.
+	template, err := normalizeDashboardTemplate(a.TemplateYAML)
+	if err != nil {
+		return diag.Errorf("could not parse template of dashboard %s: %s", d.Id(), err)
+	}
+	if !dashboardTemplateContains(template, d.Get("template").(string)) {
+		err = d.Set("template", template)
+		if err != nil {
+			return diag.Errorf("error setting template for resource %s: %s", d.Id(), err)
+		}
+	}
+	return nil
+}
+
+func resourceDashboardDelete(_ context.Context, d *schema.ResourceData, client interface{}) diag.Diagnostics {
+	err := client.(*providerMeta).dashboards().Delete(
+		d.Get("repository").(string),
+		d.Get("name").(string),
+	)
+	if err != nil {
+		return diag.Errorf("could not delete dashboard: %s", err)
+	}
+	return nil
+}
+
+func dashboardFromResourceData(d *schema.ResourceData) (Dashboard, error) {
+	return Dashboard{
+		ID:           d.Get("dashboard_id").(string),
+		Name:         d.Get("name").(string),
+		TemplateYAML: d.Get("template").(string),
+	}, nil
+}
+
+// normalizeDashboardTemplate converts a YAML or JSON dashboard template to YAML with sorted keys and consistent
+// indentation, so that formatting differences between the configuration and LogScale do not show up as diffs.
+func normalizeDashboardTemplate(template string) (string, error) {
+	var parsed interface{}
+	err := yaml.Unmarshal([]byte(template), &parsed)
+	if err != nil {
+		return "", err
+	}
+	if _, ok := parsed.(map[string]interface{}); !ok {
+		return "", fmt.Errorf("template must be a YAML or JSON object")
+	}
+	normalized, err := yaml.Marshal(parsed)
+	if err != nil {
+		return "", err
+	}
+	return string(normalized), nil
+}
+
+// dashboardTemplateContains reports whether the exported template has every field set in the template with the same
+// value, ignoring the fields only the exported template has.
+func dashboardTemplateContains(exported, template string) bool {
+	if template == "" {
+		return false
+	}
+	var parsedExported, parsedTemplate interface{}
+	if err := yaml.Unmarshal([]byte(exported), &parsedExported); err != nil {
+		return false
+	}
+	if err := yaml.Unmarshal([]byte(template), &parsedTemplate); err != nil {
+		return false
+	}
+	return yamlContains(parsedExported, parsedTemplate)
+}
+
+func yamlContains(exported, template interface{}) bool {
+	switch t := template.(type) {
+	case map[string]interface{}:
+		e, ok := exported.(map[string]interface{})
+		if !ok {
+			return false
+		}
+		for key, value := range t {
+			if v, ok := e[key]; !ok || !yamlContains(v, value) {
+				return false
+			}
+		}
+		return true
+	case []interface{}:
+		e, ok := exported.([]interface{})
+		if !ok || len(e) != len(t) {
+			return false
+		}
+		for i := range t {
+			if !yamlContains(e[i], t[i]) {
+				return false
+			}
+		}
+		return true
+	case int:
+		// LogScale may export whole numbers as floats.
+		e, ok := exported.(float64)
+		return ok && e == float64(t) || exported == template
+	}
+	return exported == template
+}
+
+func validateDashboardTemplate(v interface{}, _ cty.Path) diag.Diagnostics {
+	_, err := normalizeDashboardTemplate(v.(string))
+	if err != nil {
+		return diag.Errorf("template must be a valid YAML or JSON dashboard template: %s", err)
+	}
+	return nil
+}
@@ -0,0 +1,257 @@
+// Copyright © 2020 Humio Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package humio
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccDashboardRequiredFields(t *testing.T) {
+	accTestCase(t, []resource.TestStep{
+		{Config: dashboardEmpty, ExpectError: regexp.MustCompile(`The argument "repository" is required, but no definition was found.`)},
+		{Config: dashboardEmpty, ExpectError: regexp.MustCompile(`The argument "name" is required, but no definition was found.`)},
+		{Config: dashboardEmpty, ExpectError: regexp.MustCompile(`The argument "template" is required, but no definition was found.`)},
+	}, nil)
+}
+
+func TestAccDashboardInvalidInputs(t *testing.T) {
+	accTestCase(t, []resource.TestStep{
+		{Config: dashboardInvalidTemplate, ExpectError: regexp.MustCompile(`template must be a valid YAML or JSON dashboard template`)},
+	}, nil)
+}
+
+func TestAccDashboardBasic(t *testing.T) {
+	accTestCase(t, []resource.TestStep{
+		{
+			Config: dashboardBasic,
+			Check: resource.ComposeTestCheckFunc(
+				resource.TestCheckResourceAttrSet("humio_dashboard.test", "dashboard_id"),
+				resource.TestCheckResourceAttr("humio_dashboard.test", "repository", "sandbox"),
+				resource.TestCheckResourceAttr("humio_dashboard.test", "name", "dashboard-test"),
+			),
+		},
+		{
+			// Reformatting the template must not cause a diff.
+			Config:   dashboardBasicJSON,
+			PlanOnly: true,
+		},
+		{
+			ResourceName:      "humio_dashboard.test",
+			ImportState:       true,
+			ImportStateId:     "sandbox+dashboard-test",
+			ImportStateVerify: true,
+		},
+	}, testAccCheckDashboardDestroy)
+}
+
+func testAccCheckDashboardDestroy(s *terraform.State) error {
+	conn := testAccProviders["humio"].Meta().(*providerMeta).client
+
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "humio_dashboard" {
+			continue
+		}
+		parts := parseRepositoryAndID(rs.Primary.ID)
+		resp, err := newDashboards(conn).Get(parts[0], parts[1])
+		if err == nil {
+			return fmt.Errorf("dashboard still exists: %#+v", resp)
+		}
+	}
+	return nil
+}
+
+const dashboardEmpty = `
+resource "humio_dashboard" "test" {}
+`
+
+const dashboardInvalidTemplate = `
+resource "humio_dashboard" "test" {
+	repository = "sandbox"
+	name       = "dashboard-test"
+	template   = "[invalid"
+}
+`
+
+const dashboardBasic = `
+resource "humio_dashboard" "test" {
+	repository = "sandbox"
+	name       = "dashboard-test"
+	template   = <<-EOT
+		$schema: https://schemas.humio.com/dashboard/v0.17.0
+		name: dashboard-test
+		timeSelector: {}
+		sharedTimeInterval:
+		  enabled: false
+		  isLive: false
+		  start: 1d
+		widgets:
+		  count:
+		    x: 0
+		    y: 0
+		    height: 4
+		    width: 4
+		    type: query
+		    title: Events
+		    queryString: count()
+		    start: 1d
+		    end: now
+		    isLive: false
+		    visualization: single-value
+	EOT
+}
+`
+
+const dashboardBasicJSON = `
+resource "humio_dashboard" "test" {
+	repository = "sandbox"
+	name       = "dashboard-test"
+	template   = jsonencode({
+		"$schema" = "https://schemas.humio.com/dashboard/v0.17.0"
+		name         = "dashboard-test"
+		timeSelector = {}
+		sharedTimeInterval = {
+			enabled = false
+			isLive  = false
+			start   = "1d"
+		}
+		widgets = {
+			count = {
+				x             = 0
+				y             = 0
+				height        = 4
+				width         = 4
+				type          = "query"
+				title         = "Events"
+				queryString   = "count()"
+				start         = "1d"
+				end           = "now"
+				isLive        = false
+				visualization = "single-value"
+			}
+		}
+	})
+}
+`
+
+var wantDashboard = Dashboard{
+	ID:           "",
+	Name:         "overview",
+	TemplateYAML: "name: overview\nwidgets:\n    count:\n        queryString: count()\n        type: query\n",
+}
+
+func TestEncodeDecodeDashboardResource(t *testing.T) {
+	res := resourceDashboard()
+	data := res.TestResourceData()
+	resourceDataFromDashboard(&wantDashboard, data)
+	got, err := dashboardFromResourceData(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !cmp.Equal(wantDashboard, got) {
+		t.Error(cmp.Diff(wantDashboard, got))
+	}
+}
+
+func TestNormalizeDashboardTemplate(t *testing.T) {
+	yamlTemplate := "widgets:\n  count:\n    type: query\n    queryString: count()\nname: overview\n"
+	jsonTemplate := `{"name": "overview", "widgets": {"count": {"queryString": "count()", "type": "query"}}}`
+
+	normalizedYAML, err := normalizeDashboardTemplate(yamlTemplate)
+	if err != nil {
+		t.Fatal(err)
+	}
+	normalizedJSON, err := normalizeDashboardTemplate(jsonTemplate)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if normalizedYAML != normalizedJSON {
+		t.Error(cmp.Diff(normalizedYAML, normalizedJSON))
+	}
+	if normalizedYAML != wantDashboard.TemplateYAML {
+		t.Error(cmp.Diff(wantDashboard.TemplateYAML, normalizedYAML))
+	}
+
+	for _, invalid := range []string{"[invalid", "- a list", "just a string"} {
+		if _, err := normalizeDashboardTemplate(invalid); err == nil {
+			t.Errorf("expected an error normalizing %q", invalid)
+		}
+	}
+}
+
+func TestReadDashboardWithExportedFields(t *testing.T) {
+	data := resourceDashboard().TestResourceData()
+	if err := data.Set("template", wantDashboard.TemplateYAML); err != nil {
+		t.Fatal(err)
+	}
+
+	exported := wantDashboard
+	exported.TemplateYAML = "$schema: https://schemas.humio.com/dashboard/v0.17.0\n" +
+		"name: overview\n" +
+		"timeSelector: {}\n" +
+		"widgets:\n" +
+		"  count:\n" +
+		"    queryString: count()\n" +
+		"    type: query\n" +
+		"    visualization: table-view\n" +
+		"    width: 4.0\n"
+	if diags := resourceDataFromDashboard(&exported, data); diags.HasError() {
+		t.Fatal(diags)
+	}
+	if got := data.Get("template").(string); got != wantDashboard.TemplateYAML {
+		t.Errorf("template changed to the exported one:\n%s", cmp.Diff(wantDashboard.TemplateYAML, got))
+	}
+
+	changed := wantDashboard
+	changed.TemplateYAML = strings.Replace(exported.TemplateYAML, "count()", "count(field=status)", 1)
+	if diags := resourceDataFromDashboard(&changed, data); diags.HasError() {
+		t.Fatal(diags)
+	}
+	if got := data.Get("template").(string); !strings.Contains(got, "count(field=status)") {
+		t.Errorf("template not updated after the dashboard changed in LogScale:\n%s", got)
+	}
+}
+
+func TestDashboardTemplateContains(t *testing.T) {
+	template := "name: overview\nwidgets:\n  - width: 4\n    queryString: count()\n"
+
+	tests := []struct {
+		exported string
+		want     bool
+	}{
+		{exported: template, want: true},
+		{exported: "$schema: x\nname: overview\nwidgets:\n  - width: 4.0\n    queryString: count()\n    x: 1\n", want: true},
+		{exported: "name: overview\nwidgets:\n  - width: 6\n    queryString: count()\n", want: false},
+		{exported: "name: overview\nwidgets: []\n", want: false},
+		{exported: "name: overview\n", want: false},
+	}
+
+	for _, tt := range tests {
+		if got := dashboardTemplateContains(tt.exported, template); got != tt.want {
+			t.Errorf("dashboardTemplateContains(%q) = %t, want %t", tt.exported, got, tt.want)
+		}
+	}
+	if dashboardTemplateContains(template, "") {
+		t.Error("expected an empty template, as when importing, not to be contained")
+	}
+}
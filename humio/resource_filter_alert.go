@@ -0,0 +1,247 @@
+// Copyright © 2020 Humio Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package humio
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+func resourceFilterAlert() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceFilterAlertCreate,
+		ReadContext:   resourceFilterAlertRead,
+		UpdateContext: resourceFilterAlertUpdate,
+		DeleteContext: resourceFilterAlertDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+		CustomizeDiff: validateQueryDiff("query"),
+
+		Schema: map[string]*schema.Schema{
+			"filter_alert_id": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"repository": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"name": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"description": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"enabled": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+			"query": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"throttle_time_seconds": {
+				Type:             schema.TypeInt,
+				Optional:         true,
+				ValidateDiagFunc: validation.ToDiagFunc(validation.IntAtLeast(0)),
+			},
+			"throttle_field": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				RequiredWith: []string{"throttle_time_seconds"},
+			},
+			"actions": {
+				Type:     schema.TypeList,
+				Optional: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+			"labels": {
+				Type:     schema.TypeList,
+				Optional: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+			"run_as_user_id": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+			},
+			"query_ownership_type": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				Computed:         true,
+				ValidateDiagFunc: validateQueryOwnershipType,
+			},
+		},
+	}
+}
+
+func resourceFilterAlertCreate(ctx context.Context, d *schema.ResourceData, client interface{}) diag.Diagnostics {
+	filterAlert, err := filterAlertFromResourceData(d)
+	if err != nil {
+		return diag.Errorf("could not obtain filter alert from resource data: %s", err)
+	}
+
+	_, err = client.(*providerMeta).filterAlerts().Add(
+		d.Get("repository").(string),
+		&filterAlert,
+	)
+	if err != nil {
+		return diag.Errorf("could not create filter alert: %s", err)
+	}
+	d.SetId(fmt.Sprintf("%s+%s", d.Get("repository"), d.Get("name")))
+
+	return resourceFilterAlertRead(ctx, d, client)
+}
+
+func resourceFilterAlertRead(_ context.Context, d *schema.ResourceData, client interface{}) diag.Diagnostics {
+	// If we don't have a repository when importing, we parse it from the ID.
+	if _, ok := d.GetOk("repository"); !ok {
+		parts := parseRepositoryAndID(d.Id())
+		//we check that we have parsed the id into the correct number of segments
+		if parts[0] == "" || parts[1] == "" {
+			return diag.Errorf("error importing humio_filter_alert. Please make sure the ID is in the form REPOSITORYNAME+FILTERALERTNAME (i.e. myRepoName+myFilterAlertName")
+		}
+		err := d.Set("repository", parts[0])
+		if err != nil {
+			return diag.Errorf("error setting repository for resource %s: %s", d.Id(), err)
+		}
+		err = d.Set("name", parts[1])
+		if err != nil {
+			return diag.Errorf("error setting name for resource %s: %s", d.Id(), err)
+		}
+	}
+
+	filterAlert, err := client.(*providerMeta).filterAlerts().Get(
+		d.Get("repository").(string),
+		d.Get("name").(string),
+	)
+	if isNotFound(err) {
+		log.Printf("[WARN] filter alert %s not found, removing from state", d.Id())
+		d.SetId("")
+		return nil
+	}
+	if err != nil {
+		return diag.Errorf("could not get filter alert: %s", err)
+	}
+	return resourceDataFromFilterAlert(filterAlert, d)
+}
+
+func resourceDataFromFilterAlert(a *FilterAlert, d *schema.ResourceData) diag.Diagnostics {
+	err := d.Set("filter_alert_id", a.ID)
+	if err != nil {
+		return diag.Errorf("error setting filter_alert_id for resource %s: %s", d.Id(), err)
+	}
+	err = d.Set("name", a.Name)
+	if err != nil {
+		return diag.Errorf("error setting name for resource %s: %s", d.Id(), err)
+	}
+	err = d.Set("description", a.Description)
+	if err != nil {
+		return diag.Errorf("error setting description for resource %s: %s", d.Id(), err)
+	}
+	err = d.Set("enabled", a.Enabled)
+	if err != nil {
+		return diag.Errorf("error setting enabled for resource %s: %s", d.Id(), err)
+	}
+	err = d.Set("query", a.QueryString)
+	if err != nil {
+		return diag.Errorf("error setting query for resource %s: %s", d.Id(), err)
+	}
+	err = d.Set("throttle_time_seconds", a.ThrottleTimeSeconds)
+	if err != nil {
+		return diag.Errorf("error setting throttle_time_seconds for resource %s: %s", d.Id(), err)
+	}
+	err = d.Set("throttle_field", a.ThrottleField)
+	if err != nil {
+		return diag.Errorf("error setting throttle_field for resource %s: %s", d.Id(), err)
+	}
+	err = d.Set("actions", a.Actions)
+	if err != nil {
+		return diag.Errorf("error setting actions for resource %s: %s", d.Id(), err)
+	}
+	err = d.Set("labels", a.Labels)
+	if err != nil {
+		return diag.Errorf("error setting labels for resource %s: %s", d.Id(), err)
+	}
+	err = d.Set("run_as_user_id", a.RunAsUserID)
+	if err != nil {
+		return diag.Errorf("error setting run_as_user_id for resource %s: %s", d.Id(), err)
+	}
+	err = d.Set("query_ownership_type", a.QueryOwnershipType)
+	if err != nil {
+		return diag.Errorf("error setting query_ownership_type for resource %s: %s", d.Id(), err)
+	}
+	return nil
+}
+
+func resourceFilterAlertUpdate(ctx context.Context, d *schema.ResourceData, client interface{}) diag.Diagnostics {
+	filterAlert, err := filterAlertFromResourceData(d)
+	if err != nil {
+		return diag.Errorf("could not obtain filter alert from resource data: %s", err)
+	}
+
+	_, err = client.(*providerMeta).filterAlerts().Update(
+		d.Get("repository").(string),
+		&filterAlert,
+	)
+	if err != nil {
+		return diag.Errorf("could not update filter alert: %s", err)
+	}
+
+	return resourceFilterAlertRead(ctx, d, client)
+}
+
+func resourceFilterAlertDelete(_ context.Context, d *schema.ResourceData, client interface{}) diag.Diagnostics {
+	filterAlert, err := filterAlertFromResourceData(d)
+	if err != nil {
+		return diag.Errorf("could not obtain filter alert from resource data: %s", err)
+	}
+
+	err = client.(*providerMeta).filterAlerts().Delete(
+		d.Get("repository").(string),
+		filterAlert.Name,
+	)
+	if err != nil {
+		return diag.Errorf("could not delete filter alert: %s", err)
+	}
+	return nil
+}
+
+func filterAlertFromResourceData(d *schema.ResourceData) (FilterAlert, error) {
+	return FilterAlert{
+		ID:                  d.Get("filter_alert_id").(string),
+		Name:                d.Get("name").(string),
+		Description:         d.Get("description").(string),
+		QueryString:         d.Get("query").(string),
+		Actions:             convertInterfaceListToStringSlice(d.Get("actions").([]interface{})),
+		Labels:              convertInterfaceListToStringSlice(d.Get("labels").([]interface{})),
+		Enabled:             d.Get("enabled").(bool),
+		ThrottleTimeSeconds: d.Get("throttle_time_seconds").(int),
+		ThrottleField:       d.Get("throttle_field").(string),
+		RunAsUserID:         d.Get("run_as_user_id").(string),
+		QueryOwnershipType:  d.Get("query_ownership_type").(string),
+	}, nil
+}
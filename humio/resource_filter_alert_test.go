@@ -0,0 +1,222 @@
+// Copyright © 2020 Humio Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package humio
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	humio "github.com/humio/cli/api"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccFilterAlertRequiredFields(t *testing.T) {
+	config := filterAlertEmpty
+	accTestCase(t, []resource.TestStep{
+		{Config: config, ExpectError: regexp.MustCompile(`The argument "repository" is required, but no definition was found.`)},
+		{Config: config, ExpectError: regexp.MustCompile(`The argument "name" is required, but no definition was found.`)},
+		{Config: config, ExpectError: regexp.MustCompile(`The argument "query" is required, but no definition was found.`)},
+	}, nil)
+}
+
+func TestAccFilterAlertInvalidInputs(t *testing.T) {
+	config := filterAlertInvalidInputs
+	accTestCase(t, []resource.TestStep{
+		{Config: config, ExpectError: regexp.MustCompile(`Inappropriate value for attribute "repository"`)},
+		{Config: config, ExpectError: regexp.MustCompile(`Inappropriate value for attribute "name"`)},
+		{Config: config, ExpectError: regexp.MustCompile(`Inappropriate value for attribute "query"`)},
+		{Config: config, ExpectError: regexp.MustCompile(`Inappropriate value for attribute "throttle_time_seconds"`)},
+		{Config: config, ExpectError: regexp.MustCompile(`Inappropriate value for attribute "labels"`)},
+		{Config: config, ExpectError: regexp.MustCompile(`Inappropriate value for attribute "actions"`)},
+	}, nil)
+}
+
+func TestAccFilterAlertInvalidOwnership(t *testing.T) {
+	accTestCase(t, []resource.TestStep{
+		{Config: filterAlertInvalidOwnership, ExpectError: regexp.MustCompile(`query_ownership_type must be 'User' or 'Organization'`)},
+	}, nil)
+}
+
+func TestAccFilterAlertBasic(t *testing.T) {
+	accTestCase(t, []resource.TestStep{
+		{
+			Config: filterAlertBasic,
+			Check: resource.ComposeTestCheckFunc(
+				resource.TestCheckResourceAttrSet("humio_filter_alert.test", "filter_alert_id"),
+				resource.TestCheckResourceAttr("humio_filter_alert.test", "repository", "sandbox"),
+				resource.TestCheckResourceAttr("humio_filter_alert.test", "name", "filter-alert-test"),
+				resource.TestCheckResourceAttr("humio_filter_alert.test", "query", "loglevel=ERROR"),
+				resource.TestCheckResourceAttr("humio_filter_alert.test", "enabled", "false"),
+				resource.TestCheckResourceAttr("humio_filter_alert.test", "query_ownership_type", "Organization"),
+				resource.TestCheckNoResourceAttr("humio_filter_alert.test", "actions"),
+				resource.TestCheckNoResourceAttr("humio_filter_alert.test", "labels"),
+			),
+		},
+	}, testAccCheckFilterAlertDestroy)
+}
+
+func TestAccFilterAlertBasicToFull(t *testing.T) {
+	accTestCase(t, []resource.TestStep{
+		{
+			Config: filterAlertBasic,
+			Check: resource.ComposeTestCheckFunc(
+				resource.TestCheckResourceAttr("humio_filter_alert.test", "repository", "sandbox"),
+				resource.TestCheckResourceAttr("humio_filter_alert.test", "name", "filter-alert-test"),
+				resource.TestCheckResourceAttr("humio_filter_alert.test", "enabled", "false"),
+				resource.TestCheckNoResourceAttr("humio_filter_alert.test", "actions"),
+				resource.TestCheckNoResourceAttr("humio_filter_alert.test", "labels"),
+			),
+		},
+		{
+			Config:             filterAlertFull,
+			PlanOnly:           true,
+			ExpectNonEmptyPlan: true,
+		},
+		{
+			Config: filterAlertFull,
+			Check: resource.ComposeTestCheckFunc(
+				resource.TestCheckResourceAttr("humio_filter_alert.test", "repository", "sandbox"),
+				resource.TestCheckResourceAttr("humio_filter_alert.test", "name", "filter-alert-test"),
+				resource.TestCheckResourceAttr("humio_filter_alert.test", "description", "errors by host"),
+				resource.TestCheckResourceAttr("humio_filter_alert.test", "query", "loglevel=ERROR"),
+				resource.TestCheckResourceAttr("humio_filter_alert.test", "enabled", "true"),
+				resource.TestCheckResourceAttr("humio_filter_alert.test", "throttle_time_seconds", "3600"),
+				resource.TestCheckResourceAttr("humio_filter_alert.test", "throttle_field", "host"),
+				resource.TestCheckResourceAttr("humio_filter_alert.test", "labels.#", "2"),
+				resource.TestCheckResourceAttr("humio_filter_alert.test", "labels.0", "errors"),
+				resource.TestCheckResourceAttr("humio_filter_alert.test", "labels.1", "hosts"),
+				resource.TestCheckResourceAttr("humio_filter_alert.test", "actions.#", "1"),
+				resource.TestCheckResourceAttrSet("humio_filter_alert.test", "actions.0"),
+			),
+		},
+	}, testAccCheckFilterAlertDestroy)
+}
+
+func TestAccFilterAlertImport(t *testing.T) {
+	accTestCase(t, []resource.TestStep{
+		{
+			Config: filterAlertBasic,
+		},
+		{
+			ResourceName:      "humio_filter_alert.test",
+			ImportState:       true,
+			ImportStateId:     "sandbox+filter-alert-test",
+			ImportStateVerify: true,
+		},
+	}, testAccCheckFilterAlertDestroy)
+}
+
+func testAccCheckFilterAlertDestroy(s *terraform.State) error {
+	conn := testAccProviders["humio"].Meta().(*providerMeta).client
+
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "humio_filter_alert" {
+			continue
+		}
+		parts := parseRepositoryAndID(rs.Primary.ID)
+		resp, err := newFilterAlerts(conn).Get(parts[0], parts[1])
+		if err == nil {
+			return fmt.Errorf("filter alert still exists: %#+v", resp)
+		}
+	}
+	return nil
+}
+
+const filterAlertEmpty = `
+resource "humio_filter_alert" "test" {}
+`
+
+const filterAlertInvalidInputs = `
+resource "humio_filter_alert" "test" {
+	repository            = ["invalid"]
+	name                  = ["invalid"]
+	query                 = ["invalid"]
+	throttle_time_seconds = "invalid"
+	labels                = "invalid"
+	actions               = "invalid"
+}
+`
+
+const filterAlertInvalidOwnership = `
+resource "humio_filter_alert" "test" {
+	repository           = "sandbox"
+	name                 = "filter-alert-invalid"
+	query                = "loglevel=ERROR"
+	query_ownership_type = "organization"
+}
+`
+
+const filterAlertBasic = `
+resource "humio_filter_alert" "test" {
+	repository = "sandbox"
+	name       = "filter-alert-test"
+	query      = "loglevel=ERROR"
+}
+`
+
+const filterAlertFull = `
+resource "humio_action" "test" {
+    repository = "sandbox"
+    type       = "EmailAction"
+    name       = "filter-alert-email-test"
+    email {
+        recipients = ["test@example.org"]
+    }
+}
+
+resource "humio_filter_alert" "test" {
+	repository            = "sandbox"
+	name                  = "filter-alert-test"
+	description           = "errors by host"
+	query                 = "loglevel=ERROR"
+	enabled               = true
+	throttle_time_seconds = 3600
+	throttle_field        = "host"
+	labels                = ["errors", "hosts"]
+	actions               = [humio_action.test.action_id]
+}
+`
+
+var wantFilterAlert = FilterAlert{
+	ID:                  "",
+	Name:                "error filter",
+	Description:         "errors by host",
+	QueryString:         "loglevel=ERROR",
+	Actions:             []string{"action1", "action2"},
+	Labels:              []string{"important", "error"},
+	Enabled:             true,
+	ThrottleTimeSeconds: 300,
+	ThrottleField:       "host",
+	RunAsUserID:         "",
+	QueryOwnershipType:  humio.QueryOwnershipTypeOrganization,
+}
+
+func TestEncodeDecodeFilterAlertResource(t *testing.T) {
+	res := resourceFilterAlert()
+	data := res.TestResourceData()
+	resourceDataFromFilterAlert(&wantFilterAlert, data)
+	got, err := filterAlertFromResourceData(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !cmp.Equal(wantFilterAlert, got) {
+		t.Error(cmp.Diff(wantFilterAlert, got))
+	}
+}
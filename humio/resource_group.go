@@ -0,0 +1,124 @@
+// Copyright © 2020 Humio Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package humio
+
+import (
+	"context"
+	"log"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func resourceGroup() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceGroupCreate,
+		ReadContext:   resourceGroupRead,
+		UpdateContext: resourceGroupUpdate,
+		DeleteContext: resourceGroupDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"group_id": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"display_name": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"lookup_name": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+		},
+	}
+}
+
+func resourceGroupCreate(ctx context.Context, d *schema.ResourceData, client interface{}) diag.Diagnostics {
+	group, err := groupFromResourceData(d)
+	if err != nil {
+		return diag.Errorf("could not obtain group from resource data: %s", err)
+	}
+
+	created, err := newGroups(client.(*providerMeta).client).Add(&group)
+	if err != nil {
+		return diag.Errorf("could not create group: %s", err)
+	}
+	d.SetId(created.ID)
+
+	return resourceGroupRead(ctx, d, client)
+}
+
+func resourceGroupRead(_ context.Context, d *schema.ResourceData, client interface{}) diag.Diagnostics {
+	group, err := newGroups(client.(*providerMeta).client).Get(d.Id())
+	if isNotFound(err) {
+		log.Printf("[WARN] group %s not found, removing from state", d.Id())
+		d.SetId("")
+		return nil
+	}
+	if err != nil {
+		return diag.Errorf("could not get group: %s", err)
+	}
+	return resourceDataFromGroup(group, d)
+}
+
+func resourceDataFromGroup(g *Group, d *schema.ResourceData) diag.Diagnostics {
+	err := d.Set("group_id", g.ID)
+	if err != nil {
+		return diag.Errorf("error setting group_id for resource %s: %s", d.Id(), err)
+	}
+	err = d.Set("display_name", g.DisplayName)
+	if err != nil {
+		return diag.Errorf("error setting display_name for resource %s: %s", d.Id(), err)
+	}
+	err = d.Set("lookup_name", g.LookupName)
+	if err != nil {
+		return diag.Errorf("error setting lookup_name for resource %s: %s", d.Id(), err)
+	}
+	return nil
+}
+
+func resourceGroupUpdate(ctx context.Context, d *schema.ResourceData, client interface{}) diag.Diagnostics {
+	group, err := groupFromResourceData(d)
+	if err != nil {
+		return diag.Errorf("could not obtain group from resource data: %s", err)
+	}
+
+	_, err = newGroups(client.(*providerMeta).client).Update(&group)
+	if err != nil {
+		return diag.Errorf("could not update group: %s", err)
+	}
+
+	return resourceGroupRead(ctx, d, client)
+}
+
+func resourceGroupDelete(_ context.Context, d *schema.ResourceData, client interface{}) diag.Diagnostics {
+	err := newGroups(client.(*providerMeta).client).Delete(d.Id())
+	if err != nil {
+		return diag.Errorf("could not delete group: %s", err)
+	}
+	return nil
+}
+
+func groupFromResourceData(d *schema.ResourceData) (Group, error) {
+	return Group{
+		ID:          d.Id(),
+		DisplayName: d.Get("display_name").(string),
+		LookupName:  d.Get("lookup_name").(string),
+	}, nil
+}
@@ -0,0 +1,110 @@
+// Copyright © 2020 Humio Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package humio
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func resourceGroupMembership() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceGroupMembershipCreate,
+		ReadContext:   resourceGroupMembershipRead,
+		DeleteContext: resourceGroupMembershipDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"group_id": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"user_id": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+		},
+	}
+}
+
+func resourceGroupMembershipCreate(ctx context.Context, d *schema.ResourceData, client interface{}) diag.Diagnostics {
+	groupID := d.Get("group_id").(string)
+	userID := d.Get("user_id").(string)
+
+	err := client.(*providerMeta).client.Groups().AddUserToGroup(groupID, userID)
+	if err != nil {
+		return diag.Errorf("could not add user to group: %s", err)
+	}
+	d.SetId(fmt.Sprintf("%s+%s", groupID, userID))
+
+	return resourceGroupMembershipRead(ctx, d, client)
+}
+
+func resourceGroupMembershipRead(_ context.Context, d *schema.ResourceData, client interface{}) diag.Diagnostics {
+	// If we don't have a group when importing, we parse it from the ID.
+	if _, ok := d.GetOk("group_id"); !ok {
+		parts := parseRepositoryAndID(d.Id())
+		//we check that we have parsed the id into the correct number of segments
+		if parts[0] == "" || parts[1] == "" {
+			return diag.Errorf("error importing humio_group_membership. Please make sure the ID is in the form GROUPID+USERID (i.e. myGroupID+myUserID")
+		}
+		err := d.Set("group_id", parts[0])
+		if err != nil {
+			return diag.Errorf("error setting group_id for resource %s: %s", d.Id(), err)
+		}
+		err = d.Set("user_id", parts[1])
+		if err != nil {
+			return diag.Errorf("error setting user_id for resource %s: %s", d.Id(), err)
+		}
+	}
+
+	userIDs, err := newGroups(client.(*providerMeta).client).UserIDs(d.Get("group_id").(string))
+	if isNotFound(err) {
+		log.Printf("[WARN] group membership %s not found, removing from state", d.Id())
+		d.SetId("")
+		return nil
+	}
+	if err != nil {
+		return diag.Errorf("could not get group members: %s", err)
+	}
+
+	for _, userID := range userIDs {
+		if userID == d.Get("user_id").(string) {
+			return nil
+		}
+	}
+	log.Printf("[WARN] group membership %s not found, removing from state", d.Id())
+	d.SetId("")
+	return nil
+}
+
+func resourceGroupMembershipDelete(_ context.Context, d *schema.ResourceData, client interface{}) diag.Diagnostics {
+	err := client.(*providerMeta).client.Groups().RemoveUserFromGroup(
+		d.Get("group_id").(string),
+		d.Get("user_id").(string),
+	)
+	if err != nil {
+		return diag.Errorf("could not remove user from group: %s", err)
+	}
+	return nil
+}
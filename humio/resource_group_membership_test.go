@@ -0,0 +1,90 @@
+// Copyright © 2020 Humio Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package humio
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccGroupMembershipRequiredFields(t *testing.T) {
+	config := groupMembershipEmpty
+	accTestCase(t, []resource.TestStep{
+		{Config: config, ExpectError: regexp.MustCompile(`The argument "group_id" is required, but no definition was found.`)},
+		{Config: config, ExpectError: regexp.MustCompile(`The argument "user_id" is required, but no definition was found.`)},
+	}, nil)
+}
+
+func TestAccGroupMembershipBasic(t *testing.T) {
+	accTestCase(t, []resource.TestStep{
+		{
+			Config: groupMembershipBasic,
+			Check: resource.ComposeTestCheckFunc(
+				resource.TestCheckResourceAttrPair("humio_group_membership.test", "group_id", "humio_group.test", "group_id"),
+				resource.TestCheckResourceAttrPair("humio_group_membership.test", "user_id", "humio_user.test", "user_id"),
+			),
+		},
+		{
+			ResourceName:      "humio_group_membership.test",
+			ImportState:       true,
+			ImportStateVerify: true,
+		},
+	}, testAccCheckGroupMembershipDestroy)
+}
+
+func testAccCheckGroupMembershipDestroy(s *terraform.State) error {
+	conn := testAccProviders["humio"].Meta().(*providerMeta).client
+
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "humio_group_membership" {
+			continue
+		}
+		parts := parseRepositoryAndID(rs.Primary.ID)
+		userIDs, err := newGroups(conn).UserIDs(parts[0])
+		if err != nil {
+			continue
+		}
+		for _, userID := range userIDs {
+			if userID == parts[1] {
+				return fmt.Errorf("group membership still exists: %s", rs.Primary.ID)
+			}
+		}
+	}
+	return nil
+}
+
+const groupMembershipEmpty = `
+resource "humio_group_membership" "test" {}
+`
+
+const groupMembershipBasic = `
+resource "humio_user" "test" {
+	username = "group-membership-test@example.org"
+}
+
+resource "humio_group" "test" {
+	display_name = "group-membership-test"
+}
+
+resource "humio_group_membership" "test" {
+	group_id = humio_group.test.group_id
+	user_id  = humio_user.test.user_id
+}
+`
@@ -0,0 +1,109 @@
+// Copyright © 2020 Humio Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package humio
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccGroupRequiredFields(t *testing.T) {
+	accTestCase(t, []resource.TestStep{
+		{Config: groupEmpty, ExpectError: regexp.MustCompile(`The argument "display_name" is required, but no definition was found.`)},
+	}, nil)
+}
+
+func TestAccGroupBasicToFull(t *testing.T) {
+	accTestCase(t, []resource.TestStep{
+		{
+			Config: groupBasic,
+			Check: resource.ComposeTestCheckFunc(
+				resource.TestCheckResourceAttrSet("humio_group.test", "group_id"),
+				resource.TestCheckResourceAttr("humio_group.test", "display_name", "group-test"),
+				resource.TestCheckResourceAttr("humio_group.test", "lookup_name", ""),
+			),
+		},
+		{
+			Config: groupFull,
+			Check: resource.ComposeTestCheckFunc(
+				resource.TestCheckResourceAttr("humio_group.test", "display_name", "group-test-renamed"),
+				resource.TestCheckResourceAttr("humio_group.test", "lookup_name", "cn=group-test,ou=groups"),
+			),
+		},
+		{
+			ResourceName:      "humio_group.test",
+			ImportState:       true,
+			ImportStateVerify: true,
+		},
+	}, testAccCheckGroupDestroy)
+}
+
+func testAccCheckGroupDestroy(s *terraform.State) error {
+	conn := testAccProviders["humio"].Meta().(*providerMeta).client
+
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "humio_group" {
+			continue
+		}
+		resp, err := newGroups(conn).Get(rs.Primary.ID)
+		if err == nil {
+			return fmt.Errorf("group still exists: %#+v", resp)
+		}
+	}
+	return nil
+}
+
+const groupEmpty = `
+resource "humio_group" "test" {}
+`
+
+const groupBasic = `
+resource "humio_group" "test" {
+	display_name = "group-test"
+}
+`
+
+const groupFull = `
+resource "humio_group" "test" {
+	display_name = "group-test-renamed"
+	lookup_name  = "cn=group-test,ou=groups"
+}
+`
+
+var wantGroup = Group{
+	ID:          "",
+	DisplayName: "developers",
+	LookupName:  "cn=developers,ou=groups",
+}
+
+func TestEncodeDecodeGroupResource(t *testing.T) {
+	res := resourceGroup()
+	data := res.TestResourceData()
+	resourceDataFromGroup(&wantGroup, data)
+	got, err := groupFromResourceData(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !cmp.Equal(wantGroup, got) {
+		t.Error(cmp.Diff(wantGroup, got))
+	}
+}
@@ -0,0 +1,126 @@
+// Copyright © 2020 Humio Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package humio
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func resourceGroupViewRole() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceGroupViewRoleCreate,
+		ReadContext:   resourceGroupViewRoleRead,
+		DeleteContext: resourceGroupViewRoleDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"group_id": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"view": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"role_id": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+		},
+	}
+}
+
+func resourceGroupViewRoleCreate(ctx context.Context, d *schema.ResourceData, client interface{}) diag.Diagnostics {
+	groupID := d.Get("group_id").(string)
+	view := d.Get("view").(string)
+	roleID := d.Get("role_id").(string)
+
+	err := newGroups(client.(*providerMeta).client).AssignViewRole(groupID, view, roleID)
+	if err != nil {
+		return diag.Errorf("could not assign role to group: %s", err)
+	}
+	d.SetId(fmt.Sprintf("%s+%s+%s", groupID, view, roleID))
+
+	return resourceGroupViewRoleRead(ctx, d, client)
+}
+
+func resourceGroupViewRoleRead(_ context.Context, d *schema.ResourceData, client interface{}) diag.Diagnostics {
+	// If we don't have a group when importing, we parse it from the ID.
+	if _, ok := d.GetOk("group_id"); !ok {
+		parts := strings.Split(d.Id(), "+")
+		//we check that we have parsed the id into the correct number of segments
+		if len(parts) != 3 || parts[0] == "" || parts[1] == "" || parts[2] == "" {
+			return diag.Errorf("error importing humio_group_view_role. Please make sure the ID is in the form GROUPID+VIEWNAME+ROLEID (i.e. myGroupID+myViewName+myRoleID")
+		}
+		err := d.Set("group_id", parts[0])
+		if err != nil {
+			return diag.Errorf("error setting group_id for resource %s: %s", d.Id(), err)
+		}
+		err = d.Set("view", parts[1])
+		if err != nil {
+			return diag.Errorf("error setting view for resource %s: %s", d.Id(), err)
+		}
+		err = d.Set("role_id", parts[2])
+		if err != nil {
+			return diag.Errorf("error setting role_id for resource %s: %s", d.Id(), err)
+		}
+	}
+
+	viewRoles, err := newGroups(client.(*providerMeta).client).ViewRoles(d.Get("group_id").(string))
+	if isNotFound(err) {
+		log.Printf("[WARN] group view role %s not found, removing from state", d.Id())
+		d.SetId("")
+		return nil
+	}
+	if err != nil {
+		return diag.Errorf("could not get group roles: %s", err)
+	}
+
+	want := GroupViewRole{
+		ViewName: d.Get("view").(string),
+		RoleID:   d.Get("role_id").(string),
+	}
+	for _, viewRole := range viewRoles {
+		if viewRole == want {
+			return nil
+		}
+	}
+	log.Printf("[WARN] group view role %s not found, removing from state", d.Id())
+	d.SetId("")
+	return nil
+}
+
+func resourceGroupViewRoleDelete(_ context.Context, d *schema.ResourceData, client interface{}) diag.Diagnostics {
+	err := newGroups(client.(*providerMeta).client).UnassignViewRole(
+		d.Get("group_id").(string),
+		d.Get("view").(string),
+		d.Get("role_id").(string),
+	)
+	if err != nil {
+		return diag.Errorf("could not unassign role from group: %s", err)
+	}
+	return nil
+}
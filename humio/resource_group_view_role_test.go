@@ -0,0 +1,95 @@
+// Copyright © 2020 Humio Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package humio
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccGroupViewRoleRequiredFields(t *testing.T) {
+	config := groupViewRoleEmpty
+	accTestCase(t, []resource.TestStep{
+		{Config: config, ExpectError: regexp.MustCompile(`The argument "group_id" is required, but no definition was found.`)},
+		{Config: config, ExpectError: regexp.MustCompile(`The argument "view" is required, but no definition was found.`)},
+		{Config: config, ExpectError: regexp.MustCompile(`The argument "role_id" is required, but no definition was found.`)},
+	}, nil)
+}
+
+func TestAccGroupViewRoleBasic(t *testing.T) {
+	accTestCase(t, []resource.TestStep{
+		{
+			Config: groupViewRoleBasic,
+			Check: resource.ComposeTestCheckFunc(
+				resource.TestCheckResourceAttrPair("humio_group_view_role.test", "group_id", "humio_group.test", "group_id"),
+				resource.TestCheckResourceAttrPair("humio_group_view_role.test", "role_id", "humio_role.test", "role_id"),
+				resource.TestCheckResourceAttr("humio_group_view_role.test", "view", "sandbox"),
+			),
+		},
+		{
+			ResourceName:      "humio_group_view_role.test",
+			ImportState:       true,
+			ImportStateVerify: true,
+		},
+	}, testAccCheckGroupViewRoleDestroy)
+}
+
+func testAccCheckGroupViewRoleDestroy(s *terraform.State) error {
+	conn := testAccProviders["humio"].Meta().(*providerMeta).client
+
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "humio_group_view_role" {
+			continue
+		}
+		parts := strings.Split(rs.Primary.ID, "+")
+		viewRoles, err := newGroups(conn).ViewRoles(parts[0])
+		if err != nil {
+			continue
+		}
+		for _, viewRole := range viewRoles {
+			if viewRole == (GroupViewRole{ViewName: parts[1], RoleID: parts[2]}) {
+				return fmt.Errorf("group view role still exists: %s", rs.Primary.ID)
+			}
+		}
+	}
+	return nil
+}
+
+const groupViewRoleEmpty = `
+resource "humio_group_view_role" "test" {}
+`
+
+const groupViewRoleBasic = `
+resource "humio_group" "test" {
+	display_name = "group-view-role-test"
+}
+
+resource "humio_role" "test" {
+	name             = "group-view-role-test"
+	view_permissions = ["ReadAccess"]
+}
+
+resource "humio_group_view_role" "test" {
+	group_id = humio_group.test.group_id
+	view     = "sandbox"
+	role_id  = humio_role.test.role_id
+}
+`
@@ -17,9 +17,11 @@ package humio
 import (
 	"context"
 	"fmt"
+	"log"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 
 	humio "github.com/humio/cli/api"
 )
@@ -33,6 +35,13 @@ func resourceIngestToken() *schema.Resource {
 		Importer: &schema.ResourceImporter{
 			StateContext: schema.ImportStatePassthroughContext,
 		},
+		CustomizeDiff: func(_ context.Context, d *schema.ResourceDiff, _ interface{}) error {
+			// Changing rotate_when refreshes the token on the next apply, so the token value is not known until then.
+			if d.Id() != "" && d.HasChange("rotate_when") {
+				return d.SetNewComputed("token")
+			}
+			return nil
+		},
 
 		Schema: map[string]*schema.Schema{
 			"repository": {
@@ -55,6 +64,16 @@ func resourceIngestToken() *schema.Resource {
 				Computed:  true,
 				Sensitive: true,
 			},
+			"rotate_when": {
+				Type:     schema.TypeMap,
+				Optional: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+			"rotation_grace_period_seconds": {
+				Type:             schema.TypeInt,
+				Optional:         true,
+				ValidateDiagFunc: validation.ToDiagFunc(validation.IntAtLeast(0)),
+			},
 		},
 	}
 }
@@ -65,7 +84,7 @@ func resourceIngestTokenCreate(ctx context.Context, d *schema.ResourceData, clie
 		return diag.Errorf("could not obtain alert from resource data: %s", err)
 	}
 
-	_, err = client.(*humio.Client).IngestTokens().Add(
+	_, err = client.(*providerMeta).client.IngestTokens().Add(
 		d.Get("repository").(string),
 		ingestToken.Name,
 		ingestToken.AssignedParser,
@@ -101,10 +120,15 @@ func resourceIngestTokenRead(_ context.Context, d *schema.ResourceData, client i
 		}
 	}
 
-	ingestToken, err := client.(*humio.Client).IngestTokens().Get(
+	ingestToken, err := newIngestTokens(client.(*providerMeta).client).Get(
 		d.Get("repository").(string),
 		d.Get("name").(string),
 	)
+	if isNotFound(err) {
+		log.Printf("[WARN] ingest token %s not found, removing from state", d.Id())
+		d.SetId("")
+		return nil
+	}
 	if err != nil {
 		return diag.Errorf("could not get ingest token: %s", err)
 	}
@@ -133,13 +157,26 @@ func resourceIngestTokenUpdate(ctx context.Context, d *schema.ResourceData, clie
 		return diag.Errorf("could not obtain alert from resource data: %s", err)
 	}
 
-	_, err = client.(*humio.Client).IngestTokens().Update(
-		d.Get("repository").(string),
-		ingestToken.Name,
-		ingestToken.AssignedParser,
-	)
-	if err != nil {
-		return diag.Errorf("could not update ingest token: %s", err)
+	if d.HasChange("parser") {
+		_, err = client.(*providerMeta).client.IngestTokens().Update(
+			d.Get("repository").(string),
+			ingestToken.Name,
+			ingestToken.AssignedParser,
+		)
+		if err != nil {
+			return diag.Errorf("could not update ingest token: %s", err)
+		}
+	}
+
+	if d.HasChange("rotate_when") {
+		err = newIngestTokens(client.(*providerMeta).client).Refresh(
+			d.Get("repository").(string),
+			ingestToken.Name,
+			d.Get("rotation_grace_period_seconds").(int),
+		)
+		if err != nil {
+			return diag.Errorf("could not rotate ingest token: %s", err)
+		}
 	}
 	return resourceIngestTokenRead(ctx, d, client)
 }
@@ -150,7 +187,7 @@ func resourceIngestTokenDelete(_ context.Context, d *schema.ResourceData, client
 		return diag.Errorf("could not obtain alert from resource data: %s", err)
 	}
 
-	err = client.(*humio.Client).IngestTokens().Remove(
+	err = client.(*providerMeta).client.IngestTokens().Remove(
 		d.Get("repository").(string),
 		ingestToken.Name,
 	)
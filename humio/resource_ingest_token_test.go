@@ -64,6 +64,23 @@ func TestAccIngestTokenBasic(t *testing.T) {
 	}, testAccCheckIngestTokenDestroy)
 }
 
+func TestAccIngestTokenDisappears(t *testing.T) {
+	accTestCase(t, []resource.TestStep{
+		{
+			Config: ingestTokenBasic,
+			Check: resource.ComposeTestCheckFunc(
+				resource.TestCheckResourceAttr("humio_ingest_token.test", "name", "ingest-token-test"),
+				testAccCheckIngestTokenDisappears("humio_ingest_token.test"),
+			),
+			ExpectNonEmptyPlan: true,
+		},
+		{
+			Config: ingestTokenBasic,
+			Check:  resource.TestCheckResourceAttrSet("humio_ingest_token.test", "token"),
+		},
+	}, testAccCheckIngestTokenDestroy)
+}
+
 func TestAccIngestTokenBasicToFull(t *testing.T) {
 	accTestCase(t, []resource.TestStep{
 		{
@@ -98,6 +115,28 @@ func TestAccIngestTokenBasicToFull(t *testing.T) {
 	}, testAccCheckIngestTokenDestroy)
 }
 
+func TestAccIngestTokenRotate(t *testing.T) {
+	var token string
+	accTestCase(t, []resource.TestStep{
+		{
+			Config: ingestTokenBasic,
+			Check:  testAccCaptureAttr("humio_ingest_token.test", "token", &token),
+		},
+		{
+			Config: ingestTokenRotated,
+			Check: resource.ComposeTestCheckFunc(
+				resource.TestCheckResourceAttr("humio_ingest_token.test", "rotate_when.rotation", "1"),
+				resource.TestCheckResourceAttr("humio_ingest_token.test", "rotation_grace_period_seconds", "300"),
+				testAccCheckAttrChanged("humio_ingest_token.test", "token", &token),
+			),
+		},
+		{
+			Config:   ingestTokenRotated,
+			PlanOnly: true,
+		},
+	}, testAccCheckIngestTokenDestroy)
+}
+
 func TestAccIngestTokenFull(t *testing.T) {
 	accTestCase(t, []resource.TestStep{
 		{
@@ -113,7 +152,7 @@ func TestAccIngestTokenFull(t *testing.T) {
 }
 
 func testAccCheckIngestTokenDestroy(s *terraform.State) error {
-	conn := testAccProviders["humio"].Meta().(*humio.Client)
+	conn := testAccProviders["humio"].Meta().(*providerMeta).client
 
 	for _, rs := range s.RootModule().Resources {
 		if rs.Type != "humio_ingest_token" {
@@ -130,6 +169,18 @@ func testAccCheckIngestTokenDestroy(s *terraform.State) error {
 	return nil
 }
 
+func testAccCheckIngestTokenDisappears(name string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[name]
+		if !ok {
+			return fmt.Errorf("resource not found: %s", name)
+		}
+		conn := testAccProviders["humio"].Meta().(*providerMeta).client
+		parts := parseRepositoryAndID(rs.Primary.ID)
+		return conn.IngestTokens().Remove(parts[0], parts[1])
+	}
+}
+
 const ingestTokenEmpty = `
 resource "humio_ingest_token" "test" {}
 `
@@ -158,6 +209,18 @@ resource "humio_ingest_token" "test" {
 }
 `
 
+const ingestTokenRotated = `
+resource "humio_ingest_token" "test" {
+	repository = "sandbox"
+	name       = "ingest-token-test"
+
+	rotate_when = {
+		rotation = "1"
+	}
+	rotation_grace_period_seconds = 300
+}
+`
+
 var wantIngestToken = humio.IngestToken{
 	Name:           "testing-shipper",
 	Token:          "",
@@ -0,0 +1,231 @@
+// Copyright © 2020 Humio Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package humio
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+
+	humio "github.com/humio/cli/api"
+)
+
+func resourceLookupFile() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceLookupFileCreate,
+		ReadContext:   resourceLookupFileRead,
+		UpdateContext: resourceLookupFileUpdate,
+		DeleteContext: resourceLookupFileDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+		CustomizeDiff: resourceLookupFileCustomizeDiff,
+
+		Schema: map[string]*schema.Schema{
+			"file_id": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"repository": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"name": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"source": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ExactlyOneOf: []string{"source", "content"},
+			},
+			"content": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			// content_sha256 is the hash of the content we uploaded. A change to it causes the file to be uploaded again.
+			"content_sha256": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			// content_hash is the hash LogScale reports for the file, used to detect changes made outside of Terraform.
+			"content_hash": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+	}
+}
+
+func resourceLookupFileCustomizeDiff(_ context.Context, d *schema.ResourceDiff, _ interface{}) error {
+	// The content may come from another resource that is not created yet, in which case it can only be hashed when
+	// applying.
+	if !d.NewValueKnown("source") || !d.NewValueKnown("content") {
+		err := d.SetNewComputed("content_sha256")
+		if err != nil {
+			return err
+		}
+		return d.SetNewComputed("content_hash")
+	}
+	content, err := lookupFileContent(d.Get("source").(string), d.Get("content").(string))
+	if err != nil {
+		return err
+	}
+	hash := lookupFileHash(content)
+	if hash != d.Get("content_sha256").(string) {
+		err = d.SetNew("content_sha256", hash)
+		if err != nil {
+			return err
+		}
+		return d.SetNewComputed("content_hash")
+	}
+	return nil
+}
+
+func resourceLookupFileCreate(ctx context.Context, d *schema.ResourceData, client interface{}) diag.Diagnostics {
+	diags := uploadLookupFile(d, client)
+	if diags.HasError() {
+		return diags
+	}
+	d.SetId(fmt.Sprintf("%s+%s", d.Get("repository"), d.Get("name")))
+
+	return resourceLookupFileRead(ctx, d, client)
+}
+
+func resourceLookupFileRead(_ context.Context, d *schema.ResourceData, client interface{}) diag.Diagnostics {
+	// If we don't have a repository when importing, we parse it from the ID.
+	if _, ok := d.GetOk("repository"); !ok {
+		parts := parseRepositoryAndID(d.Id())
+		//we check that we have parsed the id into the correct number of segments
+		if parts[0] == "" || parts[1] == "" {
+			return diag.Errorf("error importing humio_lookup_file. Please make sure the ID is in the form REPOSITORYNAME+FILENAME (i.e. myRepoName+myFileName.csv")
+		}
+		err := d.Set("repository", parts[0])
+		if err != nil {
+			return diag.Errorf("error setting repository for resource %s: %s", d.Id(), err)
+		}
+		err = d.Set("name", parts[1])
+		if err != nil {
+			return diag.Errorf("error setting name for resource %s: %s", d.Id(), err)
+		}
+	}
+
+	file, err := newFiles(client.(*providerMeta).client).Get(
+		d.Get("repository").(string),
+		d.Get("name").(string),
+	)
+	if isNotFound(err) {
+		log.Printf("[WARN] lookup file %s not found, removing from state", d.Id())
+		d.SetId("")
+		return nil
+	}
+	if err != nil {
+		return diag.Errorf("could not get lookup file: %s", err)
+	}
+	return resourceDataFromLookupFile(file, d)
+}
+
+func resourceDataFromLookupFile(f *humio.File, d *schema.ResourceData) diag.Diagnostics {
+	// If the file was changed outside of Terraform, forget the hash of the uploaded content so the next plan uploads
+	// it again.
+	if previous := d.Get("content_hash").(string); previous != "" && previous != f.ContentHash {
+		log.Printf("[WARN] lookup file %s was changed outside of Terraform", d.Id())
+		err := d.Set("content_sha256", "")
+		if err != nil {
+			return diag.Errorf("error setting content_sha256 for resource %s: %s", d.Id(), err)
+		}
+	}
+
+	err := d.Set("file_id", f.ID)
+	if err != nil {
+		return diag.Errorf("error setting file_id for resource %s: %s", d.Id(), err)
+	}
+	err = d.Set("name", f.Name)
+	if err != nil {
+		return diag.Errorf("error setting name for resource %s: %s", d.Id(), err)
+	}
+	err = d.Set("content_hash", f.ContentHash)
+	if err != nil {
+		return diag.Errorf("error setting content_hash for resource %s: %s", d.Id(), err)
+	}
+	return nil
+}
+
+func resourceLookupFileUpdate(ctx context.Context, d *schema.ResourceData, client interface{}) diag.Diagnostics {
+	if d.HasChange("content_sha256") {
+		diags := uploadLookupFile(d, client)
+		if diags.HasError() {
+			return diags
+		}
+	}
+
+	return resourceLookupFileRead(ctx, d, client)
+}
+
+func resourceLookupFileDelete(_ context.Context, d *schema.ResourceData, client interface{}) diag.Diagnostics {
+	err := client.(*providerMeta).client.Files().Delete(
+		d.Get("repository").(string),
+		d.Get("name").(string),
+	)
+	if err != nil {
+		return diag.Errorf("could not delete lookup file: %s", err)
+	}
+	return nil
+}
+
+func uploadLookupFile(d *schema.ResourceData, client interface{}) diag.Diagnostics {
+	content, err := lookupFileContent(d.Get("source").(string), d.Get("content").(string))
+	if err != nil {
+		return diag.Errorf("could not read lookup file content: %s", err)
+	}
+
+	err = client.(*providerMeta).client.Files().Upload(
+		d.Get("repository").(string),
+		d.Get("name").(string),
+		bytes.NewReader(content),
+	)
+	if err != nil {
+		return diag.Errorf("could not upload lookup file: %s", err)
+	}
+
+	err = d.Set("content_sha256", lookupFileHash(content))
+	if err != nil {
+		return diag.Errorf("error setting content_sha256 for resource %s: %s", d.Id(), err)
+	}
+	return nil
+}
+
+// lookupFileContent returns the content of the local file at source, or the inline content if no source is given.
+func lookupFileContent(source, content string) ([]byte, error) {
+	if source == "" {
+		return []byte(content), nil
+	}
+
+	return os.ReadFile(source)
+}
+
+func lookupFileHash(content []byte) string {
+	hash := sha256.Sum256(content)
+	return hex.EncodeToString(hash[:])
+}
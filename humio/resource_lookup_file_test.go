@@ -0,0 +1,177 @@
+// Copyright © 2020 Humio Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package humio
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccLookupFileRequiredFields(t *testing.T) {
+	accTestCase(t, []resource.TestStep{
+		{Config: lookupFileEmpty, ExpectError: regexp.MustCompile(`The argument "repository" is required, but no definition was found.`)},
+		{Config: lookupFileEmpty, ExpectError: regexp.MustCompile(`The argument "name" is required, but no definition was found.`)},
+		{Config: lookupFileEmpty, ExpectError: regexp.MustCompile(`one of .content,source. must be specified`)},
+	}, nil)
+}
+
+func TestAccLookupFileInlineToSource(t *testing.T) {
+	source := filepath.Join(t.TempDir(), "hosts.csv")
+	err := os.WriteFile(source, []byte("host,owner\nweb-1,web\nweb-2,web\n"), 0o600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var hash string
+	accTestCase(t, []resource.TestStep{
+		{
+			Config: lookupFileInline,
+			Check: resource.ComposeTestCheckFunc(
+				resource.TestCheckResourceAttrSet("humio_lookup_file.test", "file_id"),
+				resource.TestCheckResourceAttr("humio_lookup_file.test", "repository", "sandbox"),
+				resource.TestCheckResourceAttr("humio_lookup_file.test", "name", "lookup-file-test.csv"),
+				resource.TestCheckResourceAttr("humio_lookup_file.test", "content_sha256", lookupFileHash([]byte("host,owner\nweb-1,web\n"))),
+				resource.TestCheckResourceAttrSet("humio_lookup_file.test", "content_hash"),
+				testAccCaptureAttr("humio_lookup_file.test", "content_hash", &hash),
+			),
+		},
+		{
+			Config: fmt.Sprintf(lookupFileSource, source),
+			Check: resource.ComposeTestCheckFunc(
+				resource.TestCheckResourceAttr("humio_lookup_file.test", "content_sha256", lookupFileHash([]byte("host,owner\nweb-1,web\nweb-2,web\n"))),
+				testAccCheckAttrChanged("humio_lookup_file.test", "content_hash", &hash),
+			),
+		},
+		{
+			ResourceName:            "humio_lookup_file.test",
+			ImportState:             true,
+			ImportStateVerify:       true,
+			ImportStateVerifyIgnore: []string{"source", "content", "content_sha256"},
+		},
+	}, testAccCheckLookupFileDestroy)
+}
+
+func testAccCheckLookupFileDestroy(s *terraform.State) error {
+	conn := testAccProviders["humio"].Meta().(*providerMeta).client
+
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "humio_lookup_file" {
+			continue
+		}
+		parts := parseRepositoryAndID(rs.Primary.ID)
+		resp, err := newFiles(conn).Get(parts[0], parts[1])
+		if err == nil {
+			return fmt.Errorf("lookup file still exists: %#+v", resp)
+		}
+	}
+	return nil
+}
+
+const lookupFileEmpty = `
+resource "humio_lookup_file" "test" {}
+`
+
+const lookupFileInline = `
+resource "humio_lookup_file" "test" {
+	repository = "sandbox"
+	name       = "lookup-file-test.csv"
+	content    = "host,owner\nweb-1,web\n"
+}
+`
+
+const lookupFileSource = `
+resource "humio_lookup_file" "test" {
+	repository = "sandbox"
+	name       = "lookup-file-test.csv"
+	source     = %q
+}
+`
+
+func TestLookupFileContent(t *testing.T) {
+	source := filepath.Join(t.TempDir(), "hosts.csv")
+	err := os.WriteFile(source, []byte("from file"), 0o600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	content, err := lookupFileContent(source, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(content) != "from file" {
+		t.Errorf("got content %q from source, want %q", content, "from file")
+	}
+
+	content, err = lookupFileContent("", "inline")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(content) != "inline" {
+		t.Errorf("got content %q, want %q", content, "inline")
+	}
+
+	_, err = lookupFileContent(filepath.Join(t.TempDir(), "missing.csv"), "")
+	if err == nil {
+		t.Error("expected an error reading a missing source file")
+	}
+
+	want := "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+	if got := lookupFileHash(nil); got != want {
+		t.Errorf("got hash %s of empty content, want %s", got, want)
+	}
+}
+
+func TestLookupFileDiffHashesContent(t *testing.T) {
+	r := resourceLookupFile()
+	block := schema.InternalMap(r.Schema).CoreConfigSchema()
+	diff := func(content cty.Value) *terraform.InstanceDiff {
+		t.Helper()
+		values := map[string]cty.Value{}
+		for name, attributeType := range block.ImpliedType().AttributeTypes() {
+			values[name] = cty.NullVal(attributeType)
+		}
+		values["repository"] = cty.StringVal("sandbox")
+		values["name"] = cty.StringVal("hosts.csv")
+		values["content"] = content
+		d, err := r.Diff(context.Background(), nil, terraform.NewResourceConfigShimmed(cty.ObjectVal(values), block), nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return d
+	}
+
+	known := diff(cty.StringVal("inline"))
+	if got, want := known.Attributes["content_sha256"].New, lookupFileHash([]byte("inline")); got != want {
+		t.Errorf("got content_sha256 %q, want %q", got, want)
+	}
+
+	// Content from a resource that is not created yet, such as a local_file, is unknown when planning.
+	unknown := diff(cty.UnknownVal(cty.String))
+	for _, attribute := range []string{"content_sha256", "content_hash"} {
+		if !unknown.Attributes[attribute].NewComputed {
+			t.Errorf("expected %s to be computed for unknown content, got %+v", attribute, unknown.Attributes[attribute])
+		}
+	}
+}
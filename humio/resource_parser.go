@@ -17,6 +17,7 @@ package humio
 import (
 	"context"
 	"fmt"
+	"log"
 	"reflect"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
@@ -34,6 +35,7 @@ func resourceParser() *schema.Resource {
 		Importer: &schema.ResourceImporter{
 			StateContext: schema.ImportStatePassthroughContext,
 		},
+		CustomizeDiff: validateQueryDiff("parser_script"),
 
 		Schema: map[string]*schema.Schema{
 			"repository": {
@@ -69,7 +71,7 @@ func resourceParserCreate(ctx context.Context, d *schema.ResourceData, client in
 		return diag.Errorf("could not obtain parser from resource data: %s", err)
 	}
 
-	err = client.(*humio.Client).Parsers().Add(
+	err = client.(*providerMeta).client.Parsers().Add(
 		d.Get("repository").(string),
 		&parser,
 		false,
@@ -100,10 +102,15 @@ func resourceParserRead(_ context.Context, d *schema.ResourceData, client interf
 		}
 	}
 
-	parser, err := client.(*humio.Client).Parsers().Get(
+	parser, err := client.(*providerMeta).client.Parsers().Get(
 		d.Get("repository").(string),
 		d.Get("name").(string),
 	)
+	if isNotFound(err) {
+		log.Printf("[WARN] parser %s not found, removing from state", d.Id())
+		d.SetId("")
+		return nil
+	}
 	if err != nil || reflect.DeepEqual(*parser, humio.Parser{Tests: []string{}}) {
 		return diag.Errorf("could not get parser: %s", err)
 	}
@@ -140,7 +147,7 @@ func resourceParserUpdate(ctx context.Context, d *schema.ResourceData, client in
 		return diag.Errorf("could not obtain parser from resource data: %s", err)
 	}
 
-	err = client.(*humio.Client).Parsers().Add(
+	err = client.(*providerMeta).client.Parsers().Add(
 		d.Get("repository").(string),
 		&parser,
 		true,
@@ -166,7 +173,7 @@ func resourceParserDelete(_ context.Context, d *schema.ResourceData, client inte
 		return diag.Errorf("could not obtain parser from resource data: %s", err)
 	}
 
-	err = client.(*humio.Client).Parsers().Remove(
+	err = client.(*providerMeta).client.Parsers().Remove(
 		d.Get("repository").(string),
 		parser.Name,
 	)
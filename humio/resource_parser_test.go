@@ -126,7 +126,7 @@ func TestAccParserFull(t *testing.T) {
 }
 
 func testAccCheckParserDestroy(s *terraform.State) error {
-	conn := testAccProviders["humio"].Meta().(*humio.Client)
+	conn := testAccProviders["humio"].Meta().(*providerMeta).client
 
 	for _, rs := range s.RootModule().Resources {
 		if rs.Type != "humio_parser" {
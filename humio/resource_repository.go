@@ -2,6 +2,7 @@ package humio
 
 import (
 	"context"
+	"log"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
@@ -47,6 +48,16 @@ func resourceRepository() *schema.Resource {
 							Optional:         true,
 							ValidateDiagFunc: validation.ToDiagFunc(validation.FloatBetween(1, 365)),
 						},
+						"ingest_size_in_gb": {
+							Type:             schema.TypeFloat,
+							Optional:         true,
+							ValidateDiagFunc: validation.ToDiagFunc(validation.FloatAtLeast(0)),
+						},
+						"storage_size_in_gb": {
+							Type:             schema.TypeFloat,
+							Optional:         true,
+							ValidateDiagFunc: validation.ToDiagFunc(validation.FloatAtLeast(0)),
+						},
 					},
 				},
 			},
@@ -60,14 +71,14 @@ func resourceRepositoryCreate(ctx context.Context, d *schema.ResourceData, clien
 		return diag.Errorf("could not obtain repository from resource data: %s", err)
 	}
 
-	err = client.(*humio.Client).Repositories().Create(
+	err = client.(*providerMeta).client.Repositories().Create(
 		repository.Name,
 	)
 	if err != nil {
 		return diag.Errorf("could not create repository: %s", err)
 	}
 
-	err = client.(*humio.Client).Repositories().UpdateDescription(
+	err = client.(*providerMeta).client.Repositories().UpdateDescription(
 		repository.Name,
 		repository.Description,
 	)
@@ -75,13 +86,9 @@ func resourceRepositoryCreate(ctx context.Context, d *schema.ResourceData, clien
 		return diag.Errorf("could not set description for repository: %s", err)
 	}
 
-	err = client.(*humio.Client).Repositories().UpdateTimeBasedRetention(
-		repository.Name,
-		repository.RetentionDays,
-		d.Get("allow_data_deletion").(bool),
-	)
-	if err != nil {
-		return diag.Errorf("could not set time based retention for repository: %s", err)
+	diags := updateRepositoryRetention(client.(*providerMeta).client, repository, d.Get("allow_data_deletion").(bool))
+	if diags.HasError() {
+		return diags
 	}
 
 	d.SetId(repository.Name)
@@ -90,9 +97,17 @@ func resourceRepositoryCreate(ctx context.Context, d *schema.ResourceData, clien
 }
 
 func resourceRepositoryRead(_ context.Context, d *schema.ResourceData, client interface{}) diag.Diagnostics {
-	repo, err := client.(*humio.Client).Repositories().Get(d.Id())
+	repo, err := client.(*providerMeta).client.Repositories().Get(d.Id())
+	if err != nil {
+		err = searchDomainNotFoundOr(client.(*providerMeta).client, d.Id(), err)
+	}
+	if isNotFound(err) {
+		log.Printf("[WARN] repository %s not found, removing from state", d.Id())
+		d.SetId("")
+		return nil
+	}
 	if err != nil {
-		diag.Errorf("could not get repository: %s", err)
+		return diag.Errorf("could not get repository: %s", err)
 	}
 	return resourceDataFromRepository(&repo, d)
 }
@@ -115,29 +130,56 @@ func resourceDataFromRepository(a *humio.Repository, d *schema.ResourceData) dia
 func retentionFromRepository(a *humio.Repository) []tfMap {
 	s := tfMap{}
 	s["time_in_days"] = a.RetentionDays
+	s["ingest_size_in_gb"] = a.IngestRetentionSizeGB
+	s["storage_size_in_gb"] = a.StorageRetentionSizeGB
 	return []tfMap{s}
 }
 
+// updateRepositoryRetention sets all retention limits of the repository. A limit of 0 removes the limit.
+func updateRepositoryRetention(client *humio.Client, repository humio.Repository, allowDataDeletion bool) diag.Diagnostics {
+	err := client.Repositories().UpdateTimeBasedRetention(
+		repository.Name,
+		repository.RetentionDays,
+		allowDataDeletion,
+	)
+	if err != nil {
+		return diag.Errorf("could not set time based retention for repository: %s", err)
+	}
+	err = client.Repositories().UpdateIngestBasedRetention(
+		repository.Name,
+		repository.IngestRetentionSizeGB,
+		allowDataDeletion,
+	)
+	if err != nil {
+		return diag.Errorf("could not set ingest based retention for repository: %s", err)
+	}
+	err = client.Repositories().UpdateStorageBasedRetention(
+		repository.Name,
+		repository.StorageRetentionSizeGB,
+		allowDataDeletion,
+	)
+	if err != nil {
+		return diag.Errorf("could not set storage based retention for repository: %s", err)
+	}
+	return nil
+}
+
 func resourceRepositoryUpdate(ctx context.Context, d *schema.ResourceData, client interface{}) diag.Diagnostics {
 	repository, err := repositoryFromResourceData(d)
 	if err != nil {
 		return diag.Errorf("could not obtain repository from resource data: %s", err)
 	}
 
-	err = client.(*humio.Client).Repositories().UpdateDescription(
+	err = client.(*providerMeta).client.Repositories().UpdateDescription(
 		repository.Name,
 		repository.Description,
 	)
 	if err != nil {
 		return diag.Errorf("could not update description for repository: %s", err)
 	}
-	err = client.(*humio.Client).Repositories().UpdateTimeBasedRetention(
-		repository.Name,
-		repository.RetentionDays,
-		d.Get("allow_data_deletion").(bool),
-	)
-	if err != nil {
-		return diag.Errorf("could not update time based retention for repository: %s", err)
+	diags := updateRepositoryRetention(client.(*providerMeta).client, repository, d.Get("allow_data_deletion").(bool))
+	if diags.HasError() {
+		return diags
 	}
 
 	return resourceRepositoryRead(ctx, d, client)
@@ -150,7 +192,7 @@ func resourceRepositoryDelete(_ context.Context, d *schema.ResourceData, client
 	}
 
 	deleteReason := "Deleted by Terraform"
-	err = client.(*humio.Client).Repositories().Delete(
+	err = client.(*providerMeta).client.Repositories().Delete(
 		repository.Name,
 		deleteReason,
 		d.Get("allow_data_deletion").(bool),
@@ -162,14 +204,21 @@ func resourceRepositoryDelete(_ context.Context, d *schema.ResourceData, client
 }
 
 func repositoryFromResourceData(d *schema.ResourceData) (humio.Repository, error) {
-	var retentionDays float64
+	var retentionDays, ingestSizeInGB, storageSizeInGB float64
 	if rawRetention, ok := d.GetOk("retention"); ok {
-		retentionDays = rawRetention.(*schema.Set).List()[0].(tfMap)["time_in_days"].(float64)
+		// An empty retention block may be read back as nil rather than a map of zero values.
+		if retention, ok := rawRetention.(*schema.Set).List()[0].(tfMap); ok {
+			retentionDays = retention["time_in_days"].(float64)
+			ingestSizeInGB = retention["ingest_size_in_gb"].(float64)
+			storageSizeInGB = retention["storage_size_in_gb"].(float64)
+		}
 	}
 
 	return humio.Repository{
 		Name:                   d.Get("name").(string),
 		Description:            d.Get("description").(string),
 		RetentionDays:          retentionDays,
+		IngestRetentionSizeGB:  ingestSizeInGB,
+		StorageRetentionSizeGB: storageSizeInGB,
 	}, nil
 }
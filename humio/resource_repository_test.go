@@ -56,7 +56,7 @@ func TestAccRepositoryInvalidInputs(t *testing.T) {
 func TestAccRepositoryInvalidRetentionSettings(t *testing.T) {
 	config := repositoryInvalidRetentionSettings
 	accTestCase(t, []resource.TestStep{
-		{Config: config, ExpectError: regexp.MustCompile(`Name:time_in_days}] to be at least \(0\.000000\), got -30\.000000`)},
+		{Config: config, ExpectError: regexp.MustCompile(`Name:time_in_days}] to be in the range \(1\.000000 - 365\.000000\), got -30\.000000`)},
 		{Config: config, ExpectError: regexp.MustCompile(`Name:ingest_size_in_gb}] to be at least \(0\.000000\), got -10\.000000`)},
 		{Config: config, ExpectError: regexp.MustCompile(`Name:storage_size_in_gb}] to be at least \(0\.000000\), got -5\.000000`)},
 		//{Config: config, ExpectError: regexp.MustCompile(`Inappropriate value for attribute "retention"`)},
@@ -152,8 +152,30 @@ func TestAccRepositoryFull(t *testing.T) {
 	}, testAccCheckRepositoryDestroy)
 }
 
+func TestAccRepositoryClearRetentionLimits(t *testing.T) {
+	accTestCase(t, []resource.TestStep{
+		{
+			Config: repositoryFull,
+			Check: resource.ComposeTestCheckFunc(
+				resource.TestCheckResourceAttr("humio_repository.test", "retention.0.time_in_days", "30"),
+				resource.TestCheckResourceAttr("humio_repository.test", "retention.0.ingest_size_in_gb", "10"),
+				resource.TestCheckResourceAttr("humio_repository.test", "retention.0.storage_size_in_gb", "5"),
+			),
+		},
+		{
+			Config: repositoryTimeBasedRetentionOnly,
+			Check: resource.ComposeTestCheckFunc(
+				resource.TestCheckResourceAttr("humio_repository.test", "retention.#", "1"),
+				resource.TestCheckResourceAttr("humio_repository.test", "retention.0.time_in_days", "30"),
+				resource.TestCheckResourceAttr("humio_repository.test", "retention.0.ingest_size_in_gb", "0"),
+				resource.TestCheckResourceAttr("humio_repository.test", "retention.0.storage_size_in_gb", "0"),
+			),
+		},
+	}, testAccCheckRepositoryDestroy)
+}
+
 func testAccCheckRepositoryDestroy(s *terraform.State) error {
-	conn := testAccProviders["humio"].Meta().(*humio.Client)
+	conn := testAccProviders["humio"].Meta().(*providerMeta).client
 
 	for _, rs := range s.RootModule().Resources {
 		if rs.Type != "humio_repository" {
@@ -214,6 +236,17 @@ resource "humio_repository" "test" {
 }
 `
 
+const repositoryTimeBasedRetentionOnly = `
+resource "humio_repository" "test" {
+    name                = "repository-test"
+    description         = "some description"
+    allow_data_deletion = true
+    retention {
+        time_in_days = 30
+    }
+}
+`
+
 var wantRepository = humio.Repository{
 	Name:                   "test-repository",
 	Description:            "important",
@@ -0,0 +1,155 @@
+// Copyright © 2020 Humio Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package humio
+
+import (
+	"context"
+	"log"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func resourceRole() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceRoleCreate,
+		ReadContext:   resourceRoleRead,
+		UpdateContext: resourceRoleUpdate,
+		DeleteContext: resourceRoleDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"role_id": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"name": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"description": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			// Permissions are validated by LogScale, as the set of permissions changes between versions.
+			"view_permissions": {
+				Type:     schema.TypeSet,
+				Optional: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+			"organization_permissions": {
+				Type:     schema.TypeSet,
+				Optional: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+			"system_permissions": {
+				Type:     schema.TypeSet,
+				Optional: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+		},
+	}
+}
+
+func resourceRoleCreate(ctx context.Context, d *schema.ResourceData, client interface{}) diag.Diagnostics {
+	role, err := roleFromResourceData(d)
+	if err != nil {
+		return diag.Errorf("could not obtain role from resource data: %s", err)
+	}
+
+	created, err := newRoles(client.(*providerMeta).client).Add(&role)
+	if err != nil {
+		return diag.Errorf("could not create role: %s", err)
+	}
+	d.SetId(created.ID)
+
+	return resourceRoleRead(ctx, d, client)
+}
+
+func resourceRoleRead(_ context.Context, d *schema.ResourceData, client interface{}) diag.Diagnostics {
+	role, err := newRoles(client.(*providerMeta).client).Get(d.Id())
+	if isNotFound(err) {
+		log.Printf("[WARN] role %s not found, removing from state", d.Id())
+		d.SetId("")
+		return nil
+	}
+	if err != nil {
+		return diag.Errorf("could not get role: %s", err)
+	}
+	return resourceDataFromRole(role, d)
+}
+
+func resourceDataFromRole(r *Role, d *schema.ResourceData) diag.Diagnostics {
+	err := d.Set("role_id", r.ID)
+	if err != nil {
+		return diag.Errorf("error setting role_id for resource %s: %s", d.Id(), err)
+	}
+	err = d.Set("name", r.DisplayName)
+	if err != nil {
+		return diag.Errorf("error setting name for resource %s: %s", d.Id(), err)
+	}
+	err = d.Set("description", r.Description)
+	if err != nil {
+		return diag.Errorf("error setting description for resource %s: %s", d.Id(), err)
+	}
+	err = d.Set("view_permissions", r.ViewPermissions)
+	if err != nil {
+		return diag.Errorf("error setting view_permissions for resource %s: %s", d.Id(), err)
+	}
+	err = d.Set("organization_permissions", r.OrganizationPermissions)
+	if err != nil {
+		return diag.Errorf("error setting organization_permissions for resource %s: %s", d.Id(), err)
+	}
+	err = d.Set("system_permissions", r.SystemPermissions)
+	if err != nil {
+		return diag.Errorf("error setting system_permissions for resource %s: %s", d.Id(), err)
+	}
+	return nil
+}
+
+func resourceRoleUpdate(ctx context.Context, d *schema.ResourceData, client interface{}) diag.Diagnostics {
+	role, err := roleFromResourceData(d)
+	if err != nil {
+		return diag.Errorf("could not obtain role from resource data: %s", err)
+	}
+
+	_, err = newRoles(client.(*providerMeta).client).Update(&role)
+	if err != nil {
+		return diag.Errorf("could not update role: %s", err)
+	}
+
+	return resourceRoleRead(ctx, d, client)
+}
+
+func resourceRoleDelete(_ context.Context, d *schema.ResourceData, client interface{}) diag.Diagnostics {
+	err := newRoles(client.(*providerMeta).client).Delete(d.Id())
+	if err != nil {
+		return diag.Errorf("could not delete role: %s", err)
+	}
+	return nil
+}
+
+func roleFromResourceData(d *schema.ResourceData) (Role, error) {
+	return Role{
+		ID:                      d.Id(),
+		DisplayName:             d.Get("name").(string),
+		Description:             d.Get("description").(string),
+		ViewPermissions:         convertInterfaceListToStringSlice(d.Get("view_permissions").(*schema.Set).List()),
+		OrganizationPermissions: convertInterfaceListToStringSlice(d.Get("organization_permissions").(*schema.Set).List()),
+		SystemPermissions:       convertInterfaceListToStringSlice(d.Get("system_permissions").(*schema.Set).List()),
+	}, nil
+}
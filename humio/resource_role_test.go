@@ -0,0 +1,147 @@
+// Copyright © 2020 Humio Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package humio
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccRoleRequiredFields(t *testing.T) {
+	accTestCase(t, []resource.TestStep{
+		{Config: roleEmpty, ExpectError: regexp.MustCompile(`The argument "name" is required, but no definition was found.`)},
+	}, nil)
+}
+
+func TestAccRoleInvalidInputs(t *testing.T) {
+	config := roleInvalidInputs
+	accTestCase(t, []resource.TestStep{
+		{Config: config, ExpectError: regexp.MustCompile(`Inappropriate value for attribute "view_permissions"`)},
+		{Config: config, ExpectError: regexp.MustCompile(`Inappropriate value for attribute "organization_permissions"`)},
+		{Config: config, ExpectError: regexp.MustCompile(`Inappropriate value for attribute "system_permissions"`)},
+	}, nil)
+}
+
+func TestAccRoleBasicToFull(t *testing.T) {
+	accTestCase(t, []resource.TestStep{
+		{
+			Config: roleBasic,
+			Check: resource.ComposeTestCheckFunc(
+				resource.TestCheckResourceAttrSet("humio_role.test", "role_id"),
+				resource.TestCheckResourceAttr("humio_role.test", "name", "role-test"),
+				resource.TestCheckResourceAttr("humio_role.test", "view_permissions.#", "1"),
+				resource.TestCheckTypeSetElemAttr("humio_role.test", "view_permissions.*", "ReadAccess"),
+				resource.TestCheckResourceAttr("humio_role.test", "organization_permissions.#", "0"),
+				resource.TestCheckResourceAttr("humio_role.test", "system_permissions.#", "0"),
+			),
+		},
+		{
+			Config: roleFull,
+			Check: resource.ComposeTestCheckFunc(
+				resource.TestCheckResourceAttr("humio_role.test", "name", "role-test"),
+				resource.TestCheckResourceAttr("humio_role.test", "description", "read and manage alerts"),
+				resource.TestCheckResourceAttr("humio_role.test", "view_permissions.#", "2"),
+				resource.TestCheckTypeSetElemAttr("humio_role.test", "view_permissions.*", "ReadAccess"),
+				resource.TestCheckTypeSetElemAttr("humio_role.test", "view_permissions.*", "ChangeTriggers"),
+				resource.TestCheckResourceAttr("humio_role.test", "organization_permissions.#", "1"),
+				resource.TestCheckTypeSetElemAttr("humio_role.test", "organization_permissions.*", "CreateRepository"),
+				resource.TestCheckResourceAttr("humio_role.test", "system_permissions.#", "1"),
+				resource.TestCheckTypeSetElemAttr("humio_role.test", "system_permissions.*", "ReadHealthCheck"),
+			),
+		},
+		{
+			ResourceName:      "humio_role.test",
+			ImportState:       true,
+			ImportStateVerify: true,
+		},
+	}, testAccCheckRoleDestroy)
+}
+
+func testAccCheckRoleDestroy(s *terraform.State) error {
+	conn := testAccProviders["humio"].Meta().(*providerMeta).client
+
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "humio_role" {
+			continue
+		}
+		resp, err := newRoles(conn).Get(rs.Primary.ID)
+		if err == nil {
+			return fmt.Errorf("role still exists: %#+v", resp)
+		}
+	}
+	return nil
+}
+
+const roleEmpty = `
+resource "humio_role" "test" {}
+`
+
+const roleInvalidInputs = `
+resource "humio_role" "test" {
+	name                     = "invalid"
+	view_permissions         = "invalid"
+	organization_permissions = "invalid"
+	system_permissions       = "invalid"
+}
+`
+
+const roleBasic = `
+resource "humio_role" "test" {
+	name             = "role-test"
+	view_permissions = ["ReadAccess"]
+}
+`
+
+const roleFull = `
+resource "humio_role" "test" {
+	name                     = "role-test"
+	description              = "read and manage alerts"
+	view_permissions         = ["ReadAccess", "ChangeTriggers"]
+	organization_permissions = ["CreateRepository"]
+	system_permissions       = ["ReadHealthCheck"]
+}
+`
+
+var wantRole = Role{
+	ID:                      "",
+	DisplayName:             "alert managers",
+	Description:             "read and manage alerts",
+	ViewPermissions:         []string{"ChangeTriggers", "ReadAccess"},
+	OrganizationPermissions: []string{"CreateRepository"},
+	SystemPermissions:       []string{"ReadHealthCheck"},
+}
+
+func TestEncodeDecodeRoleResource(t *testing.T) {
+	res := resourceRole()
+	data := res.TestResourceData()
+	resourceDataFromRole(&wantRole, data)
+	got, err := roleFromResourceData(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Permissions are stored as sets, so their order is not preserved.
+	sortStrings := cmpopts.SortSlices(func(a, b string) bool { return a < b })
+	if !cmp.Equal(wantRole, got, sortStrings) {
+		t.Error(cmp.Diff(wantRole, got, sortStrings))
+	}
+}
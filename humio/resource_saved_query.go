@@ -0,0 +1,221 @@
+// Copyright © 2020 Humio Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package humio
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/structure"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+func resourceSavedQuery() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceSavedQueryCreate,
+		ReadContext:   resourceSavedQueryRead,
+		UpdateContext: resourceSavedQueryUpdate,
+		DeleteContext: resourceSavedQueryDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+		CustomizeDiff: validateQueryDiff("query"),
+
+		Schema: map[string]*schema.Schema{
+			"saved_query_id": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"repository": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			// Saved queries are referenced by ID, so they can be renamed without breaking dashboards using them.
+			"name": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"query": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"start": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Default:  "24h",
+			},
+			"end": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Default:  "now",
+			},
+			"is_live": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+			"widget_type": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+			},
+			// LogScale does not return the visualization options in a form we can read back, so changes made
+			// outside of Terraform are not detected.
+			"options": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				ValidateDiagFunc: validation.ToDiagFunc(validation.StringIsJSON),
+				DiffSuppressFunc: structure.SuppressJsonDiff,
+			},
+			"labels": {
+				Type:     schema.TypeList,
+				Optional: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+		},
+	}
+}
+
+func resourceSavedQueryCreate(ctx context.Context, d *schema.ResourceData, client interface{}) diag.Diagnostics {
+	savedQuery, err := savedQueryFromResourceData(d)
+	if err != nil {
+		return diag.Errorf("could not obtain saved query from resource data: %s", err)
+	}
+
+	created, err := client.(*providerMeta).savedQueries().Add(
+		d.Get("repository").(string),
+		&savedQuery,
+	)
+	if err != nil {
+		return diag.Errorf("could not create saved query: %s", err)
+	}
+	d.SetId(fmt.Sprintf("%s+%s", d.Get("repository"), created.ID))
+
+	return resourceSavedQueryRead(ctx, d, client)
+}
+
+func resourceSavedQueryRead(_ context.Context, d *schema.ResourceData, client interface{}) diag.Diagnostics {
+	parts := parseRepositoryAndID(d.Id())
+	//we check that we have parsed the id into the correct number of segments
+	if parts[0] == "" || parts[1] == "" {
+		return diag.Errorf("error importing humio_saved_query. Please make sure the ID is in the form REPOSITORYNAME+SAVEDQUERYID or REPOSITORYNAME+SAVEDQUERYNAME (i.e. myRepoName+mySavedQueryName")
+	}
+	// If we don't have a repository when importing, we take it from the ID.
+	if _, ok := d.GetOk("repository"); !ok {
+		err := d.Set("repository", parts[0])
+		if err != nil {
+			return diag.Errorf("error setting repository for resource %s: %s", d.Id(), err)
+		}
+	}
+
+	savedQuery, err := client.(*providerMeta).savedQueries().Get(
+		d.Get("repository").(string),
+		parts[1],
+	)
+	if isNotFound(err) {
+		log.Printf("[WARN] saved query %s not found, removing from state", d.Id())
+		d.SetId("")
+		return nil
+	}
+	if err != nil {
+		return diag.Errorf("could not get saved query: %s", err)
+	}
+	// Imports may refer to the saved query by name, but the ID always uses the saved query ID.
+	d.SetId(fmt.Sprintf("%s+%s", d.Get("repository"), savedQuery.ID))
+
+	return resourceDataFromSavedQuery(savedQuery, d)
+}
+
+func resourceDataFromSavedQuery(s *SavedQuery, d *schema.ResourceData) diag.Diagnostics {
+	err := d.Set("saved_query_id", s.ID)
+	if err != nil {
+		return diag.Errorf("error setting saved_query_id for resource %s: %s", d.Id(), err)
+	}
+	err = d.Set("name", s.Name)
+	if err != nil {
+		return diag.Errorf("error setting name for resource %s: %s", d.Id(), err)
+	}
+	err = d.Set("query", s.QueryString)
+	if err != nil {
+		return diag.Errorf("error setting query for resource %s: %s", d.Id(), err)
+	}
+	err = d.Set("start", s.Start)
+	if err != nil {
+		return diag.Errorf("error setting start for resource %s: %s", d.Id(), err)
+	}
+	err = d.Set("end", s.End)
+	if err != nil {
+		return diag.Errorf("error setting end for resource %s: %s", d.Id(), err)
+	}
+	err = d.Set("is_live", s.IsLive)
+	if err != nil {
+		return diag.Errorf("error setting is_live for resource %s: %s", d.Id(), err)
+	}
+	err = d.Set("widget_type", s.WidgetType)
+	if err != nil {
+		return diag.Errorf("error setting widget_type for resource %s: %s", d.Id(), err)
+	}
+	err = d.Set("labels", s.Labels)
+	if err != nil {
+		return diag.Errorf("error setting labels for resource %s: %s", d.Id(), err)
+	}
+	return nil
+}
+
+func resourceSavedQueryUpdate(ctx context.Context, d *schema.ResourceData, client interface{}) diag.Diagnostics {
+	savedQuery, err := savedQueryFromResourceData(d)
+	if err != nil {
+		return diag.Errorf("could not obtain saved query from resource data: %s", err)
+	}
+
+	_, err = client.(*providerMeta).savedQueries().Update(
+		d.Get("repository").(string),
+		&savedQuery,
+	)
+	if err != nil {
+		return diag.Errorf("could not update saved query: %s", err)
+	}
+
+	return resourceSavedQueryRead(ctx, d, client)
+}
+
+func resourceSavedQueryDelete(_ context.Context, d *schema.ResourceData, client interface{}) diag.Diagnostics {
+	err := client.(*providerMeta).savedQueries().Delete(
+		d.Get("repository").(string),
+		d.Get("saved_query_id").(string),
+	)
+	if err != nil {
+		return diag.Errorf("could not delete saved query: %s", err)
+	}
+	return nil
+}
+
+func savedQueryFromResourceData(d *schema.ResourceData) (SavedQuery, error) {
+	return SavedQuery{
+		ID:          d.Get("saved_query_id").(string),
+		Name:        d.Get("name").(string),
+		QueryString: d.Get("query").(string),
+		Start:       d.Get("start").(string),
+		End:         d.Get("end").(string),
+		IsLive:      d.Get("is_live").(bool),
+		WidgetType:  d.Get("widget_type").(string),
+		Options:     d.Get("options").(string),
+		Labels:      convertInterfaceListToStringSlice(d.Get("labels").([]interface{})),
+	}, nil
+}
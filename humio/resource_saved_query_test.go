@@ -0,0 +1,160 @@
+// Copyright © 2020 Humio Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package humio
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccSavedQueryRequiredFields(t *testing.T) {
+	accTestCase(t, []resource.TestStep{
+		{Config: savedQueryEmpty, ExpectError: regexp.MustCompile(`The argument "repository" is required, but no definition was found.`)},
+		{Config: savedQueryEmpty, ExpectError: regexp.MustCompile(`The argument "name" is required, but no definition was found.`)},
+		{Config: savedQueryEmpty, ExpectError: regexp.MustCompile(`The argument "query" is required, but no definition was found.`)},
+	}, nil)
+}
+
+func TestAccSavedQueryInvalidInputs(t *testing.T) {
+	accTestCase(t, []resource.TestStep{
+		{Config: savedQueryInvalidOptions, ExpectError: regexp.MustCompile(`"options" contains an invalid JSON`)},
+	}, nil)
+}
+
+func TestAccSavedQueryBasicToRenamed(t *testing.T) {
+	var id string
+	accTestCase(t, []resource.TestStep{
+		{
+			Config: savedQueryBasic,
+			Check: resource.ComposeTestCheckFunc(
+				resource.TestCheckResourceAttrSet("humio_saved_query.test", "saved_query_id"),
+				resource.TestCheckResourceAttr("humio_saved_query.test", "repository", "sandbox"),
+				resource.TestCheckResourceAttr("humio_saved_query.test", "name", "saved-query-test"),
+				resource.TestCheckResourceAttr("humio_saved_query.test", "query", "count()"),
+				resource.TestCheckResourceAttr("humio_saved_query.test", "start", "24h"),
+				resource.TestCheckResourceAttr("humio_saved_query.test", "end", "now"),
+				resource.TestCheckResourceAttr("humio_saved_query.test", "is_live", "false"),
+				testAccCaptureAttr("humio_saved_query.test", "saved_query_id", &id),
+			),
+		},
+		{
+			Config: savedQueryFull,
+			Check: resource.ComposeTestCheckFunc(
+				resource.TestCheckResourceAttr("humio_saved_query.test", "name", "saved-query-test-renamed"),
+				resource.TestCheckResourceAttr("humio_saved_query.test", "query", "groupBy(#type)"),
+				resource.TestCheckResourceAttr("humio_saved_query.test", "start", "7d"),
+				resource.TestCheckResourceAttr("humio_saved_query.test", "is_live", "true"),
+				resource.TestCheckResourceAttr("humio_saved_query.test", "widget_type", "pie-chart"),
+				resource.TestCheckResourceAttr("humio_saved_query.test", "labels.#", "2"),
+				resource.TestCheckResourceAttrPtr("humio_saved_query.test", "saved_query_id", &id),
+			),
+		},
+		{
+			ResourceName:            "humio_saved_query.test",
+			ImportState:             true,
+			ImportStateVerify:       true,
+			ImportStateVerifyIgnore: []string{"options"},
+		},
+		{
+			ResourceName:            "humio_saved_query.test",
+			ImportState:             true,
+			ImportStateId:           "sandbox+saved-query-test-renamed",
+			ImportStateVerify:       true,
+			ImportStateVerifyIgnore: []string{"options"},
+		},
+	}, testAccCheckSavedQueryDestroy)
+}
+
+func testAccCheckSavedQueryDestroy(s *terraform.State) error {
+	conn := testAccProviders["humio"].Meta().(*providerMeta).client
+
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "humio_saved_query" {
+			continue
+		}
+		parts := parseRepositoryAndID(rs.Primary.ID)
+		resp, err := newSavedQueries(conn).Get(parts[0], parts[1])
+		if err == nil {
+			return fmt.Errorf("saved query still exists: %#+v", resp)
+		}
+	}
+	return nil
+}
+
+const savedQueryEmpty = `
+resource "humio_saved_query" "test" {}
+`
+
+const savedQueryInvalidOptions = `
+resource "humio_saved_query" "test" {
+	repository = "sandbox"
+	name       = "saved-query-test"
+	query      = "count()"
+	options    = "{invalid"
+}
+`
+
+const savedQueryBasic = `
+resource "humio_saved_query" "test" {
+	repository = "sandbox"
+	name       = "saved-query-test"
+	query      = "count()"
+}
+`
+
+const savedQueryFull = `
+resource "humio_saved_query" "test" {
+	repository  = "sandbox"
+	name        = "saved-query-test-renamed"
+	query       = "groupBy(#type)"
+	start       = "7d"
+	is_live     = true
+	widget_type = "pie-chart"
+	options     = jsonencode({ "series" = {} })
+	labels      = ["ops", "overview"]
+}
+`
+
+var wantSavedQuery = SavedQuery{
+	ID:          "",
+	Name:        "errors by host",
+	QueryString: "loglevel=ERROR | groupBy(host)",
+	Start:       "7d",
+	End:         "now",
+	IsLive:      true,
+	WidgetType:  "bar-chart",
+	Options:     "",
+	Labels:      []string{"ops", "errors"},
+}
+
+func TestEncodeDecodeSavedQueryResource(t *testing.T) {
+	res := resourceSavedQuery()
+	data := res.TestResourceData()
+	resourceDataFromSavedQuery(&wantSavedQuery, data)
+	got, err := savedQueryFromResourceData(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !cmp.Equal(wantSavedQuery, got) {
+		t.Error(cmp.Diff(wantSavedQuery, got))
+	}
+}
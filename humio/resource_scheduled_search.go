@@ -0,0 +1,288 @@
+// Copyright © 2020 Humio Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package humio
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+func resourceScheduledSearch() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceScheduledSearchCreate,
+		ReadContext:   resourceScheduledSearchRead,
+		UpdateContext: resourceScheduledSearchUpdate,
+		DeleteContext: resourceScheduledSearchDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+		CustomizeDiff: validateQueryDiff("query"),
+
+		Schema: map[string]*schema.Schema{
+			"scheduled_search_id": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"repository": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"name": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"description": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"enabled": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+			"query": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"start": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"end": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Default:  "now",
+			},
+			"schedule": {
+				Type:             schema.TypeString,
+				Required:         true,
+				ValidateDiagFunc: validateCronExpression,
+			},
+			"time_zone": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Default:  "UTC",
+			},
+			"backfill_limit": {
+				Type:             schema.TypeInt,
+				Optional:         true,
+				Default:          0,
+				ValidateDiagFunc: validation.ToDiagFunc(validation.IntAtLeast(0)),
+			},
+			"actions": {
+				Type:     schema.TypeList,
+				Optional: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+			"labels": {
+				Type:     schema.TypeList,
+				Optional: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+			"run_as_user_id": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+			},
+			"query_ownership_type": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				Computed:         true,
+				ValidateDiagFunc: validateQueryOwnershipType,
+			},
+		},
+	}
+}
+
+// validateCronExpression only checks the overall shape of the expression; LogScale validates the fields themselves.
+func validateCronExpression(v interface{}, _ cty.Path) diag.Diagnostics {
+	value := v.(string)
+	if len(strings.Fields(value)) != 5 {
+		return diag.Errorf("schedule must be a cron expression with 5 fields (minute hour day-of-month month day-of-week), got: %q", value)
+	}
+	return nil
+}
+
+func resourceScheduledSearchCreate(ctx context.Context, d *schema.ResourceData, client interface{}) diag.Diagnostics {
+	scheduledSearch, err := scheduledSearchFromResourceData(d)
+	if err != nil {
+		return diag.Errorf("could not obtain scheduled search from resource data: %s", err)
+	}
+
+	_, err = client.(*providerMeta).scheduledSearches().Add(
+		d.Get("repository").(string),
+		&scheduledSearch,
+	)
+	if err != nil {
+		return diag.Errorf("could not create scheduled search: %s", err)
+	}
+	d.SetId(fmt.Sprintf("%s+%s", d.Get("repository"), d.Get("name")))
+
+	return resourceScheduledSearchRead(ctx, d, client)
+}
+
+func resourceScheduledSearchRead(_ context.Context, d *schema.ResourceData, client interface{}) diag.Diagnostics {
+	// If we don't have a repository when importing, we parse it from the ID.
+	if _, ok := d.GetOk("repository"); !ok {
+		parts := parseRepositoryAndID(d.Id())
+		//we check that we have parsed the id into the correct number of segments
+		if parts[0] == "" || parts[1] == "" {
+			return diag.Errorf("error importing humio_scheduled_search. Please make sure the ID is in the form REPOSITORYNAME+SCHEDULEDSEARCHNAME (i.e. myRepoName+myScheduledSearchName")
+		}
+		err := d.Set("repository", parts[0])
+		if err != nil {
+			return diag.Errorf("error setting repository for resource %s: %s", d.Id(), err)
+		}
+		err = d.Set("name", parts[1])
+		if err != nil {
+			return diag.Errorf("error setting name for resource %s: %s", d.Id(), err)
+		}
+	}
+
+	scheduledSearch, err := client.(*providerMeta).scheduledSearches().Get(
+		d.Get("repository").(string),
+		d.Get("name").(string),
+	)
+	if isNotFound(err) {
+		log.Printf("[WARN] scheduled search %s not found, removing from state", d.Id())
+		d.SetId("")
+		return nil
+	}
+	if err != nil {
+		return diag.Errorf("could not get scheduled search: %s", err)
+	}
+	return resourceDataFromScheduledSearch(scheduledSearch, d)
+}
+
+func resourceDataFromScheduledSearch(s *ScheduledSearch, d *schema.ResourceData) diag.Diagnostics {
+	err := d.Set("scheduled_search_id", s.ID)
+	if err != nil {
+		return diag.Errorf("error setting scheduled_search_id for resource %s: %s", d.Id(), err)
+	}
+	err = d.Set("name", s.Name)
+	if err != nil {
+		return diag.Errorf("error setting name for resource %s: %s", d.Id(), err)
+	}
+	err = d.Set("description", s.Description)
+	if err != nil {
+		return diag.Errorf("error setting description for resource %s: %s", d.Id(), err)
+	}
+	err = d.Set("enabled", s.Enabled)
+	if err != nil {
+		return diag.Errorf("error setting enabled for resource %s: %s", d.Id(), err)
+	}
+	err = d.Set("query", s.QueryString)
+	if err != nil {
+		return diag.Errorf("error setting query for resource %s: %s", d.Id(), err)
+	}
+	err = d.Set("start", s.QueryStart)
+	if err != nil {
+		return diag.Errorf("error setting start for resource %s: %s", d.Id(), err)
+	}
+	err = d.Set("end", s.QueryEnd)
+	if err != nil {
+		return diag.Errorf("error setting end for resource %s: %s", d.Id(), err)
+	}
+	err = d.Set("schedule", s.Schedule)
+	if err != nil {
+		return diag.Errorf("error setting schedule for resource %s: %s", d.Id(), err)
+	}
+	err = d.Set("time_zone", s.TimeZone)
+	if err != nil {
+		return diag.Errorf("error setting time_zone for resource %s: %s", d.Id(), err)
+	}
+	err = d.Set("backfill_limit", s.BackfillLimit)
+	if err != nil {
+		return diag.Errorf("error setting backfill_limit for resource %s: %s", d.Id(), err)
+	}
+	err = d.Set("actions", s.Actions)
+	if err != nil {
+		return diag.Errorf("error setting actions for resource %s: %s", d.Id(), err)
+	}
+	err = d.Set("labels", s.Labels)
+	if err != nil {
+		return diag.Errorf("error setting labels for resource %s: %s", d.Id(), err)
+	}
+	err = d.Set("run_as_user_id", s.RunAsUserID)
+	if err != nil {
+		return diag.Errorf("error setting run_as_user_id for resource %s: %s", d.Id(), err)
+	}
+	err = d.Set("query_ownership_type", s.QueryOwnershipType)
+	if err != nil {
+		return diag.Errorf("error setting query_ownership_type for resource %s: %s", d.Id(), err)
+	}
+	return nil
+}
+
+func resourceScheduledSearchUpdate(ctx context.Context, d *schema.ResourceData, client interface{}) diag.Diagnostics {
+	scheduledSearch, err := scheduledSearchFromResourceData(d)
+	if err != nil {
+		return diag.Errorf("could not obtain scheduled search from resource data: %s", err)
+	}
+
+	_, err = client.(*providerMeta).scheduledSearches().Update(
+		d.Get("repository").(string),
+		&scheduledSearch,
+	)
+	if err != nil {
+		return diag.Errorf("could not update scheduled search: %s", err)
+	}
+
+	return resourceScheduledSearchRead(ctx, d, client)
+}
+
+func resourceScheduledSearchDelete(_ context.Context, d *schema.ResourceData, client interface{}) diag.Diagnostics {
+	scheduledSearch, err := scheduledSearchFromResourceData(d)
+	if err != nil {
+		return diag.Errorf("could not obtain scheduled search from resource data: %s", err)
+	}
+
+	err = client.(*providerMeta).scheduledSearches().Delete(
+		d.Get("repository").(string),
+		scheduledSearch.Name,
+	)
+	if err != nil {
+		return diag.Errorf("could not delete scheduled search: %s", err)
+	}
+	return nil
+}
+
+func scheduledSearchFromResourceData(d *schema.ResourceData) (ScheduledSearch, error) {
+	return ScheduledSearch{
+		ID:                 d.Get("scheduled_search_id").(string),
+		Name:               d.Get("name").(string),
+		Description:        d.Get("description").(string),
+		QueryString:        d.Get("query").(string),
+		QueryStart:         d.Get("start").(string),
+		QueryEnd:           d.Get("end").(string),
+		TimeZone:           d.Get("time_zone").(string),
+		Schedule:           d.Get("schedule").(string),
+		BackfillLimit:      d.Get("backfill_limit").(int),
+		Enabled:            d.Get("enabled").(bool),
+		Actions:            convertInterfaceListToStringSlice(d.Get("actions").([]interface{})),
+		Labels:             convertInterfaceListToStringSlice(d.Get("labels").([]interface{})),
+		RunAsUserID:        d.Get("run_as_user_id").(string),
+		QueryOwnershipType: d.Get("query_ownership_type").(string),
+	}, nil
+}
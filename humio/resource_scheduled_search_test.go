@@ -0,0 +1,248 @@
+// Copyright © 2020 Humio Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package humio
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	humio "github.com/humio/cli/api"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccScheduledSearchRequiredFields(t *testing.T) {
+	config := scheduledSearchEmpty
+	accTestCase(t, []resource.TestStep{
+		{Config: config, ExpectError: regexp.MustCompile(`The argument "repository" is required, but no definition was found.`)},
+		{Config: config, ExpectError: regexp.MustCompile(`The argument "name" is required, but no definition was found.`)},
+		{Config: config, ExpectError: regexp.MustCompile(`The argument "query" is required, but no definition was found.`)},
+		{Config: config, ExpectError: regexp.MustCompile(`The argument "start" is required, but no definition was found.`)},
+		{Config: config, ExpectError: regexp.MustCompile(`The argument "schedule" is required, but no definition was found.`)},
+	}, nil)
+}
+
+func TestAccScheduledSearchInvalidInputs(t *testing.T) {
+	config := scheduledSearchInvalidInputs
+	accTestCase(t, []resource.TestStep{
+		{Config: config, ExpectError: regexp.MustCompile(`Inappropriate value for attribute "repository"`)},
+		{Config: config, ExpectError: regexp.MustCompile(`Inappropriate value for attribute "name"`)},
+		{Config: config, ExpectError: regexp.MustCompile(`Inappropriate value for attribute "query"`)},
+		{Config: config, ExpectError: regexp.MustCompile(`Inappropriate value for attribute "start"`)},
+		{Config: config, ExpectError: regexp.MustCompile(`Inappropriate value for attribute "backfill_limit"`)},
+		{Config: config, ExpectError: regexp.MustCompile(`Inappropriate value for attribute "labels"`)},
+		{Config: config, ExpectError: regexp.MustCompile(`Inappropriate value for attribute "actions"`)},
+	}, nil)
+}
+
+func TestAccScheduledSearchInvalidSchedule(t *testing.T) {
+	accTestCase(t, []resource.TestStep{
+		{Config: scheduledSearchInvalidSchedule, ExpectError: regexp.MustCompile(`schedule must be a cron expression with 5 fields`)},
+		{Config: scheduledSearchInvalidSchedule, ExpectError: regexp.MustCompile(`query_ownership_type must be 'User' or 'Organization'`)},
+	}, nil)
+}
+
+func TestAccScheduledSearchBasic(t *testing.T) {
+	accTestCase(t, []resource.TestStep{
+		{
+			Config: scheduledSearchBasic,
+			Check: resource.ComposeTestCheckFunc(
+				resource.TestCheckResourceAttrSet("humio_scheduled_search.test", "scheduled_search_id"),
+				resource.TestCheckResourceAttr("humio_scheduled_search.test", "repository", "sandbox"),
+				resource.TestCheckResourceAttr("humio_scheduled_search.test", "name", "scheduled-search-test"),
+				resource.TestCheckResourceAttr("humio_scheduled_search.test", "query", "loglevel=ERROR | count()"),
+				resource.TestCheckResourceAttr("humio_scheduled_search.test", "start", "1h"),
+				resource.TestCheckResourceAttr("humio_scheduled_search.test", "end", "now"),
+				resource.TestCheckResourceAttr("humio_scheduled_search.test", "schedule", "0 * * * *"),
+				resource.TestCheckResourceAttr("humio_scheduled_search.test", "time_zone", "UTC"),
+				resource.TestCheckResourceAttr("humio_scheduled_search.test", "backfill_limit", "0"),
+				resource.TestCheckResourceAttr("humio_scheduled_search.test", "enabled", "false"),
+				resource.TestCheckNoResourceAttr("humio_scheduled_search.test", "actions"),
+				resource.TestCheckNoResourceAttr("humio_scheduled_search.test", "labels"),
+			),
+		},
+	}, testAccCheckScheduledSearchDestroy)
+}
+
+func TestAccScheduledSearchBasicToFull(t *testing.T) {
+	accTestCase(t, []resource.TestStep{
+		{
+			Config: scheduledSearchBasic,
+			Check: resource.ComposeTestCheckFunc(
+				resource.TestCheckResourceAttr("humio_scheduled_search.test", "repository", "sandbox"),
+				resource.TestCheckResourceAttr("humio_scheduled_search.test", "name", "scheduled-search-test"),
+				resource.TestCheckResourceAttr("humio_scheduled_search.test", "schedule", "0 * * * *"),
+				resource.TestCheckResourceAttr("humio_scheduled_search.test", "enabled", "false"),
+				resource.TestCheckNoResourceAttr("humio_scheduled_search.test", "actions"),
+				resource.TestCheckNoResourceAttr("humio_scheduled_search.test", "labels"),
+			),
+		},
+		{
+			Config:             scheduledSearchFull,
+			PlanOnly:           true,
+			ExpectNonEmptyPlan: true,
+		},
+		{
+			Config: scheduledSearchFull,
+			Check: resource.ComposeTestCheckFunc(
+				resource.TestCheckResourceAttr("humio_scheduled_search.test", "repository", "sandbox"),
+				resource.TestCheckResourceAttr("humio_scheduled_search.test", "name", "scheduled-search-test"),
+				resource.TestCheckResourceAttr("humio_scheduled_search.test", "description", "hourly error report"),
+				resource.TestCheckResourceAttr("humio_scheduled_search.test", "query", "loglevel=ERROR | count()"),
+				resource.TestCheckResourceAttr("humio_scheduled_search.test", "start", "24h"),
+				resource.TestCheckResourceAttr("humio_scheduled_search.test", "end", "now"),
+				resource.TestCheckResourceAttr("humio_scheduled_search.test", "schedule", "30 6 * * 1"),
+				resource.TestCheckResourceAttr("humio_scheduled_search.test", "time_zone", "UTC+01:00"),
+				resource.TestCheckResourceAttr("humio_scheduled_search.test", "backfill_limit", "3"),
+				resource.TestCheckResourceAttr("humio_scheduled_search.test", "enabled", "true"),
+				resource.TestCheckResourceAttr("humio_scheduled_search.test", "labels.#", "2"),
+				resource.TestCheckResourceAttr("humio_scheduled_search.test", "labels.0", "reports"),
+				resource.TestCheckResourceAttr("humio_scheduled_search.test", "labels.1", "weekly"),
+				resource.TestCheckResourceAttr("humio_scheduled_search.test", "actions.#", "1"),
+				resource.TestCheckResourceAttrSet("humio_scheduled_search.test", "actions.0"),
+				resource.TestCheckResourceAttr("humio_scheduled_search.test", "query_ownership_type", "Organization"),
+			),
+		},
+	}, testAccCheckScheduledSearchDestroy)
+}
+
+func TestAccScheduledSearchImport(t *testing.T) {
+	accTestCase(t, []resource.TestStep{
+		{
+			Config: scheduledSearchBasic,
+		},
+		{
+			ResourceName:      "humio_scheduled_search.test",
+			ImportState:       true,
+			ImportStateId:     "sandbox+scheduled-search-test",
+			ImportStateVerify: true,
+		},
+	}, testAccCheckScheduledSearchDestroy)
+}
+
+func testAccCheckScheduledSearchDestroy(s *terraform.State) error {
+	conn := testAccProviders["humio"].Meta().(*providerMeta).client
+
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "humio_scheduled_search" {
+			continue
+		}
+		parts := parseRepositoryAndID(rs.Primary.ID)
+		resp, err := newScheduledSearches(conn).Get(parts[0], parts[1])
+		if err == nil {
+			return fmt.Errorf("scheduled search still exists: %#+v", resp)
+		}
+	}
+	return nil
+}
+
+const scheduledSearchEmpty = `
+resource "humio_scheduled_search" "test" {}
+`
+
+const scheduledSearchInvalidInputs = `
+resource "humio_scheduled_search" "test" {
+	repository     = ["invalid"]
+	name           = ["invalid"]
+	query          = ["invalid"]
+	start          = ["invalid"]
+	schedule       = "0 * * * *"
+	backfill_limit = "invalid"
+	labels         = "invalid"
+	actions        = "invalid"
+}
+`
+
+const scheduledSearchInvalidSchedule = `
+resource "humio_scheduled_search" "test" {
+	repository           = "sandbox"
+	name                 = "scheduled-search-invalid"
+	query                = "count()"
+	start                = "1h"
+	schedule             = "every hour"
+	query_ownership_type = "organization"
+}
+`
+
+const scheduledSearchBasic = `
+resource "humio_scheduled_search" "test" {
+	repository = "sandbox"
+	name       = "scheduled-search-test"
+	query      = "loglevel=ERROR | count()"
+	start      = "1h"
+	schedule   = "0 * * * *"
+}
+`
+
+const scheduledSearchFull = `
+resource "humio_action" "test" {
+    repository = "sandbox"
+    type       = "EmailAction"
+    name       = "scheduled-search-email-test"
+    email {
+        recipients = ["test@example.org"]
+    }
+}
+
+resource "humio_scheduled_search" "test" {
+	repository           = "sandbox"
+	name                 = "scheduled-search-test"
+	description          = "hourly error report"
+	query                = "loglevel=ERROR | count()"
+	start                = "24h"
+	end                  = "now"
+	schedule             = "30 6 * * 1"
+	time_zone            = "UTC+01:00"
+	backfill_limit       = 3
+	enabled              = true
+	labels               = ["reports", "weekly"]
+	actions              = [humio_action.test.action_id]
+	query_ownership_type = "Organization"
+}
+`
+
+var wantScheduledSearch = ScheduledSearch{
+	ID:                 "",
+	Name:               "weekly error report",
+	Description:        "errors last week",
+	QueryString:        "loglevel=ERROR | count()",
+	QueryStart:         "7d",
+	QueryEnd:           "now",
+	TimeZone:           "UTC",
+	Schedule:           "0 8 * * 1",
+	BackfillLimit:      2,
+	Enabled:            true,
+	Actions:            []string{"action1", "action2"},
+	Labels:             []string{"important", "error"},
+	RunAsUserID:        "",
+	QueryOwnershipType: humio.QueryOwnershipTypeOrganization,
+}
+
+func TestEncodeDecodeScheduledSearchResource(t *testing.T) {
+	res := resourceScheduledSearch()
+	data := res.TestResourceData()
+	resourceDataFromScheduledSearch(&wantScheduledSearch, data)
+	got, err := scheduledSearchFromResourceData(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !cmp.Equal(wantScheduledSearch, got) {
+		t.Error(cmp.Diff(wantScheduledSearch, got))
+	}
+}
@@ -0,0 +1,262 @@
+// Copyright © 2020 Humio Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package humio
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+func resourceViewToken() *schema.Resource {
+	r := resourceToken(TokenTypeView)
+	r.Schema["views"] = &schema.Schema{
+		Type:     schema.TypeSet,
+		Required: true,
+		ForceNew: true,
+		MinItems: 1,
+		Elem:     &schema.Schema{Type: schema.TypeString},
+	}
+	return r
+}
+
+func resourceOrganizationToken() *schema.Resource {
+	return resourceToken(TokenTypeOrganization)
+}
+
+func resourceSystemToken() *schema.Resource {
+	return resourceToken(TokenTypeSystem)
+}
+
+// resourceToken returns the resource shared by the view, organization and system token resources.
+func resourceToken(tokenType string) *schema.Resource {
+	return &schema.Resource{
+		CreateContext: func(ctx context.Context, d *schema.ResourceData, client interface{}) diag.Diagnostics {
+			return resourceTokenCreate(ctx, d, client, tokenType)
+		},
+		ReadContext: func(ctx context.Context, d *schema.ResourceData, client interface{}) diag.Diagnostics {
+			return resourceTokenRead(ctx, d, client, tokenType)
+		},
+		UpdateContext: func(ctx context.Context, d *schema.ResourceData, client interface{}) diag.Diagnostics {
+			return resourceTokenUpdate(ctx, d, client, tokenType)
+		},
+		DeleteContext: resourceTokenDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+		CustomizeDiff: func(_ context.Context, d *schema.ResourceDiff, _ interface{}) error {
+			// Changing rotate_when rotates the token on the next apply, so the token value is not known until then.
+			if d.Id() != "" && d.HasChange("rotate_when") {
+				return d.SetNewComputed("token")
+			}
+			return nil
+		},
+
+		Schema: map[string]*schema.Schema{
+			"token_id": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"name": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"permissions": {
+				Type:     schema.TypeSet,
+				Required: true,
+				MinItems: 1,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+			"expire_at": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				ForceNew:         true,
+				ValidateDiagFunc: validation.ToDiagFunc(validation.IsRFC3339Time),
+				DiffSuppressFunc: suppressEquivalentTimestamps,
+			},
+			"ip_filter_id": {
+				Type:     schema.TypeString,
+				Optional: true,
+				ForceNew: true,
+			},
+			"rotate_when": {
+				Type:     schema.TypeMap,
+				Optional: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+			"token": {
+				Type:      schema.TypeString,
+				Computed:  true,
+				Sensitive: true,
+			},
+		},
+	}
+}
+
+func resourceTokenCreate(ctx context.Context, d *schema.ResourceData, client interface{}, tokenType string) diag.Diagnostics {
+	token, err := tokenFromResourceData(d, tokenType)
+	if err != nil {
+		return diag.Errorf("could not obtain token from resource data: %s", err)
+	}
+
+	tokens := newTokens(client.(*providerMeta).client)
+	value, err := tokens.Add(&token)
+	if err != nil {
+		return diag.Errorf("could not create token: %s", err)
+	}
+	err = d.Set("token", value)
+	if err != nil {
+		return diag.Errorf("error setting token for resource %s: %s", token.Name, err)
+	}
+
+	// The create mutations only return the token value, so look up the token to get its ID.
+	created, err := tokens.GetByName(tokenType, token.Name)
+	if err != nil {
+		return diag.Errorf("could not get token %s after creating it: %s", token.Name, err)
+	}
+	d.SetId(created.ID)
+
+	return resourceTokenRead(ctx, d, client, tokenType)
+}
+
+func resourceTokenRead(_ context.Context, d *schema.ResourceData, client interface{}, tokenType string) diag.Diagnostics {
+	token, err := newTokens(client.(*providerMeta).client).Get(tokenType, d.Id(), d.Get("name").(string))
+	if isNotFound(err) {
+		log.Printf("[WARN] token %s not found, removing from state", d.Id())
+		d.SetId("")
+		return nil
+	}
+	if err != nil {
+		return diag.Errorf("could not get token: %s", err)
+	}
+	return resourceDataFromToken(token, d)
+}
+
+func resourceDataFromToken(t *Token, d *schema.ResourceData) diag.Diagnostics {
+	err := d.Set("token_id", t.ID)
+	if err != nil {
+		return diag.Errorf("error setting token_id for resource %s: %s", d.Id(), err)
+	}
+	err = d.Set("name", t.Name)
+	if err != nil {
+		return diag.Errorf("error setting name for resource %s: %s", d.Id(), err)
+	}
+	err = d.Set("permissions", t.Permissions)
+	if err != nil {
+		return diag.Errorf("error setting permissions for resource %s: %s", d.Id(), err)
+	}
+	var expireAt string
+	if t.ExpireAt > 0 {
+		expireAt = time.UnixMilli(t.ExpireAt).UTC().Format(time.RFC3339)
+	}
+	err = d.Set("expire_at", expireAt)
+	if err != nil {
+		return diag.Errorf("error setting expire_at for resource %s: %s", d.Id(), err)
+	}
+	err = d.Set("ip_filter_id", t.IPFilterID)
+	if err != nil {
+		return diag.Errorf("error setting ip_filter_id for resource %s: %s", d.Id(), err)
+	}
+	if t.Type == TokenTypeView {
+		err = d.Set("views", t.Views)
+		if err != nil {
+			return diag.Errorf("error setting views for resource %s: %s", d.Id(), err)
+		}
+	}
+	return nil
+}
+
+func resourceTokenUpdate(ctx context.Context, d *schema.ResourceData, client interface{}, tokenType string) diag.Diagnostics {
+	token, err := tokenFromResourceData(d, tokenType)
+	if err != nil {
+		return diag.Errorf("could not obtain token from resource data: %s", err)
+	}
+
+	tokens := newTokens(client.(*providerMeta).client)
+	if d.HasChange("permissions") {
+		err = tokens.UpdatePermissions(&token)
+		if err != nil {
+			return diag.Errorf("could not update token permissions: %s", err)
+		}
+	}
+
+	if d.HasChange("rotate_when") {
+		value, err := tokens.Rotate(d.Id())
+		if err != nil {
+			return diag.Errorf("could not rotate token: %s", err)
+		}
+		err = d.Set("token", value)
+		if err != nil {
+			return diag.Errorf("error setting token for resource %s: %s", d.Id(), err)
+		}
+
+		// Look the token up again in case rotating it changed its ID.
+		rotated, err := tokens.GetByName(tokenType, token.Name)
+		if err != nil {
+			return diag.Errorf("could not get token %s after rotating it: %s", token.Name, err)
+		}
+		d.SetId(rotated.ID)
+	}
+
+	return resourceTokenRead(ctx, d, client, tokenType)
+}
+
+func resourceTokenDelete(_ context.Context, d *schema.ResourceData, client interface{}) diag.Diagnostics {
+	err := newTokens(client.(*providerMeta).client).Delete(d.Id())
+	if err != nil {
+		return diag.Errorf("could not delete token: %s", err)
+	}
+	return nil
+}
+
+func tokenFromResourceData(d *schema.ResourceData, tokenType string) (Token, error) {
+	token := Token{
+		ID:          d.Id(),
+		Type:        tokenType,
+		Name:        d.Get("name").(string),
+		IPFilterID:  d.Get("ip_filter_id").(string),
+		Permissions: convertInterfaceListToStringSlice(d.Get("permissions").(*schema.Set).List()),
+	}
+	if expireAt := d.Get("expire_at").(string); expireAt != "" {
+		t, err := time.Parse(time.RFC3339, expireAt)
+		if err != nil {
+			return token, fmt.Errorf("could not parse expire_at: %w", err)
+		}
+		token.ExpireAt = t.UnixMilli()
+	}
+	if tokenType == TokenTypeView {
+		token.Views = convertInterfaceListToStringSlice(d.Get("views").(*schema.Set).List())
+	}
+	return token, nil
+}
+
+// suppressEquivalentTimestamps suppresses diffs between RFC 3339 timestamps that refer to the same instant.
+func suppressEquivalentTimestamps(_, old, new string, _ *schema.ResourceData) bool {
+	oldTime, err := time.Parse(time.RFC3339, old)
+	if err != nil {
+		return false
+	}
+	newTime, err := time.Parse(time.RFC3339, new)
+	if err != nil {
+		return false
+	}
+	return oldTime.Equal(newTime)
+}
@@ -0,0 +1,244 @@
+// Copyright © 2020 Humio Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package humio
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccViewTokenRequiredFields(t *testing.T) {
+	accTestCase(t, []resource.TestStep{
+		{Config: viewTokenEmpty, ExpectError: regexp.MustCompile(`The argument "name" is required, but no definition was found.`)},
+		{Config: viewTokenEmpty, ExpectError: regexp.MustCompile(`The argument "views" is required, but no definition was found.`)},
+		{Config: viewTokenEmpty, ExpectError: regexp.MustCompile(`The argument "permissions" is required, but no definition was found.`)},
+	}, nil)
+}
+
+func TestAccViewTokenInvalidInputs(t *testing.T) {
+	accTestCase(t, []resource.TestStep{
+		{Config: viewTokenInvalidExpireAt, ExpectError: regexp.MustCompile(`expected "expire_at" to be a valid RFC3339 date`)},
+	}, nil)
+}
+
+func TestAccViewTokenBasicToRotated(t *testing.T) {
+	var token string
+	accTestCase(t, []resource.TestStep{
+		{
+			Config: viewTokenBasic,
+			Check: resource.ComposeTestCheckFunc(
+				resource.TestCheckResourceAttrSet("humio_view_token.test", "token_id"),
+				resource.TestCheckResourceAttr("humio_view_token.test", "name", "view-token-test"),
+				resource.TestCheckResourceAttr("humio_view_token.test", "views.#", "1"),
+				resource.TestCheckResourceAttr("humio_view_token.test", "permissions.#", "1"),
+				resource.TestCheckResourceAttr("humio_view_token.test", "expire_at", ""),
+				testAccCaptureAttr("humio_view_token.test", "token", &token),
+			),
+		},
+		{
+			Config: viewTokenRotated,
+			Check: resource.ComposeTestCheckFunc(
+				resource.TestCheckResourceAttr("humio_view_token.test", "permissions.#", "2"),
+				resource.TestCheckResourceAttr("humio_view_token.test", "rotate_when.rotation", "1"),
+				testAccCheckAttrChanged("humio_view_token.test", "token", &token),
+			),
+		},
+		{
+			ResourceName:            "humio_view_token.test",
+			ImportState:             true,
+			ImportStateVerify:       true,
+			ImportStateVerifyIgnore: []string{"token", "rotate_when"},
+		},
+	}, testAccCheckTokenDestroy)
+}
+
+func TestAccOrganizationTokenBasic(t *testing.T) {
+	accTestCase(t, []resource.TestStep{
+		{
+			Config: organizationTokenBasic,
+			Check: resource.ComposeTestCheckFunc(
+				resource.TestCheckResourceAttrSet("humio_organization_token.test", "token_id"),
+				resource.TestCheckResourceAttrSet("humio_organization_token.test", "token"),
+				resource.TestCheckResourceAttr("humio_organization_token.test", "permissions.#", "1"),
+				resource.TestCheckResourceAttr("humio_organization_token.test", "expire_at", "2099-01-01T00:00:00Z"),
+			),
+		},
+		{
+			ResourceName:            "humio_organization_token.test",
+			ImportState:             true,
+			ImportStateVerify:       true,
+			ImportStateVerifyIgnore: []string{"token"},
+		},
+	}, testAccCheckTokenDestroy)
+}
+
+func TestAccSystemTokenBasic(t *testing.T) {
+	accTestCase(t, []resource.TestStep{
+		{
+			Config: systemTokenBasic,
+			Check: resource.ComposeTestCheckFunc(
+				resource.TestCheckResourceAttrSet("humio_system_token.test", "token_id"),
+				resource.TestCheckResourceAttrSet("humio_system_token.test", "token"),
+				resource.TestCheckResourceAttr("humio_system_token.test", "permissions.#", "1"),
+			),
+		},
+	}, testAccCheckTokenDestroy)
+}
+
+func testAccCaptureAttr(name, key string, value *string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[name]
+		if !ok {
+			return fmt.Errorf("resource not found: %s", name)
+		}
+		*value = rs.Primary.Attributes[key]
+		if *value == "" {
+			return fmt.Errorf("%s.%s is empty", name, key)
+		}
+		return nil
+	}
+}
+
+func testAccCheckAttrChanged(name, key string, previous *string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[name]
+		if !ok {
+			return fmt.Errorf("resource not found: %s", name)
+		}
+		if value := rs.Primary.Attributes[key]; value == "" || value == *previous {
+			return fmt.Errorf("expected %s.%s to change", name, key)
+		}
+		return nil
+	}
+}
+
+func testAccCheckTokenDestroy(s *terraform.State) error {
+	conn := testAccProviders["humio"].Meta().(*providerMeta).client
+
+	tokenTypes := map[string]string{
+		"humio_view_token":         TokenTypeView,
+		"humio_organization_token": TokenTypeOrganization,
+		"humio_system_token":       TokenTypeSystem,
+	}
+	for _, rs := range s.RootModule().Resources {
+		tokenType, ok := tokenTypes[rs.Type]
+		if !ok {
+			continue
+		}
+		resp, err := newTokens(conn).Get(tokenType, rs.Primary.ID, rs.Primary.Attributes["name"])
+		if err == nil {
+			return fmt.Errorf("token still exists: %#+v", resp)
+		}
+	}
+	return nil
+}
+
+const viewTokenEmpty = `
+resource "humio_view_token" "test" {}
+`
+
+const viewTokenInvalidExpireAt = `
+resource "humio_view_token" "test" {
+	name        = "view-token-test"
+	views       = ["sandbox"]
+	permissions = ["ReadAccess"]
+	expire_at   = "tomorrow"
+}
+`
+
+const viewTokenBasic = `
+resource "humio_view_token" "test" {
+	name        = "view-token-test"
+	views       = ["sandbox"]
+	permissions = ["ReadAccess"]
+}
+`
+
+const viewTokenRotated = `
+resource "humio_view_token" "test" {
+	name        = "view-token-test"
+	views       = ["sandbox"]
+	permissions = ["ReadAccess", "ChangeTriggers"]
+	rotate_when = {
+		rotation = "1"
+	}
+}
+`
+
+const organizationTokenBasic = `
+resource "humio_organization_token" "test" {
+	name        = "organization-token-test"
+	permissions = ["CreateRepository"]
+	expire_at   = "2099-01-01T00:00:00Z"
+}
+`
+
+const systemTokenBasic = `
+resource "humio_system_token" "test" {
+	name        = "system-token-test"
+	permissions = ["ReadHealthCheck"]
+}
+`
+
+var wantViewToken = Token{
+	ID:          "",
+	Type:        TokenTypeView,
+	Name:        "automation",
+	ExpireAt:    4070908800000,
+	IPFilterID:  "ip-filter-id",
+	Views:       []string{"repo1", "view1"},
+	Permissions: []string{"ChangeTriggers", "ReadAccess"},
+}
+
+func TestEncodeDecodeViewTokenResource(t *testing.T) {
+	res := resourceViewToken()
+	data := res.TestResourceData()
+	resourceDataFromToken(&wantViewToken, data)
+	got, err := tokenFromResourceData(data, TokenTypeView)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Views and permissions are stored as sets, so their order is not preserved.
+	sortStrings := cmpopts.SortSlices(func(a, b string) bool { return a < b })
+	if !cmp.Equal(wantViewToken, got, sortStrings) {
+		t.Error(cmp.Diff(wantViewToken, got, sortStrings))
+	}
+}
+
+func TestSuppressEquivalentTimestamps(t *testing.T) {
+	tests := []struct {
+		old, new string
+		want     bool
+	}{
+		{"2099-01-01T00:00:00Z", "2099-01-01T00:00:00Z", true},
+		{"2099-01-01T00:00:00Z", "2099-01-01T01:00:00+01:00", true},
+		{"2099-01-01T00:00:00Z", "2099-01-02T00:00:00Z", false},
+		{"", "2099-01-01T00:00:00Z", false},
+		{"2099-01-01T00:00:00Z", "", false},
+	}
+	for _, tt := range tests {
+		if got := suppressEquivalentTimestamps("expire_at", tt.old, tt.new, nil); got != tt.want {
+			t.Errorf("suppressEquivalentTimestamps(%q, %q) = %v, want %v", tt.old, tt.new, got, tt.want)
+		}
+	}
+}
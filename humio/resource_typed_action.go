@@ -0,0 +1,301 @@
+// Copyright © 2020 Humio Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package humio
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+
+	humio "github.com/humio/cli/api"
+)
+
+// typedActionResources maps the names of the typed action resources to the action type they manage.
+var typedActionResources = map[string]string{
+	"humio_email_action":              humio.ActionTypeEmail,
+	"humio_opsgenie_action":           humio.ActionTypeOpsGenie,
+	"humio_pagerduty_action":          humio.ActionTypePagerDuty,
+	"humio_repository_action":         humio.ActionTypeHumioRepo,
+	"humio_slack_action":              humio.ActionTypeSlack,
+	"humio_slack_post_message_action": humio.ActionTypeSlackPostMessage,
+	"humio_upload_file_action":        ActionTypeUploadFile,
+	"humio_victorops_action":          humio.ActionTypeVictorOps,
+	"humio_webhook_action":            humio.ActionTypeWebhook,
+}
+
+func resourceEmailAction() *schema.Resource {
+	return resourceTypedAction(humio.ActionTypeEmail)
+}
+
+func resourceOpsGenieAction() *schema.Resource {
+	return resourceTypedAction(humio.ActionTypeOpsGenie)
+}
+
+func resourcePagerDutyAction() *schema.Resource {
+	return resourceTypedAction(humio.ActionTypePagerDuty)
+}
+
+func resourceRepositoryAction() *schema.Resource {
+	return resourceTypedAction(humio.ActionTypeHumioRepo)
+}
+
+func resourceSlackAction() *schema.Resource {
+	return resourceTypedAction(humio.ActionTypeSlack)
+}
+
+func resourceSlackPostMessageAction() *schema.Resource {
+	return resourceTypedAction(humio.ActionTypeSlackPostMessage)
+}
+
+func resourceUploadFileAction() *schema.Resource {
+	return resourceTypedAction(ActionTypeUploadFile)
+}
+
+func resourceVictorOpsAction() *schema.Resource {
+	return resourceTypedAction(humio.ActionTypeVictorOps)
+}
+
+func resourceWebhookAction() *schema.Resource {
+	return resourceTypedAction(humio.ActionTypeWebhook)
+}
+
+// resourceTypedAction returns a resource managing actions of a single type. Its schema is flat: the settings found in
+// the block of the action type in humio_action are top-level attributes, and the secret of the action type can be
+// given through a write-only attribute named after it.
+func resourceTypedAction(actionType string) *schema.Resource {
+	s := map[string]*schema.Schema{
+		"action_id": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"repository": {
+			Type:     schema.TypeString,
+			Required: true,
+			ForceNew: true,
+		},
+		"name": {
+			Type:     schema.TypeString,
+			Required: true,
+		},
+		"verify_on_apply": {
+			Type:     schema.TypeBool,
+			Optional: true,
+			Default:  false,
+		},
+	}
+	for k, v := range typedActionSettingsSchema(actionType) {
+		s[k] = v
+	}
+
+	block := actionBlocks[actionType]
+	if block.secret != "" {
+		s[block.secret].ExactlyOneOf = []string{block.secret, block.secret + "_wo"}
+		s[block.secret+"_wo"] = &schema.Schema{
+			Type:             schema.TypeString,
+			Optional:         true,
+			Sensitive:        true,
+			ExactlyOneOf:     []string{block.secret, block.secret + "_wo"},
+			RequiredWith:     []string{"secret_wo_version"},
+			DiffSuppressFunc: suppressWriteOnlyDiff,
+		}
+	}
+	if actionType == humio.ActionTypeWebhook {
+		s["headers_wo"] = &schema.Schema{
+			Type:             schema.TypeMap,
+			Optional:         true,
+			Sensitive:        true,
+			RequiredWith:     []string{"secret_wo_version"},
+			DiffSuppressFunc: suppressWriteOnlyDiff,
+			Elem: &schema.Schema{
+				Type: schema.TypeString,
+			},
+		}
+	}
+	if block.secret != "" || actionType == humio.ActionTypeWebhook {
+		s["secret_wo_version"] = &schema.Schema{
+			Type:             schema.TypeInt,
+			Optional:         true,
+			ValidateDiagFunc: validation.ToDiagFunc(validation.IntAtLeast(1)),
+		}
+	}
+
+	return &schema.Resource{
+		CreateContext: func(ctx context.Context, d *schema.ResourceData, client interface{}) diag.Diagnostics {
+			return resourceTypedActionCreate(ctx, d, client, actionType)
+		},
+		ReadContext: func(ctx context.Context, d *schema.ResourceData, client interface{}) diag.Diagnostics {
+			return resourceTypedActionRead(ctx, d, client, actionType)
+		},
+		UpdateContext: func(ctx context.Context, d *schema.ResourceData, client interface{}) diag.Diagnostics {
+			return resourceTypedActionUpdate(ctx, d, client, actionType)
+		},
+		DeleteContext: resourceTypedActionDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+
+		Schema: s,
+	}
+}
+
+// typedActionSettingsSchema returns the schema of the settings of the action type, taken from its block in
+// humio_action so the two resources accept the same settings.
+func typedActionSettingsSchema(actionType string) map[string]*schema.Schema {
+	return resourceAction().Schema[actionBlocks[actionType].name].Elem.(*schema.Resource).Schema
+}
+
+func resourceTypedActionCreate(ctx context.Context, d *schema.ResourceData, client interface{}, actionType string) diag.Diagnostics {
+	action := typedActionFromResourceData(d, actionType)
+
+	a, err := client.(*providerMeta).actions().Add(
+		d.Get("repository").(string),
+		&action,
+	)
+	if err != nil {
+		return diag.Errorf("could not create action: %s", err)
+	}
+	d.SetId(fmt.Sprintf("%s+%s", d.Get("repository").(string), a.Name))
+
+	if d.Get("verify_on_apply").(bool) {
+		if diags := verifyAction(client.(*providerMeta).client, d.Get("repository").(string), a); diags.HasError() {
+			return diags
+		}
+	}
+
+	return resourceTypedActionRead(ctx, d, client, actionType)
+}
+
+func resourceTypedActionRead(_ context.Context, d *schema.ResourceData, client interface{}, actionType string) diag.Diagnostics {
+	// If we don't have a repository when importing, we parse it from the ID.
+	if _, ok := d.GetOk("repository"); !ok {
+		parts := parseRepositoryAndID(d.Id())
+		if parts[0] == "" || parts[1] == "" {
+			return diag.Errorf("error importing action. Please make sure the ID is in the form REPOSITORYNAME+ACTIONNAME (i.e. myRepoName+myActionName)")
+		}
+		err := d.Set("repository", parts[0])
+		if err != nil {
+			return diag.Errorf("error setting repository for resource %s: %s", d.Id(), err)
+		}
+		err = d.Set("name", parts[1])
+		if err != nil {
+			return diag.Errorf("error setting name for resource %s: %s", d.Id(), err)
+		}
+	}
+
+	action, err := client.(*providerMeta).actions().Get(
+		d.Get("repository").(string),
+		d.Get("name").(string),
+	)
+	if isNotFound(err) {
+		log.Printf("[WARN] action %s not found, removing from state", d.Id())
+		d.SetId("")
+		return nil
+	}
+	if err != nil {
+		return diag.Errorf("could not get action: %s", err)
+	}
+	if action.Type != actionType {
+		return diag.Errorf("action %s is of type %s, not %s", action.Name, action.Type, actionType)
+	}
+	return resourceDataFromTypedAction(action, d)
+}
+
+func resourceTypedActionUpdate(ctx context.Context, d *schema.ResourceData, client interface{}, actionType string) diag.Diagnostics {
+	action := typedActionFromResourceData(d, actionType)
+
+	a, err := client.(*providerMeta).actions().Update(
+		d.Get("repository").(string),
+		&action,
+	)
+	if err != nil {
+		return diag.Errorf("could not update action: %s", err)
+	}
+	d.SetId(fmt.Sprintf("%s+%s", d.Get("repository").(string), a.Name))
+
+	if d.Get("verify_on_apply").(bool) {
+		if diags := verifyAction(client.(*providerMeta).client, d.Get("repository").(string), a); diags.HasError() {
+			return diags
+		}
+	}
+
+	return resourceTypedActionRead(ctx, d, client, actionType)
+}
+
+func resourceTypedActionDelete(_ context.Context, d *schema.ResourceData, client interface{}) diag.Diagnostics {
+	err := client.(*providerMeta).actions().Delete(
+		d.Get("repository").(string),
+		d.Get("name").(string),
+	)
+	if err != nil {
+		return diag.Errorf("could not delete action: %s", err)
+	}
+	return nil
+}
+
+func resourceDataFromTypedAction(a *Action, d *schema.ResourceData) diag.Diagnostics {
+	err := d.Set("action_id", a.ID)
+	if err != nil {
+		return diag.Errorf("could not set action_id for action: %s", err)
+	}
+	err = d.Set("name", a.Name)
+	if err != nil {
+		return diag.Errorf("could not set name for action: %s", err)
+	}
+
+	// As for humio_action, secrets given through write-only attributes must not end up in state.
+	if version, ok := d.Get("secret_wo_version").(int); ok && version != 0 {
+		a = withoutWriteOnlySecrets(a, typedActionProperties(d, a.Type))
+	}
+
+	for k, v := range actionBlocks[a.Type].fromAction(a)[0] {
+		if err := d.Set(k, v); err != nil {
+			return diag.Errorf("error setting %s for resource %s: %s", k, d.Id(), err)
+		}
+	}
+	return nil
+}
+
+func typedActionFromResourceData(d *schema.ResourceData, actionType string) Action {
+	action := Action{
+		Action: humio.Action{
+			Type: actionType,
+			ID:   d.Get("action_id").(string),
+			Name: d.Get("name").(string),
+		},
+	}
+	actionSettingsFromProperties(&action, typedActionProperties(d, actionType))
+
+	var secretKey string
+	if secret := actionBlocks[actionType].secret; secret != "" {
+		secretKey = secret + "_wo"
+	}
+	writeOnlySecretsFromConfig(d.GetRawConfig(), &action, secretKey, "headers_wo")
+
+	return action
+}
+
+// typedActionProperties returns the top-level settings of a typed action resource in the same form as the block of
+// the action type in humio_action.
+func typedActionProperties(d *schema.ResourceData, actionType string) tfMap {
+	properties := tfMap{}
+	for k := range typedActionSettingsSchema(actionType) {
+		properties[k] = d.Get(k)
+	}
+	return properties
+}
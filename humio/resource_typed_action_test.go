@@ -0,0 +1,353 @@
+// Copyright © 2020 Humio Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package humio
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hashicorp/go-cty/cty"
+	ctyjson "github.com/hashicorp/go-cty/cty/json"
+	"github.com/hashicorp/go-cty/cty/msgpack"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestAccWebhookActionBasicToFull(t *testing.T) {
+	accTestCase(t, []resource.TestStep{
+		{
+			Config: webhookActionBasic,
+			Check: resource.ComposeTestCheckFunc(
+				resource.TestCheckResourceAttrSet("humio_webhook_action.test", "action_id"),
+				resource.TestCheckResourceAttr("humio_webhook_action.test", "repository", "sandbox"),
+				resource.TestCheckResourceAttr("humio_webhook_action.test", "name", "webhook-action-test"),
+				resource.TestCheckResourceAttr("humio_webhook_action.test", "url", "https://127.0.0.1/webhook"),
+				resource.TestCheckResourceAttr("humio_webhook_action.test", "method", "POST"),
+			),
+		},
+		{
+			Config: webhookActionFull,
+			Check: resource.ComposeTestCheckFunc(
+				resource.TestCheckResourceAttr("humio_webhook_action.test", "url", "https://127.0.0.1/webhook"),
+				resource.TestCheckResourceAttr("humio_webhook_action.test", "method", "PUT"),
+				resource.TestCheckResourceAttr("humio_webhook_action.test", "headers.%", "1"),
+				resource.TestCheckResourceAttr("humio_webhook_action.test", "headers.Content-Type", "application/json"),
+				resource.TestCheckNoResourceAttr("humio_webhook_action.test", "headers_wo"),
+				resource.TestCheckResourceAttr("humio_webhook_action.test", "ignore_ssl", "true"),
+				resource.TestCheckResourceAttr("humio_webhook_action.test", "use_proxy", "true"),
+			),
+		},
+		{
+			ResourceName:            "humio_webhook_action.test",
+			ImportState:             true,
+			ImportStateVerify:       true,
+			ImportStateVerifyIgnore: []string{"headers", "secret_wo_version"},
+		},
+	}, testAccCheckTypedActionDestroy)
+}
+
+func TestAccOpsGenieActionWriteOnly(t *testing.T) {
+	accTestCase(t, []resource.TestStep{
+		{
+			Config: opsGenieActionWriteOnly,
+			Check: resource.ComposeTestCheckFunc(
+				resource.TestCheckResourceAttr("humio_opsgenie_action.test", "genie_key", ""),
+				resource.TestCheckNoResourceAttr("humio_opsgenie_action.test", "genie_key_wo"),
+				testAccCheckActionOpsGenieKey("humio_opsgenie_action.test", "secretgeniekey"),
+			),
+		},
+	}, testAccCheckTypedActionDestroy)
+}
+
+func TestAccOpsGenieActionMissingSecret(t *testing.T) {
+	accTestCase(t, []resource.TestStep{
+		{Config: opsGenieActionMissingSecret, ExpectError: regexp.MustCompile(`one of .genie_key,genie_key_wo. must be specified`)},
+	}, nil)
+}
+
+func testAccCheckTypedActionDestroy(s *terraform.State) error {
+	conn := testAccProviders["humio"].Meta().(*providerMeta).client
+
+	for _, rs := range s.RootModule().Resources {
+		if _, ok := typedActionResources[rs.Type]; !ok {
+			continue
+		}
+		resp, err := newActions(conn).Get(rs.Primary.Attributes["repository"], rs.Primary.Attributes["name"])
+		if err == nil {
+			return fmt.Errorf("action still exists: %#+v", resp)
+		}
+		if !isNotFound(err) {
+			return fmt.Errorf("could not validate if actions have been cleaned up: %s", err)
+		}
+	}
+	return nil
+}
+
+const webhookActionBasic = `
+resource "humio_webhook_action" "test" {
+	repository = "sandbox"
+	name       = "webhook-action-test"
+	url        = "https://127.0.0.1/webhook"
+}
+`
+
+const webhookActionFull = `
+resource "humio_webhook_action" "test" {
+	repository = "sandbox"
+	name       = "webhook-action-test"
+	url        = "https://127.0.0.1/webhook"
+	method     = "PUT"
+	headers = {
+		Content-Type = "application/json"
+	}
+	headers_wo = {
+		Authorization = "Bearer secret"
+	}
+	secret_wo_version = 1
+	ignore_ssl        = true
+	use_proxy         = true
+}
+`
+
+const opsGenieActionWriteOnly = `
+resource "humio_opsgenie_action" "test" {
+	repository        = "sandbox"
+	name              = "opsgenie-action-test"
+	genie_key_wo      = "secretgeniekey"
+	secret_wo_version = 1
+}
+`
+
+const opsGenieActionMissingSecret = `
+resource "humio_opsgenie_action" "test" {
+	repository = "sandbox"
+	name       = "opsgenie-action-test"
+}
+`
+
+func TestEncodeDecodeTypedActionResources(t *testing.T) {
+	tests := wantActionsByType()
+
+	for name, actionType := range typedActionResources {
+		want, ok := tests[actionType]
+		if !ok {
+			t.Errorf("no action of type %s to test %s with", actionType, name)
+			continue
+		}
+		t.Run(name, func(t *testing.T) {
+			data := Provider().ResourcesMap[name].TestResourceData()
+			if diags := resourceDataFromTypedAction(&want, data); diags.HasError() {
+				t.Fatal(diags)
+			}
+			got := typedActionFromResourceData(data, actionType)
+			if !cmp.Equal(want, got) {
+				t.Error(cmp.Diff(want, got))
+			}
+		})
+	}
+}
+
+func TestMoveActionState(t *testing.T) {
+	server := ProviderServer().(tfprotov5.ResourceServerWithMoveResourceState)
+	source := `{
+		"id": "sandbox+test-action",
+		"action_id": "abc",
+		"repository": "sandbox",
+		"name": "test-action",
+		"type": "WebhookAction",
+		"secret_wo_version": 2,
+		"webhook": [{
+			"body_template": "{}",
+			"headers": {"Content-Type": "application/json"},
+			"method": "PUT",
+			"url": "https://example.org",
+			"ignore_ssl": true,
+			"use_proxy": false
+		}],
+		"email": []
+	}`
+
+	resp, err := server.MoveResourceState(context.Background(), &tfprotov5.MoveResourceStateRequest{
+		SourceTypeName: "humio_action",
+		SourceState:    &tfprotov5.RawState{JSON: []byte(source)},
+		TargetTypeName: "humio_webhook_action",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.Diagnostics) > 0 {
+		t.Fatalf("unexpected diagnostics: %s", resp.Diagnostics[0].Detail)
+	}
+
+	ty := resourceWebhookAction().CoreConfigSchema().ImpliedType()
+	got, err := msgpack.Unmarshal(resp.TargetState.MsgPack, ty)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]cty.Value{
+		"id":                cty.StringVal("sandbox+test-action"),
+		"action_id":         cty.StringVal("abc"),
+		"repository":        cty.StringVal("sandbox"),
+		"name":              cty.StringVal("test-action"),
+		"secret_wo_version": cty.NumberIntVal(2),
+		"method":            cty.StringVal("PUT"),
+		"url":               cty.StringVal("https://example.org"),
+		"ignore_ssl":        cty.True,
+		"headers_wo":        cty.NullVal(cty.Map(cty.String)),
+	}
+	for k, v := range want {
+		if !got.GetAttr(k).RawEquals(v) {
+			t.Errorf("got %s = %#v, want %#v", k, got.GetAttr(k), v)
+		}
+	}
+	if got := got.GetAttr("headers").Index(cty.StringVal("Content-Type")); !got.RawEquals(cty.StringVal("application/json")) {
+		t.Errorf("got header Content-Type = %#v, want application/json", got)
+	}
+}
+
+// actionStateJSON returns the JSON state of a humio_action managing the action, as Terraform sends it when moving it.
+func actionStateJSON(t *testing.T, action Action, secretWOVersion int) []byte {
+	t.Helper()
+	r := resourceAction()
+	data := r.TestResourceData()
+	data.SetId("sandbox+" + action.Name)
+	if diags := resourceDataFromAction(&action, data); diags.HasError() {
+		t.Fatal(diags)
+	}
+	if err := data.Set("repository", "sandbox"); err != nil {
+		t.Fatal(err)
+	}
+	if secretWOVersion > 0 {
+		if err := data.Set("secret_wo_version", secretWOVersion); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	ty := r.CoreConfigSchema().ImpliedType()
+	val, err := data.State().AttrsAsObjectValue(ty)
+	if err != nil {
+		t.Fatal(err)
+	}
+	state, err := ctyjson.Marshal(val, ty)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return state
+}
+
+// moveTestActionState moves the state to the typed action resource and returns the moved state.
+func moveTestActionState(t *testing.T, source []byte, targetTypeName string) *schema.ResourceData {
+	t.Helper()
+	target := Provider().ResourcesMap[targetTypeName]
+	ty := target.CoreConfigSchema().ImpliedType()
+	moved, err := moveActionState(source, typedActionResources[targetTypeName], ty)
+	if err != nil {
+		t.Fatal(err)
+	}
+	val, err := ctyjson.Unmarshal(moved, ty)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return target.Data(terraform.NewInstanceStateShimmedFromValue(val, target.SchemaVersion))
+}
+
+func TestMoveActionStateEveryType(t *testing.T) {
+	webhook := Action{Action: wantWebhookAction, WebhookCACertificate: newTestCertificate(t, "CA", nil).certPEM}
+	actions := map[string]Action{
+		"humio_email_action":              wantEmailActionAttachCSV,
+		"humio_opsgenie_action":           {Action: wantOpsGenieAction},
+		"humio_pagerduty_action":          {Action: wantPagerDutyAction},
+		"humio_repository_action":         {Action: wantHumioRepoAction},
+		"humio_slack_action":              {Action: wantSlackAction},
+		"humio_slack_post_message_action": {Action: wantSlackPostMessageAction},
+		"humio_upload_file_action":        wantUploadFileAction,
+		"humio_victorops_action":          {Action: wantVictorOpsAction},
+		"humio_webhook_action":            webhook,
+	}
+	if len(actions) != len(typedActionResources) {
+		t.Fatalf("got %d actions for %d typed action resources", len(actions), len(typedActionResources))
+	}
+
+	for targetTypeName, action := range actions {
+		t.Run(targetTypeName, func(t *testing.T) {
+			action.ID = "abc"
+			moved := moveTestActionState(t, actionStateJSON(t, action, 0), targetTypeName)
+
+			if got := moved.Id(); got != "sandbox+test-action" {
+				t.Errorf("got ID %q, want %q", got, "sandbox+test-action")
+			}
+			if got := moved.Get("repository"); got != "sandbox" {
+				t.Errorf("got repository %q, want sandbox", got)
+			}
+			got := typedActionFromResourceData(moved, action.Type)
+			if !cmp.Equal(action, got) {
+				t.Error(cmp.Diff(action, got))
+			}
+		})
+	}
+}
+
+func TestMoveActionStateWriteOnlySecret(t *testing.T) {
+	// With a write-only secret, the secret is not in the state of humio_action, only its version.
+	action := Action{Action: wantOpsGenieAction}
+	action.OpsGenieAction.GenieKey = ""
+	moved := moveTestActionState(t, actionStateJSON(t, action, 3), "humio_opsgenie_action")
+
+	if got := moved.Get("secret_wo_version"); got != 3 {
+		t.Errorf("got secret_wo_version %v, want 3", got)
+	}
+	if got := moved.Get("genie_key"); got != "" {
+		t.Errorf("got genie_key %q, want it empty", got)
+	}
+	if got := moved.Get("genie_key_wo"); got != "" {
+		t.Errorf("got genie_key_wo %q, want it unset", got)
+	}
+	if got := moved.Get("api_url"); got != wantOpsGenieAction.OpsGenieAction.ApiUrl {
+		t.Errorf("got api_url %q, want %q", got, wantOpsGenieAction.OpsGenieAction.ApiUrl)
+	}
+}
+
+func TestMoveActionStateWrongType(t *testing.T) {
+	server := ProviderServer().(tfprotov5.ResourceServerWithMoveResourceState)
+
+	resp, err := server.MoveResourceState(context.Background(), &tfprotov5.MoveResourceStateRequest{
+		SourceTypeName: "humio_action",
+		SourceState:    &tfprotov5.RawState{JSON: []byte(`{"id": "sandbox+test-action", "type": "EmailAction"}`)},
+		TargetTypeName: "humio_webhook_action",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.Diagnostics) != 1 || resp.TargetState != nil {
+		t.Errorf("expected moving an email action to humio_webhook_action to fail, got %#v", resp)
+	}
+
+	resp, err = server.MoveResourceState(context.Background(), &tfprotov5.MoveResourceStateRequest{
+		SourceTypeName: "humio_alert",
+		SourceState:    &tfprotov5.RawState{JSON: []byte(`{"id": "sandbox+test-alert"}`)},
+		TargetTypeName: "humio_webhook_action",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.Diagnostics) != 1 || resp.TargetState != nil {
+		t.Errorf("expected moving humio_alert to fail, got %#v", resp)
+	}
+}
@@ -0,0 +1,200 @@
+// Copyright © 2020 Humio Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package humio
+
+import (
+	"context"
+	"log"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+
+	humio "github.com/humio/cli/api"
+)
+
+func resourceUser() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceUserCreate,
+		ReadContext:   resourceUserRead,
+		UpdateContext: resourceUserUpdate,
+		DeleteContext: resourceUserDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"user_id": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"username": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"full_name": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+			},
+			"email": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+			},
+			"company": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+			},
+			"country_code": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+			},
+			"picture": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+			},
+			"is_root": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+		},
+	}
+}
+
+func resourceUserCreate(ctx context.Context, d *schema.ResourceData, client interface{}) diag.Diagnostics {
+	user, err := userFromResourceData(d)
+	if err != nil {
+		return diag.Errorf("could not obtain user from resource data: %s", err)
+	}
+
+	_, err = client.(*providerMeta).client.Users().Add(user.Username, userChangeSetFromUser(user))
+	if err != nil {
+		return diag.Errorf("could not create user: %s", err)
+	}
+	d.SetId(user.Username)
+
+	return resourceUserRead(ctx, d, client)
+}
+
+func resourceUserRead(_ context.Context, d *schema.ResourceData, client interface{}) diag.Diagnostics {
+	user, err := client.(*providerMeta).client.Users().Get(d.Id())
+	if err != nil {
+		err = userNotFoundOr(client.(*providerMeta).client, d.Id(), err)
+	}
+	if isNotFound(err) {
+		log.Printf("[WARN] user %s not found, removing from state", d.Id())
+		d.SetId("")
+		return nil
+	}
+	if err != nil {
+		return diag.Errorf("could not get user: %s", err)
+	}
+	return resourceDataFromUser(&user, d)
+}
+
+func resourceDataFromUser(u *humio.User, d *schema.ResourceData) diag.Diagnostics {
+	err := d.Set("user_id", u.ID)
+	if err != nil {
+		return diag.Errorf("error setting user_id for resource %s: %s", d.Id(), err)
+	}
+	err = d.Set("username", u.Username)
+	if err != nil {
+		return diag.Errorf("error setting username for resource %s: %s", d.Id(), err)
+	}
+	err = d.Set("full_name", u.FullName)
+	if err != nil {
+		return diag.Errorf("error setting full_name for resource %s: %s", d.Id(), err)
+	}
+	err = d.Set("email", u.Email)
+	if err != nil {
+		return diag.Errorf("error setting email for resource %s: %s", d.Id(), err)
+	}
+	err = d.Set("company", u.Company)
+	if err != nil {
+		return diag.Errorf("error setting company for resource %s: %s", d.Id(), err)
+	}
+	err = d.Set("country_code", u.CountryCode)
+	if err != nil {
+		return diag.Errorf("error setting country_code for resource %s: %s", d.Id(), err)
+	}
+	err = d.Set("picture", u.Picture)
+	if err != nil {
+		return diag.Errorf("error setting picture for resource %s: %s", d.Id(), err)
+	}
+	err = d.Set("is_root", u.IsRoot)
+	if err != nil {
+		return diag.Errorf("error setting is_root for resource %s: %s", d.Id(), err)
+	}
+	return nil
+}
+
+func resourceUserUpdate(ctx context.Context, d *schema.ResourceData, client interface{}) diag.Diagnostics {
+	user, err := userFromResourceData(d)
+	if err != nil {
+		return diag.Errorf("could not obtain user from resource data: %s", err)
+	}
+
+	_, err = client.(*providerMeta).client.Users().Update(user.Username, userChangeSetFromUser(user))
+	if err != nil {
+		return diag.Errorf("could not update user: %s", err)
+	}
+
+	return resourceUserRead(ctx, d, client)
+}
+
+func resourceUserDelete(_ context.Context, d *schema.ResourceData, client interface{}) diag.Diagnostics {
+	_, err := client.(*providerMeta).client.Users().Remove(d.Get("username").(string))
+	if err != nil {
+		return diag.Errorf("could not delete user: %s", err)
+	}
+	return nil
+}
+
+func userFromResourceData(d *schema.ResourceData) (humio.User, error) {
+	return humio.User{
+		ID:          d.Get("user_id").(string),
+		Username:    d.Get("username").(string),
+		FullName:    d.Get("full_name").(string),
+		Email:       d.Get("email").(string),
+		Company:     d.Get("company").(string),
+		CountryCode: d.Get("country_code").(string),
+		Picture:     d.Get("picture").(string),
+		IsRoot:      d.Get("is_root").(bool),
+	}, nil
+}
+
+// userChangeSetFromUser leaves empty fields unset, so values that are only known to LogScale are kept.
+func userChangeSetFromUser(u humio.User) humio.UserChangeSet {
+	optString := func(v string) *string {
+		if v == "" {
+			return nil
+		}
+		return &v
+	}
+
+	return humio.UserChangeSet{
+		IsRoot:      &u.IsRoot,
+		FullName:    optString(u.FullName),
+		Company:     optString(u.Company),
+		CountryCode: optString(u.CountryCode),
+		Picture:     optString(u.Picture),
+		Email:       optString(u.Email),
+	}
+}
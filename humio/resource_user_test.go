@@ -0,0 +1,123 @@
+// Copyright © 2020 Humio Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package humio
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	humio "github.com/humio/cli/api"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccUserRequiredFields(t *testing.T) {
+	accTestCase(t, []resource.TestStep{
+		{Config: userEmpty, ExpectError: regexp.MustCompile(`The argument "username" is required, but no definition was found.`)},
+	}, nil)
+}
+
+func TestAccUserBasicToFull(t *testing.T) {
+	accTestCase(t, []resource.TestStep{
+		{
+			Config: userBasic,
+			Check: resource.ComposeTestCheckFunc(
+				resource.TestCheckResourceAttrSet("humio_user.test", "user_id"),
+				resource.TestCheckResourceAttr("humio_user.test", "username", "user-test@example.org"),
+				resource.TestCheckResourceAttr("humio_user.test", "is_root", "false"),
+			),
+		},
+		{
+			Config: userFull,
+			Check: resource.ComposeTestCheckFunc(
+				resource.TestCheckResourceAttr("humio_user.test", "username", "user-test@example.org"),
+				resource.TestCheckResourceAttr("humio_user.test", "full_name", "Test User"),
+				resource.TestCheckResourceAttr("humio_user.test", "email", "user-test@example.org"),
+				resource.TestCheckResourceAttr("humio_user.test", "company", "Example"),
+				resource.TestCheckResourceAttr("humio_user.test", "country_code", "DK"),
+				resource.TestCheckResourceAttr("humio_user.test", "is_root", "false"),
+			),
+		},
+		{
+			ResourceName:      "humio_user.test",
+			ImportState:       true,
+			ImportStateId:     "user-test@example.org",
+			ImportStateVerify: true,
+		},
+	}, testAccCheckUserDestroy)
+}
+
+func testAccCheckUserDestroy(s *terraform.State) error {
+	conn := testAccProviders["humio"].Meta().(*providerMeta).client
+
+	for _, rs := range s.RootModule().Resources {
+		if rs.Type != "humio_user" {
+			continue
+		}
+		resp, err := conn.Users().Get(rs.Primary.ID)
+		if err == nil {
+			return fmt.Errorf("user still exists: %#+v", resp)
+		}
+	}
+	return nil
+}
+
+const userEmpty = `
+resource "humio_user" "test" {}
+`
+
+const userBasic = `
+resource "humio_user" "test" {
+	username = "user-test@example.org"
+}
+`
+
+const userFull = `
+resource "humio_user" "test" {
+	username     = "user-test@example.org"
+	full_name    = "Test User"
+	email        = "user-test@example.org"
+	company      = "Example"
+	country_code = "DK"
+}
+`
+
+var wantUser = humio.User{
+	ID:          "",
+	Username:    "jane@example.org",
+	FullName:    "Jane Doe",
+	Email:       "jane@example.org",
+	Company:     "Example",
+	CountryCode: "DK",
+	Picture:     "https://example.org/jane.png",
+	IsRoot:      true,
+}
+
+func TestEncodeDecodeUserResource(t *testing.T) {
+	res := resourceUser()
+	data := res.TestResourceData()
+	resourceDataFromUser(&wantUser, data)
+	got, err := userFromResourceData(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !cmp.Equal(wantUser, got) {
+		t.Error(cmp.Diff(wantUser, got))
+	}
+}
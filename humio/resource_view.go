@@ -17,6 +17,8 @@ package humio
 import (
 	"context"
 	"fmt"
+	"log"
+
 	graphql "github.com/cli/shurcooL-graphql"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
@@ -72,14 +74,7 @@ func resourceViewCreate(ctx context.Context, d *schema.ResourceData, client inte
 		return diag.Errorf("count not obtain view from resource data: %s", err)
 	}
 
-	var viewConnectionInput []humio.ViewConnectionInput
-	for _, repository := range view.Connections {
-		viewConnectionInput = append(viewConnectionInput, humio.ViewConnectionInput{
-			RepositoryName: graphql.String(repository.RepoName),
-			Filter:         graphql.String(repository.Filter),
-		})
-	}
-	err = client.(*humio.Client).Views().Create(view.Name, view.Description, viewConnectionInput)
+	err = client.(*providerMeta).client.Views().Create(view.Name, view.Description, viewConnectionInputs(view.Connections))
 	if err != nil {
 		return diag.Errorf("error creatuing view name for resource %s: %s", view.Name, err)
 	}
@@ -98,7 +93,15 @@ func resourceViewRead(_ context.Context, d *schema.ResourceData, client interfac
 
 	name := d.Get("name").(string)
 
-	view, err := client.(*humio.Client).Views().Get(name)
+	view, err := client.(*providerMeta).client.Views().Get(name)
+	if err != nil {
+		err = searchDomainNotFoundOr(client.(*providerMeta).client, name, err)
+	}
+	if isNotFound(err) {
+		log.Printf("[WARN] view %s not found, removing from state", d.Id())
+		d.SetId("")
+		return nil
+	}
 	if err != nil {
 		return diag.Errorf("Unable to find view %s: %s", name, err)
 	}
@@ -120,8 +123,15 @@ func resourceDataFromView(a *humio.View, d *schema.ResourceData) diag.Diagnostic
 		return diag.Errorf("must specify at least one connection for view %s", a.Name)
 	}
 
+	// LogScale does not guarantee the order of connections, so keep the order we already know about to avoid
+	// spurious diffs.
+	var known []humio.ViewConnection
+	if _, ok := d.GetOk("repository"); ok {
+		known, _ = viewConnectionsFromResourceData(d)
+	}
+
 	var repositories []interface{}
-	for _, connection := range a.Connections {
+	for _, connection := range orderViewConnections(a.Connections, known) {
 		repo := make(map[string]interface{})
 		repo["name"] = connection.RepoName
 		repo["filter"] = connection.Filter
@@ -142,15 +152,12 @@ func resourceViewUpdate(ctx context.Context, d *schema.ResourceData, client inte
 		return diag.Errorf("could not obtain view from resource data: %s", err)
 	}
 
-	err = client.(*humio.Client).Views().UpdateDescription(view.Name, view.Description)
+	err = client.(*providerMeta).client.Views().UpdateDescription(view.Name, view.Description)
 	if err != nil {
 		return diag.Errorf("error updating view description %s: %s", d.Id(), err)
 	}
 
-	err = client.(*humio.Client).Views().UpdateConnections(view.Name, []humio.ViewConnectionInput{{
-		RepositoryName: graphql.String(view.Connections[0].RepoName),
-		Filter:         graphql.String(view.Connections[0].Filter),
-	}})
+	err = client.(*providerMeta).client.Views().UpdateConnections(view.Name, viewConnectionInputs(view.Connections))
 	if err != nil {
 		return diag.Errorf("error updating view connections: %s", err)
 	}
@@ -164,7 +171,7 @@ func resourceViewDelete(_ context.Context, d *schema.ResourceData, client interf
 		return diag.Errorf("could not obtain view from resource data: %s", err)
 	}
 
-	err = client.(*humio.Client).Views().Delete(view.Name, "Resource destruction from Terraform provider.")
+	err = client.(*providerMeta).client.Views().Delete(view.Name, "Resource destruction from Terraform provider.")
 	if err != nil {
 		return diag.Errorf("error deleting view %s: %s", d.Id(), err)
 	}
@@ -211,3 +218,58 @@ func viewConnectionsFromResourceData(d *schema.ResourceData) ([]humio.ViewConnec
 
 	return connections, nil
 }
+
+func viewConnectionInputs(connections []humio.ViewConnection) []humio.ViewConnectionInput {
+	var inputs []humio.ViewConnectionInput
+	for _, connection := range connections {
+		inputs = append(inputs, humio.ViewConnectionInput{
+			RepositoryName: graphql.String(connection.RepoName),
+			Filter:         graphql.String(connection.Filter),
+		})
+	}
+	return inputs
+}
+
+// orderViewConnections returns the connections ordered like the known connections. Connections that are unchanged
+// keep their position, connections where only the filter changed take the position of the old filter, and any other
+// connections are appended in the order returned by LogScale.
+func orderViewConnections(connections, known []humio.ViewConnection) []humio.ViewConnection {
+	used := make([]bool, len(connections))
+	take := func(match func(humio.ViewConnection) bool) (humio.ViewConnection, bool) {
+		for i, connection := range connections {
+			if !used[i] && match(connection) {
+				used[i] = true
+				return connection, true
+			}
+		}
+		return humio.ViewConnection{}, false
+	}
+
+	slots := make([]*humio.ViewConnection, len(known))
+	for i, k := range known {
+		if connection, ok := take(func(c humio.ViewConnection) bool { return c == k }); ok {
+			slots[i] = &connection
+		}
+	}
+	for i, k := range known {
+		if slots[i] != nil {
+			continue
+		}
+		if connection, ok := take(func(c humio.ViewConnection) bool { return c.RepoName == k.RepoName }); ok {
+			slots[i] = &connection
+		}
+	}
+
+	var ordered []humio.ViewConnection
+	for _, slot := range slots {
+		if slot != nil {
+			ordered = append(ordered, *slot)
+		}
+	}
+	for i, connection := range connections {
+		if !used[i] {
+			ordered = append(ordered, connection)
+		}
+	}
+	return ordered
+}
@@ -114,8 +114,60 @@ func TestAccViewFull(t *testing.T) {
 	}, testAccCheckViewDestroy)
 }
 
+func TestAccViewUpdateConnections(t *testing.T) {
+	accTestCase(t, []resource.TestStep{
+		{
+			Config: viewThreeConnections,
+			Check: resource.ComposeTestCheckFunc(
+				resource.TestCheckResourceAttr("humio_view.test", "repository.#", "3"),
+				resource.TestCheckResourceAttr("humio_view.test", "repository.0.name", "view-repository-a"),
+				resource.TestCheckResourceAttr("humio_view.test", "repository.0.filter", "*"),
+				resource.TestCheckResourceAttr("humio_view.test", "repository.1.name", "view-repository-b"),
+				resource.TestCheckResourceAttr("humio_view.test", "repository.1.filter", "*"),
+				resource.TestCheckResourceAttr("humio_view.test", "repository.2.name", "view-repository-c"),
+				resource.TestCheckResourceAttr("humio_view.test", "repository.2.filter", "*"),
+			),
+		},
+		{
+			// Removing a connection keeps the others.
+			Config: viewTwoConnections,
+			Check: resource.ComposeTestCheckFunc(
+				resource.TestCheckResourceAttr("humio_view.test", "repository.#", "2"),
+				resource.TestCheckResourceAttr("humio_view.test", "repository.0.name", "view-repository-a"),
+				resource.TestCheckResourceAttr("humio_view.test", "repository.1.name", "view-repository-c"),
+			),
+		},
+		{
+			// Changing a filter keeps the position of the connection.
+			Config: viewTwoConnectionsRefiltered,
+			Check: resource.ComposeTestCheckFunc(
+				resource.TestCheckResourceAttr("humio_view.test", "repository.#", "2"),
+				resource.TestCheckResourceAttr("humio_view.test", "repository.0.name", "view-repository-a"),
+				resource.TestCheckResourceAttr("humio_view.test", "repository.0.filter", "loglevel=ERROR"),
+				resource.TestCheckResourceAttr("humio_view.test", "repository.1.name", "view-repository-c"),
+				resource.TestCheckResourceAttr("humio_view.test", "repository.1.filter", "*"),
+			),
+		},
+		{
+			// Adding a connection in front of the others.
+			Config: viewThreeConnectionsReordered,
+			Check: resource.ComposeTestCheckFunc(
+				resource.TestCheckResourceAttr("humio_view.test", "repository.#", "3"),
+				resource.TestCheckResourceAttr("humio_view.test", "repository.0.name", "view-repository-b"),
+				resource.TestCheckResourceAttr("humio_view.test", "repository.1.name", "view-repository-a"),
+				resource.TestCheckResourceAttr("humio_view.test", "repository.1.filter", "loglevel=ERROR"),
+				resource.TestCheckResourceAttr("humio_view.test", "repository.2.name", "view-repository-c"),
+			),
+		},
+		{
+			Config:   viewThreeConnectionsReordered,
+			PlanOnly: true,
+		},
+	}, testAccCheckViewDestroy)
+}
+
 func testAccCheckViewDestroy(s *terraform.State) error {
-	conn := testAccProviders["humio"].Meta().(*humio.Client)
+	conn := testAccProviders["humio"].Meta().(*providerMeta).client
 
 	for _, rs := range s.RootModule().Resources {
 		if rs.Type != "humio_view" {
@@ -173,6 +225,128 @@ resource "humio_view" "test" {
 }
 `
 
+const viewThreeConnections = `
+resource "humio_repository" "a" {
+	name = "view-repository-a"
+}
+
+resource "humio_repository" "b" {
+	name = "view-repository-b"
+}
+
+resource "humio_repository" "c" {
+	name = "view-repository-c"
+}
+
+resource "humio_view" "test" {
+	name = "multi-repository-view"
+
+	repository {
+		name   = humio_repository.a.name
+		filter = "*"
+	}
+
+	repository {
+		name   = humio_repository.b.name
+		filter = "*"
+	}
+
+	repository {
+		name   = humio_repository.c.name
+		filter = "*"
+	}
+}
+`
+
+const viewTwoConnections = `
+resource "humio_repository" "a" {
+	name = "view-repository-a"
+}
+
+resource "humio_repository" "b" {
+	name = "view-repository-b"
+}
+
+resource "humio_repository" "c" {
+	name = "view-repository-c"
+}
+
+resource "humio_view" "test" {
+	name = "multi-repository-view"
+
+	repository {
+		name   = humio_repository.a.name
+		filter = "*"
+	}
+
+	repository {
+		name   = humio_repository.c.name
+		filter = "*"
+	}
+}
+`
+
+const viewTwoConnectionsRefiltered = `
+resource "humio_repository" "a" {
+	name = "view-repository-a"
+}
+
+resource "humio_repository" "b" {
+	name = "view-repository-b"
+}
+
+resource "humio_repository" "c" {
+	name = "view-repository-c"
+}
+
+resource "humio_view" "test" {
+	name = "multi-repository-view"
+
+	repository {
+		name   = humio_repository.a.name
+		filter = "loglevel=ERROR"
+	}
+
+	repository {
+		name   = humio_repository.c.name
+		filter = "*"
+	}
+}
+`
+
+const viewThreeConnectionsReordered = `
+resource "humio_repository" "a" {
+	name = "view-repository-a"
+}
+
+resource "humio_repository" "b" {
+	name = "view-repository-b"
+}
+
+resource "humio_repository" "c" {
+	name = "view-repository-c"
+}
+
+resource "humio_view" "test" {
+	name = "multi-repository-view"
+
+	repository {
+		name   = humio_repository.b.name
+		filter = "*"
+	}
+
+	repository {
+		name   = humio_repository.a.name
+		filter = "loglevel=ERROR"
+	}
+
+	repository {
+		name   = humio_repository.c.name
+		filter = "*"
+	}
+}
+`
+
 var wantView = humio.View{
 	Name:        "simple-view",
 	Description: "a description",
@@ -194,3 +368,33 @@ func TestEncodeDecodeViewResource(t *testing.T) {
 		t.Error(cmp.Diff(wantView, got))
 	}
 }
+
+func TestOrderViewConnections(t *testing.T) {
+	a := humio.ViewConnection{RepoName: "a", Filter: "*"}
+	b := humio.ViewConnection{RepoName: "b", Filter: "*"}
+	c := humio.ViewConnection{RepoName: "c", Filter: "*"}
+	aFiltered := humio.ViewConnection{RepoName: "a", Filter: "loglevel=ERROR"}
+
+	tests := []struct {
+		name        string
+		connections []humio.ViewConnection
+		known       []humio.ViewConnection
+		want        []humio.ViewConnection
+	}{
+		{name: "no known connections", connections: []humio.ViewConnection{c, a}, known: nil, want: []humio.ViewConnection{c, a}},
+		{name: "same connections", connections: []humio.ViewConnection{c, b, a}, known: []humio.ViewConnection{a, b, c}, want: []humio.ViewConnection{a, b, c}},
+		{name: "connection removed", connections: []humio.ViewConnection{c, a}, known: []humio.ViewConnection{a, b, c}, want: []humio.ViewConnection{a, c}},
+		{name: "connection added", connections: []humio.ViewConnection{b, c, a}, known: []humio.ViewConnection{a, c}, want: []humio.ViewConnection{a, c, b}},
+		{name: "filter changed", connections: []humio.ViewConnection{c, aFiltered}, known: []humio.ViewConnection{a, c}, want: []humio.ViewConnection{aFiltered, c}},
+		{name: "same repository twice", connections: []humio.ViewConnection{aFiltered, a}, known: []humio.ViewConnection{a, aFiltered}, want: []humio.ViewConnection{a, aFiltered}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := orderViewConnections(tt.connections, tt.known)
+			if !cmp.Equal(tt.want, got) {
+				t.Error(cmp.Diff(tt.want, got))
+			}
+		})
+	}
+}
@@ -0,0 +1,350 @@
+// Copyright © 2020 Humio Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package humio
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"io"
+	"math/rand"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+// retryConfig configures how requests to LogScale are retried.
+type retryConfig struct {
+	maxRetries        int
+	minBackoff        time.Duration
+	maxBackoff        time.Duration
+	respectRetryAfter bool
+}
+
+// retryTransport retries requests that failed with a connection error or a response indicating LogScale is
+// temporarily unavailable. Requests that only read, and requests that LogScale did not process, are retried. Other
+// mutations are not, as they might have been applied although the response was lost.
+type retryTransport struct {
+	base   http.RoundTripper
+	config retryConfig
+	// logCtx is the context retries are logged with, as github.com/humio/cli does not pass on the context of the
+	// operation requests are made for.
+	logCtx context.Context
+}
+
+// newRetryTransport returns a transport retrying the requests sent through the base transport.
+//
+// github.com/humio/cli only accepts an *http.Transport, so the retrying round tripper is registered as the handler of
+// http and https on a transport that is otherwise unused.
+func newRetryTransport(logCtx context.Context, base http.RoundTripper, config retryConfig) *http.Transport {
+	rt := &retryTransport{
+		base:   base,
+		config: config,
+		logCtx: logCtx,
+	}
+	transport := &http.Transport{
+		// A non-nil TLSNextProto keeps the transport from registering its own handler of https for HTTP/2.
+		TLSNextProto: map[string]func(string, *tls.Conn) http.RoundTripper{},
+	}
+	transport.RegisterProtocol("http", rt)
+	transport.RegisterProtocol("https", rt)
+	return transport
+}
+
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	idempotent := isIdempotentRequest(req)
+	rewindable := req.Body == nil || req.Body == http.NoBody || req.GetBody != nil
+
+	for attempt := 0; ; attempt++ {
+		r := req
+		if attempt > 0 && req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			r = req.Clone(ctx)
+			r.Body = body
+		}
+
+		resp, err := t.base.RoundTrip(r)
+		if attempt >= t.config.maxRetries || !rewindable || !shouldRetry(idempotent, resp, err) {
+			return resp, err
+		}
+
+		wait := t.backoff(attempt, resp)
+		if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < wait {
+			return resp, err
+		}
+
+		fields := map[string]interface{}{
+			"method":  req.Method,
+			"url":     req.URL.Redacted(),
+			"attempt": attempt + 1,
+			"wait":    wait.String(),
+		}
+		if err != nil {
+			fields["error"] = err.Error()
+		} else {
+			fields["status"] = resp.StatusCode
+			// Read the body, so the connection can be reused.
+			_, _ = io.Copy(io.Discard, resp.Body)
+			_ = resp.Body.Close()
+		}
+		tflog.Warn(t.logCtx, "Request to LogScale failed, retrying", fields)
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// backoff returns how long to wait before the given retry. The backoff doubles with every attempt, with jitter so
+// concurrent requests do not retry at the same time. A Retry-After header in the response takes precedence.
+func (t *retryTransport) backoff(attempt int, resp *http.Response) time.Duration {
+	if t.config.respectRetryAfter && resp != nil {
+		if wait, ok := parseRetryAfter(resp.Header.Get("Retry-After"), time.Now()); ok {
+			return wait
+		}
+	}
+
+	backoff := t.config.minBackoff
+	for i := 0; i < attempt && backoff < t.config.maxBackoff; i++ {
+		backoff *= 2
+	}
+	if backoff > t.config.maxBackoff {
+		backoff = t.config.maxBackoff
+	}
+	if backoff <= 0 {
+		return 0
+	}
+	return backoff/2 + time.Duration(rand.Int63n(int64(backoff/2)+1))
+}
+
+// parseRetryAfter parses the value of a Retry-After header, which is either a number of seconds or a date.
+func parseRetryAfter(value string, now time.Time) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+	date, err := http.ParseTime(value)
+	if err != nil {
+		return 0, false
+	}
+	if wait := date.Sub(now); wait > 0 {
+		return wait, true
+	}
+	return 0, true
+}
+
+// shouldRetry returns whether a request should be retried given the outcome of the last attempt. Idempotent requests
+// are retried on connection errors and responses saying LogScale is temporarily unavailable. Other requests are only
+// retried when LogScale cannot have processed them: when rate limited, or when no connection could be made.
+func shouldRetry(idempotent bool, resp *http.Response, err error) bool {
+	if err != nil {
+		if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+			return false
+		}
+		var opErr *net.OpError
+		return idempotent || (errors.As(err, &opErr) && opErr.Op == "dial")
+	}
+
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests:
+		return true
+	case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return idempotent
+	}
+	return false
+}
+
+// isIdempotentRequest returns whether sending the request more than once has the same effect as sending it once.
+// GraphQL requests are always POSTs, so these are idempotent when they are queries rather than mutations.
+func isIdempotentRequest(req *http.Request) bool {
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	case http.MethodPost:
+		if !strings.HasSuffix(req.URL.Path, "/graphql") || req.GetBody == nil {
+			return false
+		}
+		body, err := req.GetBody()
+		if err != nil {
+			return false
+		}
+		defer body.Close()
+
+		var request struct {
+			Query string `json:"query"`
+		}
+		if err := json.NewDecoder(body).Decode(&request); err != nil {
+			return false
+		}
+		query := strings.TrimSpace(request.Query)
+		return strings.HasPrefix(query, "{") || strings.HasPrefix(query, "query")
+	}
+	return false
+}
+
+// limitConfig configures how many requests are sent to LogScale at a time, and how often.
+type limitConfig struct {
+	// maxConcurrentRequests is the number of requests that may be in flight at once, or 0 for no limit.
+	maxConcurrentRequests int
+	// requestsPerSecond is the rate requests are sent at, or 0 for no limit.
+	requestsPerSecond float64
+}
+
+// limitTransport limits the number of concurrent requests and the rate requests are sent at. Every resource of a
+// provider shares its client, so the limits apply across all resources.
+type limitTransport struct {
+	base http.RoundTripper
+	// slots holds a value for every request in flight, if the number of concurrent requests is limited.
+	slots chan struct{}
+	// interval is the time between requests, if the rate is limited.
+	interval time.Duration
+
+	mu sync.Mutex
+	// next is the earliest time the next request may be sent.
+	next time.Time
+}
+
+// newLimitTransport returns a transport limiting the requests sent through the base transport, or the base transport
+// if nothing is limited.
+func newLimitTransport(base http.RoundTripper, config limitConfig) http.RoundTripper {
+	if config.maxConcurrentRequests <= 0 && config.requestsPerSecond <= 0 {
+		return base
+	}
+	t := &limitTransport{base: base}
+	if config.maxConcurrentRequests > 0 {
+		t.slots = make(chan struct{}, config.maxConcurrentRequests)
+	}
+	if config.requestsPerSecond > 0 {
+		t.interval = time.Duration(float64(time.Second) / config.requestsPerSecond)
+	}
+	return t
+}
+
+func (t *limitTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	if t.slots != nil {
+		select {
+		case t.slots <- struct{}{}:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	var once sync.Once
+	release := func() {
+		if t.slots != nil {
+			once.Do(func() { <-t.slots })
+		}
+	}
+
+	if err := t.waitForTurn(ctx); err != nil {
+		release()
+		return nil, err
+	}
+
+	resp, err := t.base.RoundTrip(req)
+	if err != nil || t.slots == nil || !readsResponseBody(req) {
+		release()
+		return resp, err
+	}
+	// The request is in flight until its response has been read. In case the body is never closed, the slot is
+	// released when the request ends or times out at the latest.
+	stop := context.AfterFunc(ctx, release)
+	timer := time.AfterFunc(limitedResponseTimeout, release)
+	resp.Body = &releasingBody{ReadCloser: resp.Body, release: func() {
+		stop()
+		timer.Stop()
+		release()
+	}}
+	return resp, nil
+}
+
+// limitedResponseTimeout is how long a response may take to be read before its slot is released anyway. It matches
+// the timeout of requests in github.com/humio/cli, which does not always pass a context ending with the request.
+var limitedResponseTimeout = 30 * time.Second
+
+// readsResponseBody returns whether github.com/humio/cli reads and closes the body of the response to the request.
+// It does so for GraphQL requests, but not for every other request, e.g. health checks and file uploads and downloads,
+// so those only hold a slot until the response headers have arrived.
+func readsResponseBody(req *http.Request) bool {
+	return strings.HasSuffix(req.URL.Path, "/graphql")
+}
+
+// waitForTurn waits until the rate limit allows sending another request.
+func (t *limitTransport) waitForTurn(ctx context.Context) error {
+	if t.interval == 0 {
+		return nil
+	}
+
+	t.mu.Lock()
+	now := time.Now()
+	turn := t.next
+	if turn.Before(now) {
+		turn = now
+	}
+	t.next = turn.Add(t.interval)
+	t.mu.Unlock()
+
+	wait := time.Until(turn)
+	if wait <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// releasingBody calls release when the response body has been read or is closed.
+type releasingBody struct {
+	io.ReadCloser
+	release func()
+}
+
+func (b *releasingBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	if err == io.EOF {
+		b.release()
+	}
+	return n, err
+}
+
+func (b *releasingBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.release()
+	return err
+}
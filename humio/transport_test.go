@@ -0,0 +1,518 @@
+// Copyright © 2020 Humio Ltd.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package humio
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflogtest"
+
+	humio "github.com/humio/cli/api"
+)
+
+var testRetryConfig = retryConfig{
+	maxRetries:        3,
+	minBackoff:        time.Millisecond,
+	maxBackoff:        10 * time.Millisecond,
+	respectRetryAfter: true,
+}
+
+// newRetryTestClient returns a client sending requests to the server through a retry transport, logging to the
+// returned buffer.
+func newRetryTestClient(t *testing.T, server *httptest.Server, config retryConfig) (*humio.Client, *bytes.Buffer) {
+	t.Helper()
+	address, err := url.Parse(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	clientConfig := humio.Config{
+		Address:  address,
+		Token:    "token",
+		Insecure: true,
+	}
+	var logs bytes.Buffer
+	logCtx := tflogtest.RootLogger(context.Background(), &logs)
+	transport := newRetryTransport(logCtx, humio.NewHttpTransport(clientConfig), config)
+	return humio.NewClientWithTransport(clientConfig, transport), &logs
+}
+
+// failingHandler responds with the given status to the first failures requests, and with the response to the others.
+func failingHandler(requests *atomic.Int32, failures int32, status int, response string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if requests.Add(1) <= failures {
+			w.WriteHeader(status)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = io.WriteString(w, response)
+	}
+}
+
+type testViewerQuery struct {
+	Viewer struct {
+		Username string `graphql:"username"`
+	} `graphql:"viewer"`
+}
+
+type testMutation struct {
+	RemoveRole struct {
+		Typename string `graphql:"__typename"`
+	} `graphql:"removeRole(roleId: $roleId)"`
+}
+
+func TestRetryTransportRetriesQueries(t *testing.T) {
+	for name, newServer := range map[string]func(http.Handler) *httptest.Server{
+		"http":  httptest.NewServer,
+		"https": httptest.NewTLSServer,
+	} {
+		t.Run(name, func(t *testing.T) {
+			var requests atomic.Int32
+			var bodies []string
+			handler := failingHandler(&requests, 2, http.StatusBadGateway, `{"data":{"viewer":{"username":"admin"}}}`)
+			server := newServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, _ := io.ReadAll(r.Body)
+				bodies = append(bodies, string(body))
+				handler(w, r)
+			}))
+			defer server.Close()
+
+			client, logs := newRetryTestClient(t, server, testRetryConfig)
+			var query testViewerQuery
+			if err := client.Query(&query, nil); err != nil {
+				t.Fatal(err)
+			}
+			if query.Viewer.Username != "admin" {
+				t.Errorf("username = %q, want %q", query.Viewer.Username, "admin")
+			}
+			if got := requests.Load(); got != 3 {
+				t.Errorf("got %d requests, want 3", got)
+			}
+			for i, body := range bodies {
+				if body != bodies[0] || body == "" {
+					t.Errorf("body of request %d = %q, want %q", i+1, body, bodies[0])
+				}
+			}
+
+			entries, err := tflogtest.MultilineJSONDecode(logs)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(entries) != 2 {
+				t.Fatalf("got %d log entries, want 2: %v", len(entries), entries)
+			}
+			for i, entry := range entries {
+				if entry["@message"] != "Request to LogScale failed, retrying" || entry["status"] != float64(http.StatusBadGateway) || entry["attempt"] != float64(i+1) {
+					t.Errorf("unexpected log entry %v", entry)
+				}
+			}
+		})
+	}
+}
+
+func TestRetryTransportMutations(t *testing.T) {
+	tests := []struct {
+		status       int
+		wantRequests int32
+	}{
+		{status: http.StatusTooManyRequests, wantRequests: 2},
+		{status: http.StatusBadGateway, wantRequests: 1},
+		{status: http.StatusServiceUnavailable, wantRequests: 1},
+		{status: http.StatusGatewayTimeout, wantRequests: 1},
+		{status: http.StatusInternalServerError, wantRequests: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(http.StatusText(tt.status), func(t *testing.T) {
+			var requests atomic.Int32
+			server := httptest.NewServer(failingHandler(&requests, 1, tt.status, `{"data":{"removeRole":{"__typename":"BooleanResultType"}}}`))
+			defer server.Close()
+
+			client, _ := newRetryTestClient(t, server, testRetryConfig)
+			err := client.Mutate(&testMutation{}, map[string]interface{}{"roleId": "id"})
+			if tt.wantRequests == 1 && err == nil {
+				t.Error("expected the mutation to fail")
+			}
+			if tt.wantRequests > 1 && err != nil {
+				t.Error(err)
+			}
+			if got := requests.Load(); got != tt.wantRequests {
+				t.Errorf("got %d requests, want %d", got, tt.wantRequests)
+			}
+		})
+	}
+}
+
+func TestRetryTransportMaxRetries(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(failingHandler(&requests, 100, http.StatusServiceUnavailable, ""))
+	defer server.Close()
+
+	config := testRetryConfig
+	config.maxRetries = 2
+	client, _ := newRetryTestClient(t, server, config)
+	if err := client.Query(&testViewerQuery{}, nil); err == nil {
+		t.Error("expected the query to fail")
+	}
+	if got := requests.Load(); got != 3 {
+		t.Errorf("got %d requests, want 3", got)
+	}
+}
+
+func TestRetryTransportConnectionReset(t *testing.T) {
+	tests := []struct {
+		name         string
+		request      func(*humio.Client) error
+		wantRequests int32
+	}{
+		{
+			name:         "query",
+			request:      func(c *humio.Client) error { return c.Query(&testViewerQuery{}, nil) },
+			wantRequests: 2,
+		},
+		{
+			name:         "mutation",
+			request:      func(c *humio.Client) error { return c.Mutate(&testMutation{}, map[string]interface{}{"roleId": "id"}) },
+			wantRequests: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var requests atomic.Int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if requests.Add(1) == 1 {
+					conn, _, err := w.(http.Hijacker).Hijack()
+					if err != nil {
+						t.Error(err)
+						return
+					}
+					_ = conn.Close()
+					return
+				}
+				_, _ = io.WriteString(w, `{"data":{"viewer":{"username":"admin"}}}`)
+			}))
+			defer server.Close()
+
+			client, _ := newRetryTestClient(t, server, testRetryConfig)
+			err := tt.request(client)
+			if tt.wantRequests == 1 && err == nil {
+				t.Error("expected the request to fail")
+			}
+			if tt.wantRequests > 1 && err != nil {
+				t.Error(err)
+			}
+			if got := requests.Load(); got != tt.wantRequests {
+				t.Errorf("got %d requests, want %d", got, tt.wantRequests)
+			}
+		})
+	}
+}
+
+func TestRetryTransportRetryAfter(t *testing.T) {
+	for _, respectRetryAfter := range []bool{true, false} {
+		t.Run(fmt.Sprintf("respect_retry_after=%t", respectRetryAfter), func(t *testing.T) {
+			var requests atomic.Int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if requests.Add(1) == 1 {
+					w.Header().Set("Retry-After", "1")
+					w.WriteHeader(http.StatusTooManyRequests)
+					return
+				}
+				_, _ = io.WriteString(w, `{"data":{"viewer":{"username":"admin"}}}`)
+			}))
+			defer server.Close()
+
+			config := testRetryConfig
+			config.respectRetryAfter = respectRetryAfter
+			client, _ := newRetryTestClient(t, server, config)
+			start := time.Now()
+			if err := client.Query(&testViewerQuery{}, nil); err != nil {
+				t.Fatal(err)
+			}
+			if elapsed := time.Since(start); (elapsed >= time.Second) != respectRetryAfter {
+				t.Errorf("request took %s with respect_retry_after %t", elapsed, respectRetryAfter)
+			}
+		})
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		value  string
+		want   time.Duration
+		wantOK bool
+	}{
+		{value: "", wantOK: false},
+		{value: "120", want: 2 * time.Minute, wantOK: true},
+		{value: "0", want: 0, wantOK: true},
+		{value: "-1", wantOK: false},
+		{value: "Mon, 01 Jan 2024 12:00:30 GMT", want: 30 * time.Second, wantOK: true},
+		{value: "Mon, 01 Jan 2024 11:00:00 GMT", want: 0, wantOK: true},
+		{value: "soon", wantOK: false},
+	}
+
+	for _, tt := range tests {
+		got, ok := parseRetryAfter(tt.value, now)
+		if got != tt.want || ok != tt.wantOK {
+			t.Errorf("parseRetryAfter(%q) = %s, %t, want %s, %t", tt.value, got, ok, tt.want, tt.wantOK)
+		}
+	}
+}
+
+func TestRetryTransportBackoff(t *testing.T) {
+	transport := &retryTransport{config: retryConfig{minBackoff: time.Second, maxBackoff: 10 * time.Second}}
+	tests := []struct {
+		attempt int
+		max     time.Duration
+	}{
+		{attempt: 0, max: time.Second},
+		{attempt: 1, max: 2 * time.Second},
+		{attempt: 2, max: 4 * time.Second},
+		{attempt: 3, max: 8 * time.Second},
+		{attempt: 4, max: 10 * time.Second},
+		{attempt: 100, max: 10 * time.Second},
+	}
+
+	for _, tt := range tests {
+		for i := 0; i < 100; i++ {
+			if got := transport.backoff(tt.attempt, nil); got < tt.max/2 || got > tt.max {
+				t.Fatalf("backoff(%d) = %s, want between %s and %s", tt.attempt, got, tt.max/2, tt.max)
+			}
+		}
+	}
+}
+
+func TestShouldRetry(t *testing.T) {
+	dialErr := &net.OpError{Op: "dial", Net: "tcp", Err: syscall.ECONNREFUSED}
+	readErr := &net.OpError{Op: "read", Net: "tcp", Err: syscall.ECONNRESET}
+	tests := []struct {
+		name       string
+		idempotent bool
+		status     int
+		err        error
+		want       bool
+	}{
+		{name: "query ok", idempotent: true, status: http.StatusOK, want: false},
+		{name: "query bad request", idempotent: true, status: http.StatusBadRequest, want: false},
+		{name: "query rate limited", idempotent: true, status: http.StatusTooManyRequests, want: true},
+		{name: "query bad gateway", idempotent: true, status: http.StatusBadGateway, want: true},
+		{name: "query connection refused", idempotent: true, err: dialErr, want: true},
+		{name: "query connection reset", idempotent: true, err: readErr, want: true},
+		{name: "query canceled", idempotent: true, err: context.Canceled, want: false},
+		{name: "mutation rate limited", status: http.StatusTooManyRequests, want: true},
+		{name: "mutation bad gateway", status: http.StatusBadGateway, want: false},
+		{name: "mutation connection refused", err: dialErr, want: true},
+		{name: "mutation connection reset", err: readErr, want: false},
+		{name: "mutation wrapped connection refused", err: fmt.Errorf("post: %w", dialErr), want: true},
+		{name: "mutation other error", err: errors.New("boom"), want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var resp *http.Response
+			if tt.err == nil {
+				resp = &http.Response{StatusCode: tt.status}
+			}
+			if got := shouldRetry(tt.idempotent, resp, tt.err); got != tt.want {
+				t.Errorf("shouldRetry() = %t, want %t", got, tt.want)
+			}
+		})
+	}
+}
+
+// newLimitTestClient returns a client sending requests to the server through a limit transport.
+func newLimitTestClient(t *testing.T, server *httptest.Server, config limitConfig) *humio.Client {
+	t.Helper()
+	address, err := url.Parse(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	clientConfig := humio.Config{Address: address, Token: "token"}
+	transport := newRetryTransport(context.Background(), newLimitTransport(humio.NewHttpTransport(clientConfig), config), retryConfig{})
+	return humio.NewClientWithTransport(clientConfig, transport)
+}
+
+// queryConcurrently sends the number of queries through the client at once, failing the test if any fail.
+func queryConcurrently(t *testing.T, client *humio.Client, queries int) {
+	t.Helper()
+	var wg sync.WaitGroup
+	for i := 0; i < queries; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := client.Query(&testViewerQuery{}, nil); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func TestLimitTransportConcurrentRequests(t *testing.T) {
+	var inFlight, maxInFlight, requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		n := inFlight.Add(1)
+		defer inFlight.Add(-1)
+		for {
+			max := maxInFlight.Load()
+			if n <= max || maxInFlight.CompareAndSwap(max, n) {
+				break
+			}
+		}
+		time.Sleep(20 * time.Millisecond)
+		_, _ = io.WriteString(w, `{"data":{"viewer":{"username":"admin"}}}`)
+	}))
+	defer server.Close()
+
+	client := newLimitTestClient(t, server, limitConfig{maxConcurrentRequests: 3})
+	queryConcurrently(t, client, 20)
+	if got := requests.Load(); got != 20 {
+		t.Errorf("got %d requests, want 20", got)
+	}
+	if got := maxInFlight.Load(); got > 3 {
+		t.Errorf("got %d concurrent requests, want at most 3", got)
+	}
+}
+
+func TestLimitTransportRequestsPerSecond(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(failingHandler(&requests, 0, http.StatusOK, `{"data":{"viewer":{"username":"admin"}}}`))
+	defer server.Close()
+
+	client := newLimitTestClient(t, server, limitConfig{requestsPerSecond: 20})
+	start := time.Now()
+	queryConcurrently(t, client, 6)
+	// The first request is sent at once and the following five 50ms apart.
+	if elapsed := time.Since(start); elapsed < 250*time.Millisecond {
+		t.Errorf("6 requests at 20 requests per second took %s, want at least 250ms", elapsed)
+	}
+	if got := requests.Load(); got != 6 {
+		t.Errorf("got %d requests, want 6", got)
+	}
+}
+
+func TestLimitTransportUnclosedBody(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.WriteString(w, "ok")
+	}))
+	defer server.Close()
+
+	transport := newLimitTransport(http.DefaultTransport, limitConfig{maxConcurrentRequests: 1})
+	ctx, cancel := context.WithCancel(context.Background())
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, server.URL+"/graphql", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The body of the first response is never closed, but the request ends with its context.
+	if _, err := transport.RoundTrip(req); err != nil {
+		t.Fatal(err)
+	}
+	cancel()
+
+	ctx, cancel = context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	req, err = http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := transport.RoundTrip(req)
+	if err != nil {
+		t.Fatalf("second request was not sent: %s", err)
+	}
+	_ = resp.Body.Close()
+}
+
+func TestLimitTransportUnreadResponses(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.WriteString(w, "ok")
+	}))
+	defer server.Close()
+
+	// github.com/humio/cli sends these requests without a context ending with the request, and does not always
+	// close their response bodies.
+	client := newLimitTestClient(t, server, limitConfig{maxConcurrentRequests: 2})
+	done := make(chan error)
+	go func() {
+		for i := 0; i < 5; i++ {
+			if _, err := client.HTTPRequest(http.MethodGet, "api/v1/health", nil); err != nil {
+				done <- err
+				return
+			}
+		}
+		done <- nil
+	}()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatal(err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("requests whose responses were not read are still holding their slots")
+	}
+}
+
+func TestLimitTransportUnclosedBodyTimeout(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.WriteString(w, "ok")
+	}))
+	defer server.Close()
+
+	defer func(timeout time.Duration) { limitedResponseTimeout = timeout }(limitedResponseTimeout)
+	limitedResponseTimeout = 50 * time.Millisecond
+
+	transport := newLimitTransport(http.DefaultTransport, limitConfig{maxConcurrentRequests: 1})
+	// Neither is the body of the first response closed nor does its context end.
+	req, err := http.NewRequest(http.MethodPost, server.URL+"/graphql", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := transport.RoundTrip(req); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	req, err = http.NewRequestWithContext(ctx, http.MethodPost, server.URL+"/graphql", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := transport.RoundTrip(req)
+	if err != nil {
+		t.Fatalf("second request was not sent: %s", err)
+	}
+	_ = resp.Body.Close()
+}
+
+func TestNewLimitTransportUnlimited(t *testing.T) {
+	if got := newLimitTransport(http.DefaultTransport, limitConfig{}); got != http.DefaultTransport {
+		t.Errorf("newLimitTransport() = %T, want the base transport", got)
+	}
+}
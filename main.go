@@ -34,7 +34,7 @@ func main() {
 	flag.Parse()
 
 	opts := &plugin.ServeOpts{
-		ProviderFunc: humio.Provider,
+		GRPCProviderFunc: humio.ProviderServer,
 	}
 
 	if debugMode {